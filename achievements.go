@@ -0,0 +1,50 @@
+package main
+
+const (
+	// achievementBannerFrames is how many frames a newly unlocked achievement is announced for
+	achievementBannerFrames = 180
+)
+
+// achievement represents a feat the player is recognized for once, kept in the profile
+type achievement string
+
+const (
+	// achievementNemesis is earned by destroying or surviving the nemesis asteroid
+	achievementNemesis achievement = "nemesis"
+)
+
+// achievementNames are the names of each achievement shown to the player
+var achievementNames = map[achievement]string{
+	achievementNemesis: "NEMESIS VANQUISHED",
+}
+
+// hasAchievement returns whether the player has earned an achievement
+func (g *Game) hasAchievement(a achievement) bool {
+	for _, earned := range g.profile.Achievements {
+		if earned == string(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// unlockAchievement records an achievement in the profile and announces it, unless it has already been earned.  The
+// profile is saved with the rest of the run's progress when the run ends
+func (g *Game) unlockAchievement(a achievement) {
+	if g.hasAchievement(a) {
+		return
+	}
+	g.profile.Achievements = append(g.profile.Achievements, string(a))
+	g.unlockedAchievement = a
+	g.achievementFrame = g.frameCount
+}
+
+// drawAchievementBanner announces a newly unlocked achievement for a while after it is earned
+func (g *Game) drawAchievementBanner(r Renderer) {
+	if g.unlockedAchievement == "" || g.frameCount-g.achievementFrame > achievementBannerFrames {
+		return
+	}
+	theme := g.theme()
+	banner := "ACHIEVEMENT UNLOCKED: " + achievementNames[g.unlockedAchievement]
+	r.DrawText(banner, g.fonts().Normal, (screenWidth-len(banner)*g.ui(fontSize)/2)/2, g.ui(fontSize)*6, theme.Accent())
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/audio"
+	"github.com/hajimehoshi/ebiten/audio/wav"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// announcerDirName is the directory under the profile directory holding a folder of announcer clips for each language
+	announcerDirName = "announcer"
+	// defaultAnnouncerLanguage is the language used when there are no clips for the player's locale
+	defaultAnnouncerLanguage = "en"
+)
+
+// announcement represents the lines the announcer calls out
+type announcement int
+
+const (
+	// announceBoost is called out when a star boosts the ship
+	announceBoost announcement = iota
+	// announceNewRecord is called out when a run passes the best distance
+	announceNewRecord
+	// announceWarning is called out shortly before a chaos event
+	announceWarning
+)
+
+// announcementFileNames are the names of the clip files for each line, in each language's folder
+var announcementFileNames = map[announcement]string{
+	announceBoost:     "boost.wav",
+	announceNewRecord: "record.wav",
+	announceWarning:   "warning.wav",
+}
+
+// announcerLanguage returns the two letter language of the player's locale, read from the environment
+func announcerLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" || locale == "C" || locale == "POSIX" {
+			continue
+		}
+		fields := strings.FieldsFunc(locale, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+		if len(fields) > 0 {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return defaultAnnouncerLanguage
+}
+
+// loadAnnouncer reads the announcer clips for a language from the profile directory, falling back to the default language.
+// The announcer is optional, so missing clips leave it silent
+func (a *audioManager) loadAnnouncer(language string) error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, announcerDirName)
+
+	languageDir := filepath.Join(dir, language)
+	if _, err := os.Stat(languageDir); errors.Is(err, fs.ErrNotExist) {
+		languageDir = filepath.Join(dir, defaultAnnouncerLanguage)
+	}
+
+	a.announcements = map[announcement][]byte{}
+	for line, name := range announcementFileNames {
+		samples, err := loadWAV(a.context, filepath.Join(languageDir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't load announcer clip %s: %w", name, err)
+		}
+		a.announcements[line] = samples
+	}
+	return nil
+}
+
+// loadWAV reads a WAV file, resampled to the audio context's sample rate
+func loadWAV(context *audio.Context, path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stream, err := wav.Decode(context, file)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(stream)
+}
+
+// setAnnouncer turns the announcer on or off and sets its volume from 0 to 1, on top of the overall volume
+func (a *audioManager) setAnnouncer(isEnabled bool, volume float64) {
+	if a == nil {
+		return
+	}
+	a.isAnnouncerEnabled = isEnabled
+	a.announcerVolume = volume
+}
+
+// announce calls out a line, if the announcer is enabled and has a clip for it
+func (a *audioManager) announce(line announcement) {
+	if a == nil || a.isSoundMuted || !a.isAnnouncerEnabled || a.announcements[line] == nil {
+		return
+	}
+
+	player, err := audio.NewPlayerFromBytes(a.context, a.announcements[line])
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	player.SetVolume(a.volume * a.announcerVolume)
+	if err := player.Play(); err != nil {
+		log.Println(err)
+	}
+}
+
+// updateAnnouncer calls out passing the best distance, once a run, and an upcoming chaos event, once an event
+func (g *Game) updateAnnouncer() {
+	if !g.isRecordAnnounced && g.profile.BestDistance > 0 && g.distanceTravelled > g.profile.BestDistance {
+		g.audio.announce(announceNewRecord)
+		g.isRecordAnnounced = true
+	}
+
+	if g.isChaosActive() {
+		g.isChaosAnnounced = false
+	} else if !g.isChaosAnnounced && g.chaosSpawnThreshold-g.distanceTravelled <= g.telegraphDistance(chaosWarningMeters) {
+		g.audio.announce(announceWarning)
+		g.isChaosAnnounced = true
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"image"
+	"image/color"
+	_ "image/png"
+	"log"
+	"path"
+)
+
+const (
+	// imageBackground is the key of the scrolling space background
+	imageBackground = "background"
+	// imageShip is the key of the player's ship
+	imageShip = "ship"
+	// imageFloor is the key of the ground tiles along the top and bottom of the screen
+	imageFloor = "floor"
+	// imageTopSpire is the key of the spires hanging from the top of the screen
+	imageTopSpire = "topSpire"
+	// imageBottomSpire is the key of the spires rising from the bottom of the screen
+	imageBottomSpire = "bottomSpire"
+	// imageAsteroid1 is the key of the first big asteroid
+	imageAsteroid1 = "asteroid1"
+	// imageAsteroid2 is the key of the second big asteroid, also used for the core of asteroid rings
+	imageAsteroid2 = "asteroid2"
+	// imageAsteroid3 is the key of the third big asteroid
+	imageAsteroid3 = "asteroid3"
+	// imageAsteroid4 is the key of the fourth big asteroid
+	imageAsteroid4 = "asteroid4"
+	// imageSmallAsteroid is the key of the small asteroids orbiting ring cores
+	imageSmallAsteroid = "smallAsteroid"
+	// imageAsteroidExplosion is the key of an exploding asteroid
+	imageAsteroidExplosion = "asteroidExplosion"
+	// imageStar is the key of the star pickup
+	imageStar = "star"
+	// imageFreezePickup is the key of the freeze pickup
+	imageFreezePickup = "freezePickup"
+	// imageGrowPickup is the key of the grow hazard, once its disguise drops
+	imageGrowPickup = "growPickup"
+	// imageWater is the key of the water tiles along the bottom of the screen in the ocean biome
+	imageWater = "water"
+	// imageShield is the key of the shield around the ship
+	imageShield = "shield"
+	// imageBeam is the key of the single white pixel stretched into beams, sparks and flames
+	imageBeam = "beam"
+	// imageLaser is the key of the laser projectile
+	imageLaser = "laser"
+	// imageBoss is the key of the giant asteroid boss
+	imageBoss = "boss"
+	// imageBossBolt is the key of the bolts fired by the boss
+	imageBossBolt = "bossBolt"
+)
+
+// assetFiles holds every image used by the game, embedded so the game is a single binary that doesn't need the assets folder next to it
+//
+//go:embed assets/*.png
+var assetFiles embed.FS
+
+// Assets holds every image and font the game draws with, loaded once when the game starts and looked up by key
+type Assets struct {
+	// images are the loaded images, by key
+	images map[string]*ebiten.Image
+	// fonts are the loaded font sets, by key and UI scale
+	fonts map[fontKey]*FontSet
+}
+
+// fontKey identifies a loaded font set
+type fontKey struct {
+	// name is the key of the font
+	name string
+	// scale is the UI scale the font set is sized for, as a percentage
+	scale int
+}
+
+// loadAssets loads every image and font
+func loadAssets() (*Assets, error) {
+	a := &Assets{images: map[string]*ebiten.Image{}, fonts: map[fontKey]*FontSet{}}
+	if err := a.loadImages(); err != nil {
+		return nil, err
+	}
+	if err := a.loadFonts(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// loadImages decodes the embedded images and draws the images derived from them
+func (a *Assets) loadImages() error {
+	files := map[string]string{
+		imageBackground:        "background.png",
+		imageShip:              "spaceship.png",
+		imageFloor:             "groundDirt.png",
+		imageTopSpire:          "rock-top.png",
+		imageBottomSpire:       "rock-bottom.png",
+		imageAsteroid1:         "meteorBrown_big1.png",
+		imageAsteroid2:         "meteorBrown_big2.png",
+		imageAsteroid3:         "meteorBrown_big3.png",
+		imageAsteroid4:         "meteorBrown_big4.png",
+		imageAsteroidExplosion: "meteorExplosion.png",
+		imageStar:              "starGold.png",
+		imageShield:            "shield.png",
+	}
+	for key, name := range files {
+		img, err := newImageFromAsset(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		a.images[key] = img
+	}
+
+	// Small asteroids are a scaled down copy of a big one
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(0.4, 0.4)
+	if err := a.deriveImage(imageSmallAsteroid, imageAsteroid1, 0.4, op); err != nil {
+		return err
+	}
+
+	// Freeze pickups are an ice blue copy of the star
+	op = &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(0.3, 0.8, 1, 1)
+	if err := a.deriveImage(imageFreezePickup, imageStar, 1, op); err != nil {
+		return err
+	}
+
+	// Grow hazards are a sickly red copy of the star, disguised as a real star until the ship gets close
+	op = &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(1, 0.3, 0.35, 1)
+	if err := a.deriveImage(imageGrowPickup, imageStar, 1, op); err != nil {
+		return err
+	}
+
+	// Water is a see-through blue copy of the ground
+	op = &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(0.2, 0.5, 1, 0.75)
+	if err := a.deriveImage(imageWater, imageFloor, 1, op); err != nil {
+		return err
+	}
+
+	if err := a.loadLedgeImages(); err != nil {
+		return err
+	}
+	if err := a.loadStageImages(); err != nil {
+		return err
+	}
+	if err := a.loadSeasonImages(); err != nil {
+		return err
+	}
+
+	if err := a.loadUFOImage(); err != nil {
+		return err
+	}
+	if err := a.loadGateImages(); err != nil {
+		return err
+	}
+	if err := a.loadCurrentImages(); err != nil {
+		return err
+	}
+	if err := a.loadCoinImage(); err != nil {
+		return err
+	}
+
+	// The boss is a giant, darkened copy of a big asteroid
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(bossScale, bossScale)
+	op.ColorM.Scale(0.7, 0.5, 0.6, 1)
+	if err := a.deriveImage(imageBoss, imageAsteroid3, bossScale, op); err != nil {
+		return err
+	}
+	if err := a.fillImage(imageBossBolt, 10, 10, color.RGBA{0xcc, 0x33, 0xff, 0xff}); err != nil {
+		return err
+	}
+
+	if err := a.fillImage(imageBeam, 1, 1, color.White); err != nil {
+		return err
+	}
+	return a.fillImage(imageLaser, 20, 4, color.RGBA{0xff, 0x44, 0x22, 0xff})
+}
+
+// deriveImage creates an image by drawing another, already loaded image with a scale and draw options
+func (a *Assets) deriveImage(key, source string, scale float64, op *ebiten.DrawImageOptions) error {
+	width, height := a.images[source].Size()
+	img, err := ebiten.NewImage(int(float64(width)*scale), int(float64(height)*scale), ebiten.FilterDefault)
+	if err != nil {
+		return err
+	}
+	img.DrawImage(a.images[source], op)
+	a.images[key] = img
+	return nil
+}
+
+// fillImage creates an image of a single color
+func (a *Assets) fillImage(key string, width, height int, clr color.Color) error {
+	img, err := ebiten.NewImage(width, height, ebiten.FilterDefault)
+	if err != nil {
+		return err
+	}
+	img.Fill(clr)
+	a.images[key] = img
+	return nil
+}
+
+// loadFonts creates the font set of every font the themes use, at every UI scale
+func (a *Assets) loadFonts() error {
+	// Extra fonts are only a fallback for glyphs the game fonts don't have, so the game still runs without them
+	fallbacks, err := loadFallbackFonts()
+	if err != nil {
+		log.Println(err)
+	}
+
+	ttfs := map[string][]byte{
+		fontRegular: goregular.TTF,
+		fontMono:    gomono.TTF,
+		fontBold:    gobold.TTF,
+	}
+	for name, ttf := range ttfs {
+		for _, scale := range uiScales {
+			a.fonts[fontKey{name, scale}], err = newFontSet(ttf, fallbacks, float64(scale)/100)
+			if err != nil {
+				return fmt.Errorf("font %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newFontSet creates the title, normal and small faces for a font at a scale, each falling back to the fallback fonts for missing glyphs
+func newFontSet(ttf []byte, fallbacks []*opentype.Font, scale float64) (*FontSet, error) {
+	tt, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	titleFont, err := newFallbackFaceAtSize(tt, fallbacks, titleFontSize*scale)
+	if err != nil {
+		return nil, err
+	}
+	normalFont, err := newFallbackFaceAtSize(tt, fallbacks, fontSize*scale)
+	if err != nil {
+		return nil, err
+	}
+	smallFont, err := newFallbackFaceAtSize(tt, fallbacks, smallFontSize*scale)
+	if err != nil {
+		return nil, err
+	}
+	return &FontSet{Title: titleFont, Normal: normalFont, Small: smallFont}, nil
+}
+
+// newFallbackFaceAtSize creates a face of a font at a size, chained with the fallback fonts at the same size
+func newFallbackFaceAtSize(tt *opentype.Font, fallbacks []*opentype.Font, size float64) (font.Face, error) {
+	const dpi = 72
+	options := &opentype.FaceOptions{
+		Size:    size,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	}
+	primary, err := opentype.NewFace(tt, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var faces []font.Face
+	for _, fallback := range fallbacks {
+		face, err := opentype.NewFace(fallback, options)
+		if err != nil {
+			return nil, err
+		}
+		faces = append(faces, face)
+	}
+	return newFallbackFace(primary, faces), nil
+}
+
+// Image returns the image loaded for a key
+func (a *Assets) Image(key string) *ebiten.Image {
+	return a.images[key]
+}
+
+// Fonts returns the font set loaded for a key at a UI scale
+func (a *Assets) Fonts(key string, scale int) *FontSet {
+	return a.fonts[fontKey{key, scale}]
+}
+
+// newImageFromAsset decodes an embedded image, given its file name in the assets folder
+func newImageFromAsset(name string) (*ebiten.Image, error) {
+	data, err := assetFiles.ReadFile(path.Join("assets", name))
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"math"
+)
+
+const (
+	// pathAssistKey is the key that toggles the asteroid path assist on the title screen
+	pathAssistKey = ebiten.KeyA
+	// pathAssistMeters is how many meters at the starting speed an asteroid's predicted path is shown for after it spawns
+	pathAssistMeters = 60
+	// pathAssistLookahead is how many frames ahead an asteroid's path is predicted
+	pathAssistLookahead = 120
+	// pathAssistWidth is the width of the predicted path line
+	pathAssistWidth = 2
+)
+
+// updatePathAssistOption toggles the asteroid path assist when its key is pressed, remembering the choice in the profile
+func (g *Game) updatePathAssistOption() {
+	if !g.input.IsKeyJustPressed(pathAssistKey) {
+		return
+	}
+
+	g.settings.PathAssist = !g.settings.PathAssist
+	if err := g.settings.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// trackAsteroidPath starts showing the predicted path of a newly spawned asteroid, if the path assist is enabled
+func (g *Game) trackAsteroidPath(asteroid *spriteutils.Sprite) {
+	if !g.settings.PathAssist {
+		return
+	}
+
+	if g.asteroidPaths == nil {
+		g.asteroidPaths = map[*spriteutils.Sprite]telegraphWindow{}
+	}
+	g.asteroidPaths[asteroid] = g.newTelegraphWindow(pathAssistMeters)
+	g.isAssisted = true
+}
+
+// updateAsteroidPaths stops showing predicted paths once they have been shown long enough
+func (g *Game) updateAsteroidPaths() {
+	for asteroid, window := range g.asteroidPaths {
+		if window.isOver(g.distanceTravelled) {
+			delete(g.asteroidPaths, asteroid)
+		}
+	}
+}
+
+// drawAsteroidPaths draws a faint line along the predicted path of each recently spawned asteroid, fading as it ages
+func (g *Game) drawAsteroidPaths(r Renderer) {
+	for asteroid, window := range g.asteroidPaths {
+		dx := asteroid.XVelocity * pathAssistLookahead
+		dy := asteroid.YVelocity * pathAssistLookahead
+		if dx == 0 && dy == 0 {
+			continue
+		}
+		x, y := spriteCenter(asteroid)
+		fade := window.remaining(g.distanceTravelled)
+
+		op := &Transform{}
+		op.Translate(0, -0.5)
+		op.Scale(math.Hypot(dx, dy), pathAssistWidth)
+		op.Rotate(math.Atan2(dy, dx))
+		op.Translate(x, y)
+		op.ScaleColor(1, 0.4, 0.4, 0.4*fade)
+		r.DrawImage(g.assets.Image(imageBeam), op)
+	}
+}
+
+// drawPathAssistHint draws whether the path assist is enabled and how to change it at the bottom of the title screen
+func (g *Game) drawPathAssistHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'A' KEY TO TOGGLE ASTEROID PATH ASSIST: OFF"
+	if g.settings.PathAssist {
+		hint = "PRESS 'A' KEY TO TOGGLE ASTEROID PATH ASSIST: ON"
+	}
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*4, theme.Text())
+}
+
+// assistedLabel returns the label shown on the game over screen for runs played with the path assist or developer cheats
+func (g *Game) assistedLabel() string {
+	if g.isCheated {
+		return "(DEV RUN, NOT RECORDED)"
+	}
+	if g.isAssisted {
+		return "(ASSISTED)"
+	}
+	return ""
+}
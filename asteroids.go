@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"image"
+	"math"
+)
+
+// asteroidSize represents a size class of the asteroids spawned ahead of the ship.  Heavier asteroids take a bigger
+// push to get moving, so they drift in slowly while small ones dart about
+type asteroidSize struct {
+	// name is put after the names of the big asteroid images when saving asteroids of this size, empty for the originals
+	name string
+	// scale is the size of the asteroids compared to the big asteroid images
+	scale float64
+	// hitbox is the fraction of the width and height of the asteroids that collides, leaving out their rough edges
+	hitbox float64
+	// mass is how heavy the asteroids are, dividing the impulse they are spawned with
+	mass float64
+	// minImpulseX and maxImpulseX are the range of the horizontal impulse the asteroids are spawned with
+	minImpulseX, maxImpulseX int
+	// minImpulseY and maxImpulseY are the range of the vertical impulse the asteroids are spawned with
+	minImpulseY, maxImpulseY int
+	// weight is how likely the asteroids are to spawn at the starting speed, against the weights of the other sizes
+	weight int
+	// weightPerSpeed is how much the weight changes with each speed increase, the lowest weight being 1
+	weightPerSpeed int
+}
+
+// asteroidSizes are the size classes of asteroids, smallest first.  Larger rocks become more common as the speed rises
+var asteroidSizes = []asteroidSize{
+	{name: "_small", scale: 0.7, hitbox: 0.85, mass: 0.6, minImpulseX: -12, maxImpulseX: -6, minImpulseY: -3, maxImpulseY: 3, weight: 4, weightPerSpeed: -1},
+	{name: "", scale: 1, hitbox: 0.8, mass: 1, minImpulseX: -15, maxImpulseX: -6, minImpulseY: -3, maxImpulseY: 2, weight: 5},
+	{name: "_large", scale: 1.4, hitbox: 0.75, mass: 2.5, minImpulseX: -20, maxImpulseX: -10, minImpulseY: -2, maxImpulseY: 2, weight: 1, weightPerSpeed: 1},
+}
+
+// asteroidImageKeys are the keys of the big asteroid images every size class is scaled from
+var asteroidImageKeys = []string{imageAsteroid1, imageAsteroid2, imageAsteroid3, imageAsteroid4}
+
+// initializeAsteroidHulls sets the hitboxes of every stage's asteroids at every size, and of the fragments they split into
+func initializeAsteroidHulls(assets *Assets) {
+	for _, size := range asteroidSizes {
+		for _, stage := range stages {
+			for _, key := range asteroidImageKeys {
+				image := scaledImage(assets.Image(stage.prefix+key), size.scale)
+				setAsteroidHull(image, size.hitbox)
+				for _, fragment := range fragmentImages(image) {
+					setAsteroidHull(fragment, size.hitbox)
+				}
+			}
+		}
+	}
+}
+
+// setAsteroidHull sets the hull of an asteroid image to a rectangle in its middle, a fraction of its width and height
+func setAsteroidHull(img *ebiten.Image, hitbox float64) {
+	width, height := img.Size()
+	insetX := int(float64(width) * (1 - hitbox) / 2)
+	insetY := int(float64(height) * (1 - hitbox) / 2)
+	hulls[img] = []image.Rectangle{image.Rect(insetX, insetY, width-insetX, height-insetY)}
+}
+
+// randomAsteroidSize picks the size class of the next asteroid, weighted toward larger rocks the faster the ship is going
+func (g *Game) randomAsteroidSize() int {
+	speedIncreases := int(math.Max(g.speed-g.difficulty().StartSpeed, 0))
+
+	weights := make([]int, len(asteroidSizes))
+	total := 0
+	for i, size := range asteroidSizes {
+		weights[i] = size.weight + size.weightPerSpeed*speedIncreases
+		if weights[i] < 1 {
+			weights[i] = 1
+		}
+		total += weights[i]
+	}
+
+	pick := g.randIntn("asteroid size", total)
+	for i, weight := range weights {
+		if pick < weight {
+			return i
+		}
+		pick -= weight
+	}
+	return len(asteroidSizes) - 1
+}
+
+// generateAsteroid creates an asteroid of a random size class, pushed off with an impulse in its size's range and slowed by its mass
+func (g *Game) generateAsteroid() *spriteutils.Sprite {
+	index := g.randomAsteroidSize()
+	size := asteroidSizes[index]
+	asteroid := g.generateSprite(g.asteroidSizeFactories[index])
+
+	impulseX := size.minImpulseX + g.randIntn("asteroid impulse x", size.maxImpulseX-size.minImpulseX+1)
+	impulseY := size.minImpulseY + g.randIntn("asteroid impulse y", size.maxImpulseY-size.minImpulseY+1)
+	asteroid.ApplyImpulse(float64(impulseX)/size.mass, float64(impulseY)/size.mass)
+	return asteroid
+}
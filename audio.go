@@ -0,0 +1,405 @@
+package main
+
+import (
+	"encoding/binary"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/audio"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// sampleRate is the sample rate of the audio context and every generated sound
+	sampleRate = 44100
+	// soundGapFrames is the fewest frames between two plays of the same sound effect, so many explosions at once don't stack up
+	soundGapFrames = 3
+	// thrustVolume is the volume of the thrust loop
+	thrustVolume = 0.15
+)
+
+// soundEffect represents the sound effects that can be played
+type soundEffect int
+
+const (
+	// soundStar is played when a star is picked up
+	soundStar soundEffect = iota
+	// soundExplosion is played when an asteroid explodes
+	soundExplosion
+	// soundDeath is played when the ship is destroyed
+	soundDeath
+	// soundTick is played as the score counts up on the game over screen
+	soundTick
+	// soundJingle is played when a run beats the best distance
+	soundJingle
+	// soundLaser is played when the laser fires
+	soundLaser
+	// soundSplash is played when a ship splashes into water
+	soundSplash
+)
+
+// audioManager plays the game's sound effects.  The sounds are generated when the game starts, so no audio assets are needed
+type audioManager struct {
+	// context is the audio context all sounds are played through
+	context *audio.Context
+	// sounds are the generated samples for each sound effect
+	sounds map[soundEffect][]byte
+	// lastPlayed is the frame of the audio clock each sound effect was last played on
+	lastPlayed map[soundEffect]int64
+	// effects are the sound effects that have been played and may still be sounding
+	effects []*audio.Player
+	// frame is the audio clock, counting the frames the game has run for while the audio isn't suspended
+	frame int64
+	// musicStartFrame is the frame of the audio clock the current music started on
+	musicStartFrame int64
+	// musicLength is how long the music takes to loop
+	musicLength time.Duration
+	// suspends is how many things are holding the audio suspended, such as pausing and minimizing the window
+	suspends int
+	// suspended are the players that were sounding when the audio was suspended, to carry on when it is resumed
+	suspended []*audio.Player
+	// thrust is the looping thrust sound, played while the ship is thrusting
+	thrust *audio.Player
+	// music is the looping base layer of the background music
+	music *audio.Player
+	// musicIntense is the looping intense layer of the background music, played in time with the base layer and faded in with intensity
+	musicIntense *audio.Player
+	// volume is the volume all sound is scaled by, from 0 to 1
+	volume float64
+	// isMusicMuted represents whether the background music is turned off
+	isMusicMuted bool
+	// isSoundMuted represents whether the sound effects, thrust loop and announcer are turned off
+	isSoundMuted bool
+	// announcements are the announcer clips for the player's language, missing lines stay silent
+	announcements map[announcement][]byte
+	// isAnnouncerEnabled represents whether the announcer calls out lines
+	isAnnouncerEnabled bool
+	// announcerVolume is the volume of the announcer from 0 to 1, on top of the overall volume
+	announcerVolume float64
+}
+
+// newAudioManager creates the audio context and generates every sound effect, playing them at the given volume.  Only one audio manager can be created
+func newAudioManager(volume float64) (*audioManager, error) {
+	context, err := audio.NewContext(sampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &audioManager{
+		context: context,
+		sounds: map[soundEffect][]byte{
+			soundStar:      synthesize(time.Millisecond*250, starSample),
+			soundExplosion: synthesize(time.Millisecond*300, explosionSample),
+			soundDeath:     synthesize(time.Millisecond*900, deathSample),
+			soundTick:      synthesize(time.Millisecond*30, tickSample),
+			soundJingle:    synthesize(time.Millisecond*1200, jingleSample),
+			soundLaser:     synthesize(time.Millisecond*150, laserSample),
+			soundSplash:    synthesize(time.Millisecond*400, splashSample),
+		},
+		lastPlayed:  map[soundEffect]int64{},
+		volume:      volume,
+		musicLength: loopLength(musicBaseLayer),
+	}
+
+	a.thrust, err = newLoopPlayer(context, synthesize(time.Second, thrustSample))
+	if err != nil {
+		return nil, err
+	}
+	a.thrust.SetVolume(thrustVolume * volume)
+
+	a.music, err = newLoopPlayer(context, musicBaseLayer)
+	if err != nil {
+		return nil, err
+	}
+	a.music.SetVolume(musicVolume * volume)
+
+	a.musicIntense, err = newLoopPlayer(context, musicIntenseLayer)
+	if err != nil {
+		return nil, err
+	}
+	a.musicIntense.SetVolume(0)
+
+	// The announcer is optional, so the game still has sound without its clips
+	if err := a.loadAnnouncer(announcerLanguage()); err != nil {
+		log.Println(err)
+	}
+	return a, nil
+}
+
+// newLoopPlayer creates a player that loops the given samples forever
+func newLoopPlayer(context *audio.Context, samples []byte) (*audio.Player, error) {
+	return audio.NewPlayer(context, audio.NewInfiniteLoop(audio.BytesReadSeekCloser(samples), int64(len(samples))))
+}
+
+// loopLength returns how long 16 bit stereo samples take to play through once
+func loopLength(samples []byte) time.Duration {
+	return time.Duration(len(samples)/4) * time.Second / sampleRate
+}
+
+// play plays a sound effect.  It does nothing if there is no audio, so the title preview and failed audio setups stay silent
+func (a *audioManager) play(effect soundEffect) {
+	if a == nil || a.isSoundMuted {
+		return
+	}
+
+	if frame, ok := a.lastPlayed[effect]; ok && a.frame-frame < soundGapFrames {
+		return
+	}
+	a.lastPlayed[effect] = a.frame
+
+	player, err := audio.NewPlayerFromBytes(a.context, a.sounds[effect])
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	player.SetVolume(a.volume)
+	if err := player.Play(); err != nil {
+		log.Println(err)
+	}
+	a.trackEffect(player)
+}
+
+// trackEffect keeps hold of a sound effect so it can be suspended, letting go of the ones that have finished.  Effects paused by
+// a suspension aren't playing either, so nothing is let go of until the audio is resumed
+func (a *audioManager) trackEffect(player *audio.Player) {
+	if a.suspends == 0 {
+		effects := a.effects[:0]
+		for _, effect := range a.effects {
+			if effect.IsPlaying() {
+				effects = append(effects, effect)
+			} else if err := effect.Close(); err != nil {
+				log.Println(err)
+			}
+		}
+		a.effects = effects
+	}
+	a.effects = append(a.effects, player)
+}
+
+// setVolume changes the volume all sound is scaled by, from 0 to 1
+func (a *audioManager) setVolume(volume float64) {
+	if a == nil {
+		return
+	}
+
+	a.volume = volume
+	a.thrust.SetVolume(thrustVolume * volume)
+	a.music.SetVolume(musicVolume * a.musicVolume())
+}
+
+// setMuted turns the background music and the sound effects on or off
+func (a *audioManager) setMuted(isMusicMuted, isSoundMuted bool) {
+	if a == nil {
+		return
+	}
+
+	a.isMusicMuted = isMusicMuted
+	a.isSoundMuted = isSoundMuted
+	a.music.SetVolume(musicVolume * a.musicVolume())
+	if isSoundMuted {
+		a.setThrusting(false)
+	}
+}
+
+// musicVolume returns the volume the background music is scaled by, silent while it is turned off
+func (a *audioManager) musicVolume() float64 {
+	if a.isMusicMuted {
+		return 0
+	}
+	return a.volume
+}
+
+// setThrusting starts or stops the thrust loop
+func (a *audioManager) setThrusting(isThrusting bool) {
+	if a == nil || a.suspends > 0 || (isThrusting && a.isSoundMuted) || isThrusting == a.thrust.IsPlaying() {
+		return
+	}
+
+	var err error
+	if isThrusting {
+		err = a.thrust.Play()
+	} else {
+		err = a.thrust.Pause()
+	}
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// playMusic starts the background music if it isn't already playing, and sets its intensity from 0 to 1
+func (a *audioManager) playMusic(intensity float64) {
+	if a == nil || a.suspends > 0 {
+		return
+	}
+
+	if !a.music.IsPlaying() {
+		// Both layers start together so they stay in time
+		a.musicStartFrame = a.frame
+		for _, player := range []*audio.Player{a.music, a.musicIntense} {
+			if err := player.Play(); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+	a.musicIntense.SetVolume(musicIntenseVolume * intensity * a.musicVolume())
+}
+
+// setMusic swaps the layers of the background music for others, picking up on the next call to playMusic
+func (a *audioManager) setMusic(base, intense []byte) {
+	if a == nil {
+		return
+	}
+
+	music, err := newLoopPlayer(a.context, base)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	musicIntense, err := newLoopPlayer(a.context, intense)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, player := range []*audio.Player{a.music, a.musicIntense} {
+		if err := player.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+	a.music, a.musicIntense = music, musicIntense
+	a.musicLength = loopLength(base)
+	a.music.SetVolume(musicVolume * a.musicVolume())
+	a.musicIntense.SetVolume(0)
+}
+
+// tick moves the audio clock on by a frame.  It stands still while the audio is suspended, so the music can be put back on the
+// game's beat when it resumes
+func (a *audioManager) tick() {
+	if a == nil || a.suspends > 0 {
+		return
+	}
+	a.frame++
+}
+
+// suspend pauses the music, the thrust loop and every sound effect still sounding exactly where they are, until resume is called
+// as many times as suspend was
+func (a *audioManager) suspend() {
+	if a == nil {
+		return
+	}
+
+	a.suspends++
+	if a.suspends > 1 {
+		return
+	}
+	for _, player := range append([]*audio.Player{a.music, a.musicIntense, a.thrust}, a.effects...) {
+		if !player.IsPlaying() {
+			continue
+		}
+		if err := player.Pause(); err != nil {
+			log.Println(err)
+			continue
+		}
+		a.suspended = append(a.suspended, player)
+	}
+}
+
+// resume carries on everything suspend paused once nothing holds the audio suspended.  The music is first moved to where the
+// audio clock says it should be, so it picks up on the same beat the game left it on rather than however far the wall clock got
+func (a *audioManager) resume() {
+	if a == nil || a.suspends == 0 {
+		return
+	}
+
+	a.suspends--
+	if a.suspends > 0 {
+		return
+	}
+	a.syncMusic()
+	for _, player := range a.suspended {
+		if err := player.Play(); err != nil {
+			log.Println(err)
+		}
+	}
+	a.suspended = nil
+}
+
+// syncMusic moves both layers of the music to the position the audio clock has counted up to since the music started
+func (a *audioManager) syncMusic() {
+	if a.musicLength <= 0 {
+		return
+	}
+
+	position := time.Duration(a.frame-a.musicStartFrame) * time.Second / ebiten.DefaultTPS % a.musicLength
+	for _, player := range []*audio.Player{a.music, a.musicIntense} {
+		if err := player.Seek(position); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// synthesize generates 16 bit stereo samples for a sound, given a function returning the sample from -1 to 1 at a time in seconds
+func synthesize(duration time.Duration, sample func(t float64, progress float64) float64) []byte {
+	count := int(duration.Seconds() * sampleRate)
+	data := make([]byte, count*4)
+	for i := 0; i < count; i++ {
+		t := float64(i) / sampleRate
+		value := int16(math.Max(-1, math.Min(1, sample(t, float64(i)/float64(count)))) * math.MaxInt16)
+		binary.LittleEndian.PutUint16(data[i*4:], uint16(value))
+		binary.LittleEndian.PutUint16(data[i*4+2:], uint16(value))
+	}
+	return data
+}
+
+// starSample is a rising chime
+func starSample(t float64, progress float64) float64 {
+	frequency := 880 + 880*progress
+	return math.Sin(2*math.Pi*frequency*t) * (1 - progress) * 0.4
+}
+
+// explosionSample is a burst of noise that quickly dies away
+func explosionSample(t float64, progress float64) float64 {
+	return (rand.Float64()*2 - 1) * math.Pow(1-progress, 3) * 0.5
+}
+
+// deathSample is a falling tone over a long rumble of noise
+func deathSample(t float64, progress float64) float64 {
+	frequency := 440 - 360*progress
+	tone := math.Sin(2*math.Pi*frequency*t) * 0.3
+	noise := (rand.Float64()*2 - 1) * 0.3
+	return (tone + noise) * (1 - progress)
+}
+
+// tickSample is a short click
+func tickSample(t float64, progress float64) float64 {
+	return math.Sin(2*math.Pi*1760*t) * (1 - progress) * 0.2
+}
+
+// jingleSample is a rising arpeggio ending on a held note
+func jingleSample(t float64, progress float64) float64 {
+	notes := []float64{523.25, 659.25, 783.99, 1046.5}
+	step := int(progress * 8)
+	if step >= len(notes) {
+		step = len(notes) - 1
+	}
+	return math.Sin(2*math.Pi*notes[step]*t) * (1 - progress) * 0.4
+}
+
+// laserSample is a quickly falling zap
+func laserSample(t float64, progress float64) float64 {
+	frequency := 1800 - 1400*progress
+	return math.Sin(2*math.Pi*frequency*t) * (1 - progress) * 0.25
+}
+
+// splashSample is a hiss of noise with a bubbling tone falling under it
+func splashSample(t float64, progress float64) float64 {
+	noise := (rand.Float64()*2 - 1) * math.Pow(1-progress, 2) * 0.35
+	bubble := math.Sin(2*math.Pi*(300+200*math.Sin(2*math.Pi*12*t))*t) * (1 - progress) * 0.2
+	return noise + bubble
+}
+
+// thrustSample is a pulsing rumble of noise
+func thrustSample(t float64, progress float64) float64 {
+	return (rand.Float64()*2 - 1) * (0.6 + 0.4*math.Sin(2*math.Pi*8*t))
+}
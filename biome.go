@@ -0,0 +1,166 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"math"
+	"math/rand"
+)
+
+const (
+	// biomeKey is the key that cycles through the biomes on the title screen
+	biomeKey = ebiten.KeyB
+	// waterDragFrames is how many frames a splash slows the ship down for
+	waterDragFrames = 120
+	// waterDrag scales the ship's vertical velocity every frame while it is slowed by a splash
+	waterDrag = 0.92
+	// waterSpeedLoss is the fraction of the world speed lost while the ship is slowed by a splash
+	waterSpeedLoss = 0.4
+	// waterBounce is the fraction of its speed a ship keeps when the water pushes it back up
+	waterBounce = 0.5
+	// waterWaveHeight is how far the water tiles bob up and down
+	waterWaveHeight = 4
+	// waterWaveSpeed is how fast the water tiles bob, in radians per frame
+	waterWaveSpeed = 0.08
+	// waterWaveLength is how far apart the peaks of the waves are, in radians per pixel
+	waterWaveLength = 0.02
+	// splashParticles is how many droplets a splash throws up
+	splashParticles = 14
+)
+
+// Biome represents the surroundings a run is played in
+type Biome struct {
+	// Name is the name of the biome, shown to the player and saved in the profile
+	Name string
+	// bottomGround is the type of the ground along the bottom of the screen
+	bottomGround entityType
+	// bottomImage is the key of the image the bottom ground is drawn with
+	bottomImage string
+}
+
+// biomes are every biome, the first being the default
+var biomes = []Biome{
+	{Name: "ASTEROID BELT", bottomGround: entityGround, bottomImage: imageFloor},
+	{Name: "OCEAN", bottomGround: entityWater, bottomImage: imageWater},
+}
+
+// biome returns the biome selected in the profile, or the default biome if none is selected
+func (g *Game) biome() *Biome {
+	for i := range biomes {
+		if biomes[i].Name == g.profile.Biome {
+			return &biomes[i]
+		}
+	}
+	return &biomes[0]
+}
+
+// updateBiome cycles to the next biome when its key is pressed on the title screen, remembering the choice in the profile
+func (g *Game) updateBiome() {
+	if !g.input.IsKeyJustPressed(biomeKey) {
+		return
+	}
+
+	current := g.biome()
+	for i := range biomes {
+		if &biomes[i] == current {
+			g.profile.Biome = biomes[(i+1)%len(biomes)].Name
+			break
+		}
+	}
+
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
+	}
+
+	// The title preview shares the profile, so it shows the new biome too
+	g.resetGame()
+	g.titlePreview.resetGame()
+}
+
+// isDragged returns whether the ship is slowed down by a splash
+func (g *Game) isDragged() bool {
+	return g.waterDragEndFrame > g.frameCount
+}
+
+// updateWater bobs the water tiles up and down in a wave, and gives back the speed lost to a splash once the drag wears off
+func (g *Game) updateWater() {
+	_, height := g.assets.Image(imageWater).Size()
+	for _, e := range g.entities {
+		if e.kind != entityWater {
+			continue
+		}
+		wave := math.Sin(float64(g.frameCount)*waterWaveSpeed + float64(e.X)*waterWaveLength)
+		e.Y = screenHeight - height + int(math.Round(waterWaveHeight*(wave+1)))
+	}
+
+	if g.waterSpeedLoss != 0 && !g.isDragged() {
+		g.speed += g.waterSpeedLoss
+		g.waterSpeedLoss = 0
+	}
+}
+
+// checkWaterCollisions is the water collision system.  Water pushes any ship sinking into it back up and slows the
+// ship down for a while instead of ending the run, and any asteroid hitting it is destroyed
+func (g *Game) checkWaterCollisions() {
+	for _, e := range g.entities {
+		if e.collider != colliderWater {
+			continue
+		}
+		for _, ship := range g.ships() {
+			if ship.IsColliding(e.Sprite) && ship.YVelocity > 0 {
+				g.splash(ship)
+			}
+		}
+		g.destroyAsteroidsColliding(e.Sprite, destroyedByTerrain)
+	}
+}
+
+// splash pushes a ship back out of the water and slows it down, throwing up droplets where it hit.  Splashing again
+// while still slowed down only restarts the drag
+func (g *Game) splash(ship *spriteutils.Sprite) {
+	x, _ := spriteCenter(ship)
+	_, height := ship.Image.Size()
+	g.emitSplash(x, float64(ship.Y+height), ship.YVelocity)
+	g.audio.play(soundSplash)
+
+	ship.YVelocity = -ship.YVelocity * waterBounce
+	if !g.isDragged() {
+		// The loss leaves out any boost, so losing the boost during the drag can't stop the world
+		speed := g.speed
+		if g.isBoosting {
+			speed -= g.boostFactor
+		}
+		g.waterSpeedLoss = speed * waterSpeedLoss
+		g.speed -= g.waterSpeedLoss
+	}
+	g.waterDragEndFrame = g.frameCount + waterDragFrames
+}
+
+// applyWaterDrag slows a ship's vertical movement while it is slowed by a splash
+func (g *Game) applyWaterDrag(ship *spriteutils.Sprite) {
+	if g.isDragged() {
+		ship.YVelocity *= waterDrag
+	}
+}
+
+// emitSplash emits droplets thrown up from the surface of the water at a position, higher the faster the ship hit it
+func (g *Game) emitSplash(x, y, impact float64) {
+	for i := 0; i < splashParticles; i++ {
+		droplet := g.particles.emit(g.assets.Image(imageBeam), x+(rand.Float64()-0.5)*30, y, 35)
+		droplet.xVelocity = (rand.Float64() - 0.5) * 4
+		droplet.yVelocity = -(1 + rand.Float64()*2) * math.Max(impact, 1)
+		droplet.gravity = 0.25
+		droplet.startScale = 3
+		droplet.endScale = 1
+		droplet.red, droplet.green, droplet.blue = 0.5, 0.8, 1
+		droplet.scrolls = true
+	}
+}
+
+// drawBiomeHint draws the selected biome and how to change it at the bottom of the title screen
+func (g *Game) drawBiomeHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'B' KEY TO CHANGE BIOME: " + g.biome().Name
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize), theme.Text())
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// bossInterval is the distance between bosses
+	bossInterval = 5000
+	// bossFirstDistance is the distance of the first boss, halfway between chaos events so the two don't arrive together
+	bossFirstDistance = 2500
+	// bossScale is how much bigger than a big asteroid the boss is
+	bossScale = 3
+	// bossHealth is how many laser hits it takes to destroy the boss
+	bossHealth = 15
+	// bossFrames is how many frames the boss has to be survived for before it gives up and leaves
+	bossFrames = 20 * 60
+	// bossMargin is the space between the boss and the right of the screen once it has arrived
+	bossMargin = 40
+	// bossEntrySpeed is how fast the boss moves onto the screen
+	bossEntrySpeed = 3
+	// bossLeaveSpeed is how fast the boss moves back off the screen once it gives up
+	bossLeaveSpeed = 4
+	// bossChaseSpeed is the fastest the boss moves up or down to follow the ship
+	bossChaseSpeed = 2.5
+	// bossFireFrames is how many frames the boss chases the ship between volleys
+	bossFireFrames = 100
+	// bossChargeFrames is how many frames the boss holds still, charging, before it fires a volley
+	bossChargeFrames = 40
+	// bossBoltSpeed is how far a bolt fired by the boss or a UFO travels each frame
+	bossBoltSpeed = 6
+	// bossBoltSpread is the angle between the bolts of a volley, in radians
+	bossBoltSpread = 0.25
+	// bossBoltCount is how many bolts there are in each volley
+	bossBoltCount = 3
+	// maxBolts is the most bolts from the boss and UFOs allowed in the world at once
+	maxBolts = 30
+	// bossShake is the strength of the shake when the boss is destroyed
+	bossShake = 20
+	// bossExplosions is how many explosions go off across the boss when it is destroyed
+	bossExplosions = 8
+	// bossBonusPoints is the score for destroying the boss
+	bossBonusPoints = 1000
+	// bossMeterWidth is the width of the boss health meter
+	bossMeterWidth = 300
+	// bossMeterHeight is the height of the boss health meter
+	bossMeterHeight = 10
+)
+
+// bossState represents what the boss is doing
+type bossState int
+
+const (
+	// bossEntering represents the boss moving onto the screen
+	bossEntering bossState = iota
+	// bossChasing represents the boss following the ship up and down the screen
+	bossChasing
+	// bossCharging represents the boss holding still before it fires a volley
+	bossCharging
+	// bossLeaving represents the boss giving up and moving back off the screen
+	bossLeaving
+)
+
+// boss is a giant asteroid that arrives every bossInterval, chasing the ship and firing volleys of bolts at it.  Spires,
+// asteroids, rings and twins hold off until it has been destroyed with the laser or survived until it leaves
+type boss struct {
+	// entity is the boss in the world
+	entity *entity
+	// state is what the boss is doing
+	state bossState
+	// stateFrames is how many frames the boss has been in its current state
+	stateFrames int
+	// frames is how many frames the boss has been on the screen
+	frames int
+	// health is how many more laser hits the boss takes to destroy
+	health int
+}
+
+// setState moves the boss on to a new state
+func (b *boss) setState(state bossState) {
+	b.state = state
+	b.stateFrames = 0
+}
+
+// updateBoss brings in a boss every bossInterval and runs the boss's state machine.  The boss holds still while asteroids are frozen
+func (g *Game) updateBoss() {
+	if g.boss == nil {
+		if g.distanceTravelled > g.bossSpawnThreshold {
+			g.spawnBoss()
+			g.bossSpawnThreshold += bossInterval
+		}
+		return
+	}
+
+	b := g.boss
+	if g.isFreezeActive() {
+		b.entity.XVelocity, b.entity.YVelocity = 0, 0
+		return
+	}
+	b.stateFrames++
+	b.frames++
+
+	width, _ := b.entity.Image.Size()
+	switch b.state {
+	case bossEntering:
+		b.entity.XVelocity = -bossEntrySpeed
+		if b.entity.X <= screenWidth-width-bossMargin {
+			b.entity.XVelocity = 0
+			b.setState(bossChasing)
+		}
+	case bossChasing:
+		_, shipY := spriteCenter(g.ship)
+		_, bossY := spriteCenter(b.entity.Sprite)
+		b.entity.YVelocity = math.Max(-bossChaseSpeed, math.Min(bossChaseSpeed, shipY-bossY))
+		if b.frames >= bossFrames {
+			b.setState(bossLeaving)
+		} else if b.stateFrames >= bossFireFrames {
+			b.entity.YVelocity = 0
+			b.setState(bossCharging)
+		}
+	case bossCharging:
+		if b.stateFrames >= bossChargeFrames {
+			g.fireBossVolley()
+			b.setState(bossChasing)
+		}
+	case bossLeaving:
+		b.entity.XVelocity = bossLeaveSpeed
+		b.entity.YVelocity = 0
+		if b.entity.X >= screenWidth {
+			g.removeBoss()
+		}
+	}
+}
+
+// spawnBoss brings a boss onto the screen from the right, level with the middle of the screen
+func (g *Game) spawnBoss() {
+	sprite := g.sprites.get()
+	sprite.Image = g.assets.Image(imageBoss)
+	_, height := sprite.Image.Size()
+	sprite.X = screenWidth
+	sprite.Y = (screenHeight - height) / 2
+	g.boss = &boss{entity: g.addEntity(entityBoss, sprite), health: bossHealth}
+}
+
+// removeBoss takes the boss out of the world, letting the normal spawns pick up again
+func (g *Game) removeBoss() {
+	e := g.boss.entity
+	g.filterEntities(func(other *entity) bool {
+		return other != e
+	})
+	g.releaseEntity(e)
+	g.boss = nil
+}
+
+// fireBossVolley fires a spread of bolts from the boss, aimed at the ship
+func (g *Game) fireBossVolley() {
+	bossX, bossY := spriteCenter(g.boss.entity.Sprite)
+	shipX, shipY := spriteCenter(g.ship)
+	aim := math.Atan2(shipY-bossY, shipX-bossX)
+	for i := 0; i < bossBoltCount; i++ {
+		g.fireBolt(bossX, bossY, aim+(float64(i)-float64(bossBoltCount-1)/2)*bossBoltSpread)
+	}
+}
+
+// fireBolt fires a bolt from a position in a direction, in radians
+func (g *Game) fireBolt(x, y, angle float64) {
+	width, height := g.assets.Image(imageBossBolt).Size()
+	bolt := g.sprites.get()
+	bolt.Image = g.assets.Image(imageBossBolt)
+	bolt.X = int(x) - width/2
+	bolt.Y = int(y) - height/2
+	bolt.XVelocity = math.Cos(angle) * bossBoltSpeed
+	bolt.YVelocity = math.Sin(angle) * bossBoltSpeed
+	g.addEntity(entityBolt, bolt)
+	g.audio.play(soundLaser)
+}
+
+// checkBossCollisions is the boss collision system.  A ship hitting the boss or one of its bolts ends the run, shields
+// block bolts, and each laser projectile hitting the boss wears it down until it is destroyed
+func (g *Game) checkBossCollisions() {
+	g.filterEntities(func(e *entity) bool {
+		switch e.collider {
+		case colliderBoss, colliderBolt:
+			for _, shield := range g.shields() {
+				if e.collider == colliderBolt && shield.IsColliding(e.Sprite) {
+					g.releaseEntity(e)
+					return false
+				}
+			}
+			if g.isShipColliding(e.Sprite) {
+				g.mode = ModeGameOver
+			}
+		case colliderProjectile:
+			if g.boss != nil && g.boss.state != bossLeaving && e.IsColliding(g.boss.entity.Sprite) {
+				g.hitBoss(e.Sprite)
+				g.releaseEntity(e)
+				return false
+			}
+		}
+		return true
+	})
+
+	if g.boss != nil && g.boss.health <= 0 {
+		x, y := spriteCenter(g.boss.entity.Sprite)
+		g.publish(&Event{Kind: EventBossDefeated, X: x, Y: y})
+		g.removeBoss()
+	}
+}
+
+// hitBoss wears the boss down by one laser hit, throwing sparks where the projectile struck
+func (g *Game) hitBoss(projectile *spriteutils.Sprite) {
+	g.boss.health--
+	x, y := spriteCenter(projectile)
+	flash := g.particles.emit(g.assets.Image(imageAsteroidExplosion), x, y, 4)
+	flash.startScale, flash.endScale = 0.5, 0.2
+	flash.scrolls = true
+}
+
+// emitBossExplosion sets off explosions across the boss when it is destroyed
+func (g *Game) emitBossExplosion(x, y float64) {
+	_, height := g.assets.Image(imageBoss).Size()
+	radius := float64(height) / 3
+	for i := 0; i < bossExplosions; i++ {
+		angle := 2 * math.Pi * float64(i) / bossExplosions
+		g.emitExplosion(x+math.Cos(angle)*radius, y+math.Sin(angle)*radius)
+	}
+	g.emitExplosion(x, y)
+}
+
+// onBossDefeated scores the destroyed boss
+func (g *Game) onBossDefeated(x, y float64) {
+	g.bonusScore += bossBonusPoints
+	g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d BOSS DOWN", bossBonusPoints), x: x, y: y})
+}
+
+// holdSpawnsForBoss pushes back the spires, asteroids, rings, twins, laser gates, currents, UFOs and chaos events while a boss is around, so they pick
+// up where they left off once it is gone
+func (g *Game) holdSpawnsForBoss() {
+	if g.boss == nil {
+		return
+	}
+	held := int(g.speed)
+	g.spireSpawnThreshold += held
+	g.asteroidSpawnThreshold += held
+	g.ringSpawnThreshold += held
+	g.twinSpawnThreshold += held
+	g.gateSpawnThreshold += held
+	g.currentSpawnThreshold += held
+	g.ufoSpawnThreshold += held
+	g.chaosSpawnThreshold += held
+}
+
+// drawBossMeter draws the health of the boss along the top of the screen while it is around
+func (g *Game) drawBossMeter(r Renderer) {
+	if g.boss == nil {
+		return
+	}
+
+	theme := g.theme()
+	width, height := g.uiFloat(bossMeterWidth), g.uiFloat(bossMeterHeight)
+	x := (float64(screenWidth) - width) / 2
+	y := g.uiFloat(fontSize * 2)
+
+	label := "BEHEMOTH"
+	if g.boss.state == bossCharging && (g.boss.stateFrames/6)%2 == 0 {
+		label = "BEHEMOTH - CHARGING!"
+	}
+	r.DrawText(label, g.fonts().Small, (screenWidth-len(label)*g.ui(smallFontSize)/2)/2, int(y)-g.ui(smallFontSize)/2, theme.Accent())
+	r.DrawRect(x, y, width, height, theme.Panel())
+	r.DrawRect(x, y, width*float64(g.boss.health)/bossHealth, height, theme.Accent())
+}
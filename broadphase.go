@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+// broadphaseCellWidth is the width of each column of the broadphase
+const broadphaseCellWidth = 128
+
+// broadphase buckets sprites into columns by horizontal position, so collision checks only test sprites near each other.
+// Everything in the world scrolls horizontally, so columns alone split the sprites up well
+type broadphase struct {
+	// columns are the sprites whose left edge is in each column, keyed by column index
+	columns map[int][]*spriteutils.Sprite
+	// maxWidth is the width of the widest sprite, how far to the left a sprite overlapping a column can start
+	maxWidth int
+	// removed are the sprites removed since the last rebuild, which are no longer returned as nearby
+	removed map[*spriteutils.Sprite]bool
+	// nearbyBuffer holds the result of the last nearby query, reused between queries
+	nearbyBuffer []*spriteutils.Sprite
+}
+
+// column returns the index of the column a horizontal position is in
+func (b *broadphase) column(x int) int {
+	return int(math.Floor(float64(x) / broadphaseCellWidth))
+}
+
+// rebuild buckets a fresh set of sprites, forgetting any removed sprites
+func (b *broadphase) rebuild(sprites []*spriteutils.Sprite) {
+	if b.columns == nil {
+		b.columns = map[int][]*spriteutils.Sprite{}
+		b.removed = map[*spriteutils.Sprite]bool{}
+	}
+	for column, bucket := range b.columns {
+		b.columns[column] = bucket[:0]
+	}
+	for sprite := range b.removed {
+		delete(b.removed, sprite)
+	}
+
+	b.maxWidth = 0
+	for _, sprite := range sprites {
+		width, _ := sprite.Image.Size()
+		if width > b.maxWidth {
+			b.maxWidth = width
+		}
+		column := b.column(sprite.X)
+		b.columns[column] = append(b.columns[column], sprite)
+	}
+}
+
+// remove stops a sprite from being returned as nearby until the next rebuild
+func (b *broadphase) remove(sprite *spriteutils.Sprite) {
+	b.removed[sprite] = true
+}
+
+// isRemoved returns whether a sprite has been removed since the last rebuild
+func (b *broadphase) isRemoved(sprite *spriteutils.Sprite) bool {
+	return b.removed[sprite]
+}
+
+// nearby returns the sprites in the columns a sprite overlaps, which are the only ones it can be colliding with.
+// The result is only valid until the next query
+func (b *broadphase) nearby(sprite *spriteutils.Sprite) []*spriteutils.Sprite {
+	b.nearbyBuffer = b.nearbyBuffer[:0]
+
+	width, _ := sprite.Image.Size()
+	for column := b.column(sprite.X - b.maxWidth); column <= b.column(sprite.X+width); column++ {
+		for _, candidate := range b.columns[column] {
+			if !b.removed[candidate] {
+				b.nearbyBuffer = append(b.nearbyBuffer, candidate)
+			}
+		}
+	}
+	return b.nearbyBuffer
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+// offScreenMarginX returns how far past the left and right of the screen the zoomed out kill-cam shows, which a sprite must
+// be beyond before detailed work on it is skipped
+func offScreenMarginX() int {
+	return int(math.Ceil(float64(screenWidth) * (1/killCamZoom - 1) / 2))
+}
+
+// offScreenMarginY returns how far past the top and bottom of the screen the zoomed out kill-cam shows
+func offScreenMarginY() int {
+	return int(math.Ceil(float64(screenHeight) * (1/killCamZoom - 1) / 2))
+}
+
+// isFarOffScreen returns whether a sprite is far enough outside the screen that it cannot be seen, even by the kill-cam
+func isFarOffScreen(sprite *spriteutils.Sprite) bool {
+	width, height := sprite.Image.Size()
+	marginX, marginY := offScreenMarginX(), offScreenMarginY()
+	return sprite.X+width < -marginX ||
+		sprite.X > screenWidth+marginX ||
+		sprite.Y+height < -marginY ||
+		sprite.Y > screenHeight+marginY
+}
+
+// isLeavingWorld returns whether a sprite is out of bounds and heading further away, so it can be culled.  Sprites out of
+// bounds but heading into the screen, such as hazards entering from the left or top, are kept
+func isLeavingWorld(sprite *spriteutils.Sprite) bool {
+	margin := -tuning.OutOfBoundsX
+	return (sprite.X <= -margin && sprite.XVelocity <= 0) ||
+		(sprite.X >= screenWidth+margin && sprite.XVelocity >= 0) ||
+		(sprite.Y <= -margin && sprite.YVelocity <= 0) ||
+		(sprite.Y >= screenHeight+margin && sprite.YVelocity >= 0)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	// minCameraZoom is the furthest the camera zooms out at high speed
+	minCameraZoom = 0.85
+	// cameraZoomSpeed is the speed the camera is fully zoomed out at
+	cameraZoomSpeed = 8
+	// cameraZoomFrames is how many frames the camera takes to settle on a new zoom, so speed changes don't snap the view
+	cameraZoomFrames = 120
+	// explosionShake is the strength of the shake from an asteroid exploding right next to a ship
+	explosionShake = 6
+	// explosionShakeRange is the furthest from a ship an asteroid explosion shakes the camera
+	explosionShakeRange = 300
+	// deathShake is the strength of the shake when a ship is destroyed
+	deathShake = 16
+	// maxShake is the strongest the shake can build up to
+	maxShake = 20
+	// shakeDecay scales the shake strength every frame, so it dies away quickly
+	shakeDecay = 0.88
+)
+
+// targetCameraZoom returns the zoom the camera should settle at for the current speed, zooming out as speed increases
+func (g *Game) targetCameraZoom() float64 {
+	progress := (g.speed - 1) / (cameraZoomSpeed - 1)
+	if progress < 0 {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+	return 1 - (1-minCameraZoom)*progress
+}
+
+// updateCamera eases the camera zoom toward the target zoom for the current speed
+func (g *Game) updateCamera() {
+	target := g.targetCameraZoom()
+	if g.cameraZoom == nil {
+		g.cameraZoom = NewWait(target, 0)
+	} else if g.cameraZoom.To != target {
+		g.cameraZoom = NewTween(g.cameraZoom.Value(), target, cameraZoomFrames, EaseInOutQuad)
+	}
+	g.cameraZoom.Update()
+}
+
+// addShake shakes the camera, adding to any shake already underway
+func (g *Game) addShake(strength float64) {
+	if g.settings != nil && !g.settings.ScreenShake {
+		return
+	}
+	g.shake = math.Min(g.shake+strength, maxShake)
+}
+
+// shakeFromExplosion shakes the camera for an explosion centered on a position, harder the closer it is to a ship
+func (g *Game) shakeFromExplosion(x, y float64) {
+	nearest := math.Inf(1)
+	for _, ship := range g.ships() {
+		shipX, shipY := spriteCenter(ship)
+		nearest = math.Min(nearest, math.Hypot(shipX-x, shipY-y))
+	}
+	if nearest < explosionShakeRange {
+		g.addShake(explosionShake * (1 - nearest/explosionShakeRange))
+	}
+}
+
+// updateShake moves the camera to a new random offset within the shake strength, then lets the shake die away
+func (g *Game) updateShake() {
+	if g.shake < 0.5 {
+		g.shake = 0
+		g.shakeX, g.shakeY = 0, 0
+		return
+	}
+
+	angle := rand.Float64() * 2 * math.Pi
+	g.shakeX = math.Cos(angle) * g.shake
+	g.shakeY = math.Sin(angle) * g.shake
+	g.shake *= shakeDecay
+}
+
+// camera returns the camera the world is drawn with during a run, offset by any shake
+func (g *Game) camera() Camera {
+	zoom := 1.0
+	if g.cameraZoom != nil {
+		zoom = g.cameraZoom.Value()
+	}
+	return Camera{X: g.shakeX, Y: g.shakeY, Zoom: zoom}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+)
+
+const (
+	// gameVersion is the current version of the game, used to decide when the "what's new" screen is shown
+	gameVersion = "1.2.0"
+)
+
+// ChangelogEntry represents the list of changes made in a single version of the game
+type ChangelogEntry struct {
+	// Version is the game version the changes were released in
+	Version string `json:"version"`
+	// Changes are short, player facing descriptions of each change
+	Changes []string `json:"changes"`
+}
+
+//go:embed changelog.json
+var changelogJSON []byte
+
+// changelog contains all changelog entries, newest first
+var changelog []ChangelogEntry
+
+// Parse the embedded changelog
+func init() {
+	if err := json.Unmarshal(changelogJSON, &changelog); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// currentChangelogEntry returns the changelog entry for the running game version, if there is one
+func currentChangelogEntry() (ChangelogEntry, bool) {
+	for _, entry := range changelog {
+		if entry.Version == gameVersion {
+			return entry, true
+		}
+	}
+	return ChangelogEntry{}, false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+)
+
+const (
+	// chaosEventFrames is how many frames a chaos event lasts
+	chaosEventFrames = 8 * 60
+	// chaosSpawnFrames is how many frames apart asteroids are telegraphed during a chaos event
+	chaosSpawnFrames = 40
+	// chaosTelegraphMeters is how many meters at the starting speed a telegraph warns before its asteroid enters
+	chaosTelegraphMeters = 60
+	// chaosWarningMeters is how many meters at the starting speed a chaos event is announced before it starts
+	chaosWarningMeters = 120
+	// chaosTelegraphLength is the length of the telegraph bar along the screen edge
+	chaosTelegraphLength = 90
+	// chaosTelegraphThickness is the thickness of the telegraph bar
+	chaosTelegraphThickness = 8
+)
+
+// screenEdge represents the edge of the screen a hazard enters from
+type screenEdge int
+
+const (
+	// edgeLeft is the left edge of the screen
+	edgeLeft screenEdge = iota
+	// edgeTop is the top edge of the screen
+	edgeTop
+)
+
+// chaosTelegraph warns where an asteroid will enter during a chaos event
+type chaosTelegraph struct {
+	// edge is the edge of the screen the asteroid enters from
+	edge screenEdge
+	// position is how far along the edge the asteroid enters
+	position int
+	// window is how long the telegraph warns before the asteroid enters
+	window telegraphWindow
+}
+
+// isChaosActive returns whether a chaos event is underway
+func (g *Game) isChaosActive() bool {
+	return g.frameCount < g.chaosEndFrame
+}
+
+// updateChaos starts a chaos event every ChaosEventInterval of the tuning, telegraphs asteroids entering from the left and top of the screen
+// while it lasts, and spawns them once their telegraph has warned for long enough
+func (g *Game) updateChaos() {
+	if g.distanceTravelled > g.chaosSpawnThreshold {
+		g.chaosEndFrame = g.frameCount + chaosEventFrames
+		g.chaosSpawnThreshold += tuning.ChaosEventInterval
+	}
+
+	if g.isChaosActive() && !g.isFreezeActive() && (g.chaosEndFrame-g.frameCount)%chaosSpawnFrames == 0 {
+		telegraph := &chaosTelegraph{edge: screenEdge(g.randIntn("chaos edge", 2)), window: g.newTelegraphWindow(chaosTelegraphMeters)}
+		if telegraph.edge == edgeLeft {
+			telegraph.position = chaosTelegraphLength + g.randIntn("chaos position", screenHeight-chaosTelegraphLength*2)
+		} else {
+			telegraph.position = screenWidth/4 + g.randIntn("chaos position", screenWidth*3/4-chaosTelegraphLength)
+		}
+		g.chaosTelegraphs = append(g.chaosTelegraphs, telegraph)
+	}
+
+	temp := g.chaosTelegraphs[:0]
+	for _, telegraph := range g.chaosTelegraphs {
+		if telegraph.window.isOver(g.distanceTravelled) {
+			g.spawn(SpawnAsteroid, g.generateChaosAsteroid(telegraph))
+		} else {
+			temp = append(temp, telegraph)
+		}
+	}
+	g.chaosTelegraphs = temp
+}
+
+// generateChaosAsteroid creates an asteroid just off the edge of the screen its telegraph warned of, heading into the screen
+func (g *Game) generateChaosAsteroid(telegraph *chaosTelegraph) *spriteutils.Sprite {
+	asteroid := g.generateSprite(g.asteroidFactory)
+	width, height := asteroid.Image.Size()
+	if telegraph.edge == edgeLeft {
+		asteroid.X = -width
+		asteroid.Y = telegraph.position - height/2
+		asteroid.ApplyImpulse(float64(g.randIntn("chaos impulse x", 4))+4, float64(g.randIntn("chaos impulse y", 3))-1)
+	} else {
+		asteroid.X = telegraph.position - width/2
+		asteroid.Y = -height
+		asteroid.ApplyImpulse(-g.speed, float64(g.randIntn("chaos impulse y", 3))+3)
+	}
+	return asteroid
+}
+
+// drawChaosTelegraphs draws a flashing bar on the edge of the screen where each telegraphed asteroid will enter.  When the HUD is
+// mirrored the bars are drawn along the opposite edges, at the same position along them
+func (g *Game) drawChaosTelegraphs(r Renderer) {
+	if (g.frameCount/8)%2 == 1 {
+		return
+	}
+
+	for _, telegraph := range g.chaosTelegraphs {
+		op := &Transform{}
+		if telegraph.edge == edgeLeft {
+			x := 0
+			if g.isHUDMirrored() {
+				x = screenWidth - chaosTelegraphThickness
+			}
+			op.Scale(chaosTelegraphThickness, chaosTelegraphLength)
+			op.Translate(float64(x), float64(telegraph.position-chaosTelegraphLength/2))
+		} else {
+			y := 0
+			if g.isHUDMirrored() {
+				y = screenHeight - chaosTelegraphThickness
+			}
+			op.Scale(chaosTelegraphLength, chaosTelegraphThickness)
+			op.Translate(float64(telegraph.position-chaosTelegraphLength/2), float64(y))
+		}
+		op.ScaleColor(1, 0.25, 0.15, 0.9)
+		r.DrawImage(g.assets.Image(imageBeam), op)
+	}
+}
+
+// drawChaosBanner announces a chaos event shortly before it starts and while it lasts
+func (g *Game) drawChaosBanner(r Renderer) {
+	banner := "CHAOS!"
+	if !g.isChaosActive() {
+		if g.chaosSpawnThreshold-g.distanceTravelled > g.telegraphDistance(chaosWarningMeters) {
+			return
+		}
+		banner = "CHAOS INCOMING!"
+	}
+
+	theme := g.theme()
+	r.DrawText(banner, g.fonts().Normal, (screenWidth-len(banner)*g.ui(fontSize)/2)/2, g.ui(fontSize)*4, theme.Accent())
+}
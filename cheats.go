@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cheatKeysFileName is the name of the file in the profile directory that remaps the cheat keys
+const cheatKeysFileName = "cheatkeys.json"
+
+// cheatAction represents a developer cheat that can be bound to a key
+type cheatAction string
+
+const (
+	// cheatInvulnerable toggles invulnerability, nothing ends the run while it is on
+	cheatInvulnerable cheatAction = "invulnerable"
+	// cheatSpawnAsteroid spawns an asteroid
+	cheatSpawnAsteroid cheatAction = "spawnAsteroid"
+	// cheatSpawnStar spawns a star
+	cheatSpawnStar cheatAction = "spawnStar"
+	// cheatSpawnFreeze spawns a freeze pickup
+	cheatSpawnFreeze cheatAction = "spawnFreeze"
+	// cheatSpawnRing spawns an asteroid ring
+	cheatSpawnRing cheatAction = "spawnRing"
+	// cheatFrameStepping toggles frame stepping, the run only advances one frame at a time while it is on
+	cheatFrameStepping cheatAction = "frameStepping"
+	// cheatStepFrame advances the run by one frame while frame stepping
+	cheatStepFrame cheatAction = "stepFrame"
+	// cheatStressTest spawns hundreds of asteroids at once and logs how long updates and draws take
+	cheatStressTest cheatAction = "stressTest"
+)
+
+// defaultCheatKeys are the keys each cheat is bound to unless remapped
+var defaultCheatKeys = map[cheatAction]ebiten.Key{
+	cheatInvulnerable:  ebiten.KeyF5,
+	cheatSpawnAsteroid: ebiten.KeyF6,
+	cheatSpawnStar:     ebiten.KeyF7,
+	cheatSpawnFreeze:   ebiten.KeyF8,
+	cheatSpawnRing:     ebiten.KeyF9,
+	cheatFrameStepping: ebiten.KeyF10,
+	cheatStepFrame:     ebiten.KeyPeriod,
+	cheatStressTest:    ebiten.KeyF11,
+}
+
+// cheats represents the developer cheats, which are only enabled in debug builds run with the -dev flag
+type cheats struct {
+	// enabled represents whether the cheats can be used
+	enabled bool
+	// keys are the keys each cheat is bound to
+	keys map[cheatAction]ebiten.Key
+	// invulnerable represents whether invulnerability is on
+	invulnerable bool
+	// frameStepping represents whether frame stepping is on
+	frameStepping bool
+}
+
+// loadCheats enables the cheats if the game is a debug build run with the -dev flag, binding them to the default keys
+// and any remapped in the cheat keys file.  Release builds never enable them
+func loadCheats() (*cheats, error) {
+	c := &cheats{keys: map[cheatAction]ebiten.Key{}}
+	for action, key := range defaultCheatKeys {
+		c.keys[action] = key
+	}
+	if !debugBuild || !*devFlag {
+		return c, nil
+	}
+	c.enabled = true
+
+	dir, err := profileDir()
+	if err != nil {
+		return c, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, cheatKeysFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+
+	var remapped map[cheatAction]string
+	if err := json.Unmarshal(data, &remapped); err != nil {
+		return c, err
+	}
+	for action, name := range remapped {
+		key, ok := keyByName(name)
+		if _, known := defaultCheatKeys[action]; !known || !ok {
+			err = fmt.Errorf("cheat keys: cannot bind %q to %q", action, name)
+			continue
+		}
+		c.keys[action] = key
+	}
+	return c, err
+}
+
+// keyByName returns the key with a name, such as "F5" or "Period", ignoring case
+func keyByName(name string) (ebiten.Key, bool) {
+	for key := ebiten.Key(0); key <= ebiten.KeyMax; key++ {
+		if strings.EqualFold(key.String(), name) {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// isCheatJustPressed returns whether the key bound to a cheat was just pressed
+func (g *Game) isCheatJustPressed(action cheatAction) bool {
+	return g.input.IsKeyJustPressed(g.cheats.keys[action])
+}
+
+// updateCheats handles the cheat keys during a run, marking the run as cheated when one is used.  It returns whether
+// the run should hold on this frame because frame stepping is on and no step was asked for
+func (g *Game) updateCheats() bool {
+	c := g.cheats
+	if !c.enabled {
+		return false
+	}
+
+	used := true
+	switch {
+	case g.isCheatJustPressed(cheatInvulnerable):
+		c.invulnerable = !c.invulnerable
+	case g.isCheatJustPressed(cheatSpawnAsteroid):
+		g.spawn(SpawnAsteroid, g.generateAsteroid())
+	case g.isCheatJustPressed(cheatSpawnStar):
+		g.spawnPickup(starPowerUp, g.generateSprite(starPowerUp.Factory))
+	case g.isCheatJustPressed(cheatSpawnFreeze) && freezePowerUp != nil:
+		g.spawnPickup(freezePowerUp, g.generateSprite(freezePowerUp.Factory))
+	case g.isCheatJustPressed(cheatSpawnRing):
+		g.spawn(SpawnRing, g.generateRingCore())
+	case g.isCheatJustPressed(cheatFrameStepping):
+		c.frameStepping = !c.frameStepping
+	case g.isCheatJustPressed(cheatStressTest):
+		g.startStressTest()
+	default:
+		used = false
+	}
+	if used {
+		g.isCheated = true
+	}
+
+	return c.frameStepping && !g.isCheatJustPressed(cheatStepFrame)
+}
+
+// applyInvulnerability keeps the run going if it just ended while invulnerability is on
+func (g *Game) applyInvulnerability() {
+	if g.cheats.invulnerable && g.mode == ModeGameOver {
+		g.mode = ModeGame
+	}
+}
+
+// drawCheats draws which cheats are on at the bottom left of the screen
+func (g *Game) drawCheats(r Renderer) {
+	if !g.cheats.enabled {
+		return
+	}
+
+	status := "DEV"
+	if g.cheats.invulnerable {
+		status += " INVULNERABLE"
+	}
+	if g.cheats.frameStepping {
+		status += fmt.Sprintf(" STEPPING (%s)", g.cheats.keys[cheatStepFrame])
+	}
+	theme := g.theme()
+	r.DrawText(status, g.fonts().Small, g.ui(smallFontSize), screenHeight-g.ui(smallFontSize), theme.Accent())
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	// imageCoin is the key of the coin pickup
+	imageCoin = "coin"
+	// coinSize is the width and height of a coin
+	coinSize = 24
+	// coinRimWidth is the width of the darker rim around the edge of a coin
+	coinRimWidth = 3
+	// maxWalletCoins is the most coins the wallet can hold, deposits beyond it are lost
+	maxWalletCoins = 1000000
+)
+
+// loadCoinImage creates the coin, a gold disc with a darker rim
+func (a *Assets) loadCoinImage() error {
+	img := image.NewRGBA(image.Rect(0, 0, coinSize, coinSize))
+	center := float64(coinSize-1) / 2
+	for y := 0; y < coinSize; y++ {
+		for x := 0; x < coinSize; x++ {
+			distance := math.Hypot(float64(x)-center, float64(y)-center)
+			switch {
+			case distance > coinSize/2:
+				continue
+			case distance > coinSize/2-coinRimWidth:
+				img.Set(x, y, color.RGBA{0xb3, 0x80, 0x1a, 0xff})
+			default:
+				img.Set(x, y, color.RGBA{0xff, 0xd2, 0x3f, 0xff})
+			}
+		}
+	}
+
+	coin, err := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+	if err != nil {
+		return err
+	}
+	a.images[imageCoin] = coin
+	return nil
+}
+
+// coinEffect is the effect of a coin, counted toward the coins banked in the wallet when the run ends
+type coinEffect struct{}
+
+// Apply counts the coin
+func (coinEffect) Apply(g *Game) {
+	g.coinsCollected++
+	g.audio.play(soundTick)
+}
+
+// Expire does nothing, the coin is kept
+func (coinEffect) Expire(g *Game) {}
+
+// coinsBanked returns how many of the coins collected in the run go in the wallet.  Coins buy upgrades, so runs played with
+// cheats, a tuning file or a mod don't bank any
+func (g *Game) coinsBanked() int64 {
+	if g.isCheated || isTuned || isModded {
+		return 0
+	}
+	return int64(g.coinsCollected)
+}
+
+// depositCoins adds coins to the wallet, capped at maxWalletCoins
+func (w *Wallet) depositCoins(coins int64) {
+	if coins <= 0 {
+		return
+	}
+	if coins > maxWalletCoins-w.Coins {
+		w.Coins = maxWalletCoins
+	} else {
+		w.Coins += coins
+	}
+	w.updateChecksum()
+}
+
+// spendCoins takes coins out of the wallet, returning false and leaving the wallet as it is if there aren't enough
+func (w *Wallet) spendCoins(coins int64) bool {
+	if coins < 0 || coins > w.Coins {
+		return false
+	}
+	w.Coins -= coins
+	w.updateChecksum()
+	return true
+}
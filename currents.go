@@ -0,0 +1,186 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+const (
+	// imageCurrentStreak is the key of the thin streak the flow of a current is shown with
+	imageCurrentStreak = "currentStreak"
+	// currentStreakWidth is the width of a current streak
+	currentStreakWidth = 2
+	// currentStreakLength is the length of a current streak
+	currentStreakLength = 18
+	// currentFirstDistance is the distance of the first current
+	currentFirstDistance = 2000
+	// currentSpawnMinInterval is the shortest distance between currents
+	currentSpawnMinInterval = 2500
+	// currentSpawnMaxInterval is the longest distance between currents
+	currentSpawnMaxInterval = 4500
+	// currentMinColumnWidth is the narrowest an updraft or downdraft is
+	currentMinColumnWidth = 250
+	// currentMaxColumnWidth is the widest an updraft or downdraft is
+	currentMaxColumnWidth = 450
+	// currentMinBandWidth is the shortest a band of light or heavy gravity is
+	currentMinBandWidth = 600
+	// currentMaxBandWidth is the longest a band of light or heavy gravity is
+	currentMaxBandWidth = 900
+	// currentBandHeight is the height of a band of light or heavy gravity
+	currentBandHeight = 240
+	// currentBandMargin is the closest a band of light or heavy gravity comes to the top or bottom of the screen
+	currentBandMargin = 100
+	// currentPush is how much vertical velocity an updraft or downdraft adds to a ship each frame
+	currentPush = 0.15
+	// currentLightGravity is how much of its usual gravity a ship feels in a band of light gravity
+	currentLightGravity = 0.3
+	// currentHeavyGravity is how much of its usual gravity a ship feels in a band of heavy gravity
+	currentHeavyGravity = 1.8
+	// currentStreakSpeed is how fast the streaks of a current flow
+	currentStreakSpeed = 6
+	// currentStreakFrames is how many frames a current streak lasts
+	currentStreakFrames = 30
+	// currentStreakChance is the chance out of 10 that a current emits a streak each frame
+	currentStreakChance = 6
+)
+
+// currentKind represents the types of current that push the ship around
+type currentKind int
+
+const (
+	// currentUpdraft is a column of the screen that pushes ships up
+	currentUpdraft currentKind = iota
+	// currentDowndraft is a column of the screen that pushes ships down
+	currentDowndraft
+	// currentLight is a band across the screen where ships feel less gravity
+	currentLight
+	// currentHeavy is a band across the screen where ships feel more gravity
+	currentHeavy
+	// currentKindCount is the number of types of current
+	currentKindCount
+)
+
+// currentTints are the colors the region and streaks of each type of current are drawn in
+var currentTints = map[currentKind]color.RGBA{
+	currentUpdraft:   {0x80, 0xe6, 0xff, 0xff},
+	currentDowndraft: {0x80, 0xe6, 0xff, 0xff},
+	currentLight:     {0xb3, 0xff, 0xb3, 0xff},
+	currentHeavy:     {0xcc, 0x80, 0xff, 0xff},
+}
+
+// current is a region of the world that pushes ships inside it up or down, or changes how strongly gravity pulls them,
+// scrolling along with the world
+type current struct {
+	// kind is the type of the current
+	kind currentKind
+	// x is the left of the region
+	x float64
+	// y is the top of the region
+	y float64
+	// width is the width of the region
+	width float64
+	// height is the height of the region
+	height float64
+}
+
+// contains returns whether a point is inside the current's region
+func (c *current) contains(x, y float64) bool {
+	return x >= c.x && x < c.x+c.width && y >= c.y && y < c.y+c.height
+}
+
+// loadCurrentImages creates the streak currents are shown flowing with
+func (a *Assets) loadCurrentImages() error {
+	return a.fillImage(imageCurrentStreak, currentStreakWidth, currentStreakLength, color.White)
+}
+
+// spawnCurrent adds a new current just off the right of the screen: an updraft or downdraft the height of the screen, or a
+// band of light or heavy gravity somewhere across it
+func (g *Game) spawnCurrent() {
+	c := &current{kind: currentKind(g.randIntn("current kind", int(currentKindCount))), x: float64(screenWidth + offScreenMarginX())}
+	switch c.kind {
+	case currentUpdraft, currentDowndraft:
+		c.width = float64(currentMinColumnWidth + g.randIntn("current width", currentMaxColumnWidth-currentMinColumnWidth+1))
+		c.height = float64(screenHeight)
+	default:
+		c.width = float64(currentMinBandWidth + g.randIntn("current width", currentMaxBandWidth-currentMinBandWidth+1))
+		c.height = currentBandHeight
+		c.y = float64(currentBandMargin + g.randIntn("current y", screenHeight-currentBandHeight-currentBandMargin*2+1))
+	}
+	g.currents = append(g.currents, c)
+}
+
+// updateCurrents scrolls the currents along with the world, drops the ones that have left the screen and emits the
+// streaks that show which way the rest flow
+func (g *Game) updateCurrents() {
+	temp := g.currents[:0]
+	for _, c := range g.currents {
+		c.x -= g.speed
+		if c.x+c.width < 0 {
+			continue
+		}
+		temp = append(temp, c)
+		if c.x < float64(screenWidth) && rand.Intn(10) < currentStreakChance {
+			g.emitCurrentStreak(c)
+		}
+	}
+	for i := len(temp); i < len(g.currents); i++ {
+		g.currents[i] = nil
+	}
+	g.currents = temp
+}
+
+// emitCurrentStreak emits a streak somewhere inside a current, flowing up or down for an updraft or downdraft and
+// across for a band, so the kind of current can be read from a distance
+func (g *Game) emitCurrentStreak(c *current) {
+	streak := g.particles.emit(g.assets.Image(imageCurrentStreak), c.x+rand.Float64()*c.width, c.y+rand.Float64()*c.height, currentStreakFrames)
+	switch c.kind {
+	case currentUpdraft:
+		streak.yVelocity = -currentStreakSpeed
+	case currentDowndraft:
+		streak.yVelocity = currentStreakSpeed
+	case currentLight:
+		// Light gravity drifts lazily, heavy gravity rushes
+		streak.xVelocity = -currentStreakSpeed / 2
+		streak.rotation = math.Pi / 2
+	case currentHeavy:
+		streak.xVelocity = -currentStreakSpeed * 1.5
+		streak.rotation = math.Pi / 2
+	}
+	tint := currentTints[c.kind]
+	streak.red, streak.green, streak.blue = float64(tint.R)/0xff, float64(tint.G)/0xff, float64(tint.B)/0xff
+	streak.scrolls = true
+}
+
+// applyCurrents pushes a ship inside an updraft or downdraft, and adds or takes away some of its gravity inside a band
+func (g *Game) applyCurrents(ship *spriteutils.Sprite) {
+	x, y := spriteCenter(ship)
+	for _, c := range g.currents {
+		if !c.contains(x, y) {
+			continue
+		}
+		switch c.kind {
+		case currentUpdraft:
+			ship.YVelocity -= currentPush
+		case currentDowndraft:
+			ship.YVelocity += currentPush
+		case currentLight:
+			ship.YVelocity += tuning.ShipGravity * g.gravityDirection() * (currentLightGravity - 1)
+		case currentHeavy:
+			ship.YVelocity += tuning.ShipGravity * g.gravityDirection() * (currentHeavyGravity - 1)
+		}
+	}
+}
+
+// drawCurrents tints the region of each current faintly, so the streaks have an edge to flow within
+func (g *Game) drawCurrents(r Renderer) {
+	for _, c := range g.currents {
+		tint := currentTints[c.kind]
+		op := &Transform{}
+		op.Scale(c.width, c.height)
+		op.Translate(c.x, c.y)
+		op.ScaleColor(float64(tint.R)/0xff, float64(tint.G)/0xff, float64(tint.B)/0xff, 0.08)
+		r.DrawImage(g.assets.Image(imageBeam), op)
+	}
+}
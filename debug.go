@@ -0,0 +1,12 @@
+//go:build debug
+// +build debug
+
+package main
+
+import "flag"
+
+// debugBuild represents whether the game was built with the debug build tag
+const debugBuild = true
+
+// devFlag represents whether the developer cheats are enabled with the -dev flag
+var devFlag = flag.Bool("dev", false, "enable the developer cheat keys")
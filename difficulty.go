@@ -0,0 +1,83 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"log"
+)
+
+const (
+	// difficultyKey is the key that cycles through the difficulties on the title screen
+	difficultyKey = ebiten.KeyD
+)
+
+// Difficulty represents a preset of how hard a run is
+type Difficulty struct {
+	// Name is the name of the difficulty, shown to the player and saved in the profile
+	Name string `json:"name"`
+	// StartSpeed is the speed a run starts at, before any launch boost
+	StartSpeed float64 `json:"startSpeed"`
+	// SpeedIncreaseDistance is the distance of the first speed increase
+	SpeedIncreaseDistance int `json:"speedIncreaseDistance"`
+	// SpeedIncreaseGrowth is how much further away each speed increase is than the one before
+	SpeedIncreaseGrowth float64 `json:"speedIncreaseGrowth"`
+	// SpireSpawnInterval is the distance between spires
+	SpireSpawnInterval int `json:"spireSpawnInterval"`
+	// AsteroidSpawnInterval is the distance between asteroids
+	AsteroidSpawnInterval int `json:"asteroidSpawnInterval"`
+	// BoostFrames is how many frames the speed boost and shield from a star last
+	BoostFrames int64 `json:"boostFrames"`
+	// HeatPerFrame is how much engine heat each frame of thrust builds, from 0 to 1 when it overheats.  0 leaves the engine heat mechanic out
+	HeatPerFrame float64 `json:"heatPerFrame"`
+	// CoolingPerFrame is how much engine heat dissipates each frame without thrust
+	CoolingPerFrame float64 `json:"coolingPerFrame"`
+	// OscillatingSpireChance is the chance out of 10 that a spire moves up and down.  0 leaves oscillating spires out
+	OscillatingSpireChance int `json:"oscillatingSpireChance,omitempty"`
+}
+
+// difficulty returns the difficulty selected in the profile, or the default difficulty if none is selected
+func (g *Game) difficulty() *Difficulty {
+	for i := range tuning.Difficulties {
+		if tuning.Difficulties[i].Name == g.profile.Difficulty {
+			return &tuning.Difficulties[i]
+		}
+	}
+	return tuning.defaultDifficulty()
+}
+
+// updateDifficulty cycles to the next difficulty when its key is pressed on the title screen, remembering the choice in the profile
+func (g *Game) updateDifficulty() {
+	if !g.input.IsKeyJustPressed(difficultyKey) {
+		return
+	}
+
+	current := g.difficulty()
+	for i := range tuning.Difficulties {
+		if &tuning.Difficulties[i] == current {
+			g.profile.Difficulty = tuning.Difficulties[(i+1)%len(tuning.Difficulties)].Name
+			break
+		}
+	}
+
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
+	}
+
+	// The title preview shares the profile, so it plays the new difficulty from the start too
+	g.resetGame()
+	g.titlePreview.resetGame()
+}
+
+// powerUpDuration returns how many frames a power-up lasts, the star's boost lasting as long as the difficulty and upgrades allow
+func (g *Game) powerUpDuration(powerUp *PowerUp) int64 {
+	if _, ok := powerUp.Effect.(boostEffect); ok {
+		return int64(float64(g.difficulty().BoostFrames) * g.upgradeFactor(shopShieldBattery, 1.25))
+	}
+	return powerUp.Duration
+}
+
+// drawDifficultyHint draws the selected difficulty and how to change it at the bottom of the title screen
+func (g *Game) drawDifficultyHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'D' KEY TO CHANGE DIFFICULTY: " + g.difficulty().Name
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*6, theme.Text())
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// directorRunsTracked is how many of the most recent runs the director judges the player by
+	directorRunsTracked = 5
+	// directorReferenceDistance is the run distance at which the director applies the most pressure
+	directorReferenceDistance = 3000
+	// directorNewPlayerPressure is the pressure applied to players with no finished runs
+	directorNewPlayerPressure = 0.85
+	// minPressure is the gentlest the director makes a run
+	minPressure = 0.75
+	// maxPressure is the hardest the director makes a run
+	maxPressure = 1.25
+	// nearMissRadius is how close the center of an asteroid has to pass to the center of a ship to count as a near-miss
+	nearMissRadius = 110
+	// nearMissWindowFrames is how many frames a near-miss eases the pressure for
+	nearMissWindowFrames = 10 * 60
+	// nearMissRelief is how much each recent near-miss eases the pressure
+	nearMissRelief = 0.05
+	// maxNearMissRelief is the most recent near-misses can ease the pressure
+	maxNearMissRelief = 0.2
+	// spireRetreatRange is how far spires are pulled back from the gap at the lowest pressure, and pushed into it at the highest
+	spireRetreatRange = 100
+)
+
+// resetDirector judges the player by their recent runs to choose the pressure the run starts at.  New players and players
+// dying early get a gentler run, players going far get pressure sooner.  The title preview always spawns as for a new player
+func (g *Game) resetDirector() {
+	g.basePressure = directorNewPlayerPressure
+	if recent := g.profile.RecentDistances; len(recent) > 0 && !g.isPreview {
+		total := 0
+		for _, distance := range recent {
+			total += distance
+		}
+		skill := math.Min(float64(total)/float64(len(recent))/directorReferenceDistance, 1)
+		g.basePressure = minPressure + (maxPressure-minPressure)*skill
+	}
+	g.pressure = g.basePressure
+	g.nearMissFrames = nil
+	g.nearMissAsteroids = nil
+}
+
+// updateDirector counts asteroids passing close to a ship, easing the pressure for a while after each near-miss
+func (g *Game) updateDirector() {
+	for _, asteroid := range g.entitySprites(entityAsteroid) {
+		if g.nearMissAsteroids[asteroid] {
+			continue
+		}
+		for _, ship := range g.ships() {
+			if isWithinRadius(ship, asteroid, nearMissRadius) {
+				if g.nearMissAsteroids == nil {
+					g.nearMissAsteroids = map[*spriteutils.Sprite]bool{}
+				}
+				g.nearMissAsteroids[asteroid] = true
+				g.nearMissFrames = append(g.nearMissFrames, g.frameCount)
+				break
+			}
+		}
+	}
+
+	temp := g.nearMissFrames[:0]
+	for _, frame := range g.nearMissFrames {
+		if g.frameCount-frame < nearMissWindowFrames {
+			temp = append(temp, frame)
+		}
+	}
+	g.nearMissFrames = temp
+
+	relief := math.Min(float64(len(g.nearMissFrames))*nearMissRelief, maxNearMissRelief)
+	g.pressure = math.Max(minPressure, g.basePressure-relief)
+}
+
+// recordRecentDistance remembers the distance of a finished run, so the director can judge the next runs by it
+func (g *Game) recordRecentDistance() {
+	g.profile.RecentDistances = append(g.profile.RecentDistances, g.distanceTravelled)
+	if len(g.profile.RecentDistances) > directorRunsTracked {
+		g.profile.RecentDistances = g.profile.RecentDistances[len(g.profile.RecentDistances)-directorRunsTracked:]
+	}
+}
+
+// asteroidSpawnInterval returns the distance to the next asteroid, closer together under more pressure
+func (g *Game) asteroidSpawnInterval() int {
+	return int(float64(g.difficulty().AsteroidSpawnInterval) * g.stage().asteroidSpawnFactor / g.pressure)
+}
+
+// speedIncreaseGrowth returns how much further away the next speed increase is, sooner under more pressure
+func (g *Game) speedIncreaseGrowth() float64 {
+	return 1 + (g.difficulty().SpeedIncreaseGrowth-1)/g.pressure
+}
+
+// spireRetreat returns how far spires are pulled back from the gap between them, negative to push them into it under more pressure
+func (g *Game) spireRetreat() int {
+	return int((1 - g.pressure) / (1 - minPressure) * spireRetreatRange)
+}
@@ -0,0 +1,74 @@
+package main
+
+import "github.com/llrowat/spriteutils"
+
+const (
+	// twinSpireOffset is how far behind a spire its counterpart in the other half of the screen spawns
+	twinSpireOffset = 300
+)
+
+// ships returns every ship the player is controlling
+func (g *Game) ships() []*spriteutils.Sprite {
+	if g.twinShip != nil {
+		return []*spriteutils.Sprite{g.ship, g.twinShip}
+	}
+	return []*spriteutils.Sprite{g.ship}
+}
+
+// shields returns the shields of every ship that currently has one
+func (g *Game) shields() []*spriteutils.Sprite {
+	var shields []*spriteutils.Sprite
+	if g.shield != nil {
+		shields = append(shields, g.shield)
+	}
+	if g.twinShield != nil {
+		shields = append(shields, g.twinShield)
+	}
+	return shields
+}
+
+// isShipColliding returns whether any ship is colliding with a sprite
+func (g *Game) isShipColliding(sprite *spriteutils.Sprite) bool {
+	for _, ship := range g.ships() {
+		if ship.IsColliding(sprite) && isTouchingHull(ship, sprite) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShieldColliding returns whether any shield is colliding with a sprite
+func (g *Game) isShieldColliding(sprite *spriteutils.Sprite) bool {
+	for _, shield := range g.shields() {
+		if shield.IsColliding(sprite) && isTouchingHull(shield, sprite) {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnForTwin mirrors spires and pickups into the other half of the screen in the dual ship variant, so both ships face the same density of hazards
+func (g *Game) spawnForTwin(event *SpawnEvent) {
+	if g.variant != VariantDual || event.Sprite == nil {
+		return
+	}
+
+	switch event.Kind {
+	case SpawnSpire:
+		_, height := event.Sprite.Image.Size()
+		spire := g.generateSpire(event.Sprite.Y+height/2 > screenHeight/2)
+		spire.X += twinSpireOffset
+		event.Added = append(event.Added, Spawn{Kind: SpawnSpire, Sprite: spire})
+	case SpawnPickup:
+		if event.PowerUp == nil {
+			return
+		}
+		pickup := g.generateSprite(event.PowerUp.Factory)
+		if event.Sprite.Y < screenHeight/2 {
+			pickup.Y = event.Sprite.Y + screenHeight/2
+		} else {
+			pickup.Y = event.Sprite.Y - screenHeight/2
+		}
+		event.Added = append(event.Added, Spawn{Kind: SpawnPickup, Sprite: pickup, PowerUp: event.PowerUp})
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+)
+
+// entityType represents a type of entity that can be saved, such as in a replay
+type entityType string
+
+const (
+	// entityShip is a player ship
+	entityShip entityType = "ship"
+	// entityShield is a ship's shield
+	entityShield entityType = "shield"
+	// entityGround is a ground tile
+	entityGround entityType = "ground"
+	// entityWater is a water tile
+	entityWater entityType = "water"
+	// entitySpire is a spire
+	entitySpire entityType = "spire"
+	// entityAsteroid is an asteroid, including ring asteroids
+	entityAsteroid entityType = "asteroid"
+	// entityExplosion is an asteroid explosion
+	entityExplosion entityType = "explosion"
+	// entityPickup is a power-up pickup
+	entityPickup entityType = "pickup"
+	// entityProjectile is a laser projectile
+	entityProjectile entityType = "projectile"
+	// entityBoss is the giant asteroid boss
+	entityBoss entityType = "boss"
+	// entityBolt is a bolt fired by the boss or a UFO
+	entityBolt entityType = "bolt"
+	// entityUFO is a UFO enemy
+	entityUFO entityType = "ufo"
+	// entityParticle is a plain particle, such as a spark or flame.  Particles drawn with another entity's image are saved as that entity
+	entityParticle entityType = "particle"
+)
+
+// savedEntity is the saved form of a single entity
+type savedEntity struct {
+	// Type is the type of the entity, empty in saves from before entity types were recorded
+	Type entityType `json:"type,omitempty"`
+	// Image is the name of the image the entity is drawn with
+	Image    string  `json:"image"`
+	X        int     `json:"x"`
+	Y        int     `json:"y"`
+	Rotation float64 `json:"rotation"`
+}
+
+// entityCodec converts one type of entity to and from its saved form
+type entityCodec struct {
+	// matches returns whether a sprite is an entity of the type
+	matches func(sprite *spriteutils.Sprite) bool
+	// encode returns the saved form of an entity of the type
+	encode func(sprite *spriteutils.Sprite) savedEntity
+	// decode restores an entity of the type from its saved form
+	decode func(saved savedEntity) (*spriteutils.Sprite, error)
+}
+
+var (
+	// entityCodecs are the codecs of every registered entity type
+	entityCodecs = map[entityType]*entityCodec{}
+	// entityTypeOrder are the registered entity types in the order they were registered, which is the order sprites are matched in
+	entityTypeOrder []entityType
+)
+
+// registerEntity registers the codec of an entity type, so it can be saved and restored everywhere entities are persisted
+func registerEntity(kind entityType, codec *entityCodec) {
+	if _, ok := entityCodecs[kind]; !ok {
+		entityTypeOrder = append(entityTypeOrder, kind)
+	}
+	entityCodecs[kind] = codec
+}
+
+// newImageEntityCodec creates a codec for an entity type told apart by the images it is drawn with, saving the image by name
+func newImageEntityCodec(kind entityType, images map[string]*ebiten.Image) *entityCodec {
+	names := map[*ebiten.Image]string{}
+	for name, image := range images {
+		names[image] = name
+	}
+
+	return &entityCodec{
+		matches: func(sprite *spriteutils.Sprite) bool {
+			_, ok := names[sprite.Image]
+			return ok
+		},
+		encode: func(sprite *spriteutils.Sprite) savedEntity {
+			return savedEntity{Type: kind, Image: names[sprite.Image], X: sprite.X, Y: sprite.Y, Rotation: sprite.Rotation}
+		},
+		decode: func(saved savedEntity) (*spriteutils.Sprite, error) {
+			image, ok := images[saved.Image]
+			if !ok {
+				return nil, fmt.Errorf("%s entity has unknown image %q", kind, saved.Image)
+			}
+			return &spriteutils.Sprite{Image: image, X: saved.X, Y: saved.Y, Rotation: saved.Rotation}, nil
+		},
+	}
+}
+
+// initializeEntityTypes registers every entity type with the loaded images
+func initializeEntityTypes(assets *Assets) {
+	registerEntity(entityShip, newImageEntityCodec(entityShip, map[string]*ebiten.Image{
+		"spaceship":       assets.Image(imageShip),
+		"spaceship_grown": scaledImage(assets.Image(imageShip), growScale),
+	}))
+	registerEntity(entityShield, newImageEntityCodec(entityShield, map[string]*ebiten.Image{
+		"shield":       assets.Image(imageShield),
+		"shield_grown": scaledImage(assets.Image(imageShield), growScale),
+	}))
+	registerEntity(entityGround, newImageEntityCodec(entityGround, map[string]*ebiten.Image{"groundDirt": assets.Image(imageFloor)}))
+	registerEntity(entityWater, newImageEntityCodec(entityWater, map[string]*ebiten.Image{"water": assets.Image(imageWater)}))
+
+	// Each stage's copies of the spires and big asteroids are saved under the stage's prefix, asteroids of each size
+	// class under the size's name, and the fragments of a split asteroid as the asteroid they came from and how many times it has split
+	spires := map[string]*ebiten.Image{}
+	asteroids := map[string]*ebiten.Image{"meteorBrown_small": assets.Image(imageSmallAsteroid)}
+	for _, stage := range stages {
+		spires[stage.prefix+"rock-top"] = assets.Image(stage.prefix + imageTopSpire)
+		spires[stage.prefix+"rock-bottom"] = assets.Image(stage.prefix + imageBottomSpire)
+		spires[stage.prefix+"rock-top-ledge"] = assets.Image(stage.prefix + imageTopLedgeSpire)
+		spires[stage.prefix+"rock-bottom-ledge"] = assets.Image(stage.prefix + imageBottomLedgeSpire)
+		for i, key := range asteroidImageKeys {
+			for _, size := range asteroidSizes {
+				name := fmt.Sprintf("%smeteorBrown_big%d%s", stage.prefix, i+1, size.name)
+				asteroids[name] = scaledImage(assets.Image(stage.prefix+key), size.scale)
+				for j, fragment := range fragmentImages(asteroids[name]) {
+					asteroids[fmt.Sprintf("%s_fragment%d", name, j+1)] = fragment
+				}
+			}
+		}
+	}
+	registerEntity(entitySpire, newImageEntityCodec(entitySpire, spires))
+	registerEntity(entityAsteroid, newImageEntityCodec(entityAsteroid, asteroids))
+	registerEntity(entityExplosion, newImageEntityCodec(entityExplosion, map[string]*ebiten.Image{"meteorExplosion": assets.Image(imageAsteroidExplosion)}))
+	registerEntity(entityPickup, newImageEntityCodec(entityPickup, map[string]*ebiten.Image{
+		"starGold":  assets.Image(imageStar),
+		"starFrost": assets.Image(imageFreezePickup),
+		"starGrow":  assets.Image(imageGrowPickup),
+		"coin":      assets.Image(imageCoin),
+	}))
+	registerEntity(entityProjectile, newImageEntityCodec(entityProjectile, map[string]*ebiten.Image{"laser": assets.Image(imageLaser)}))
+	registerEntity(entityBoss, newImageEntityCodec(entityBoss, map[string]*ebiten.Image{"boss": assets.Image(imageBoss)}))
+	registerEntity(entityBolt, newImageEntityCodec(entityBolt, map[string]*ebiten.Image{"bossBolt": assets.Image(imageBossBolt)}))
+	registerEntity(entityUFO, newImageEntityCodec(entityUFO, map[string]*ebiten.Image{"ufo": assets.Image(imageUFO)}))
+	registerEntity(entityParticle, newImageEntityCodec(entityParticle, map[string]*ebiten.Image{"spark": assets.Image(imageBeam)}))
+}
+
+// encodeEntity returns the saved form of a sprite, using the codec of the first registered entity type it matches
+func encodeEntity(sprite *spriteutils.Sprite) (savedEntity, error) {
+	for _, kind := range entityTypeOrder {
+		if codec := entityCodecs[kind]; codec.matches(sprite) {
+			return codec.encode(sprite), nil
+		}
+	}
+	return savedEntity{}, fmt.Errorf("sprite at %d, %d is not a registered entity type", sprite.X, sprite.Y)
+}
+
+// decodeEntity restores a sprite from its saved form.  Saves without a type are tried against every registered entity type
+func decodeEntity(saved savedEntity) (*spriteutils.Sprite, error) {
+	if saved.Type != "" {
+		codec, ok := entityCodecs[saved.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown entity type %q", saved.Type)
+		}
+		return codec.decode(saved)
+	}
+
+	for _, kind := range entityTypeOrder {
+		if sprite, err := entityCodecs[kind].decode(saved); err == nil {
+			return sprite, nil
+		}
+	}
+	return nil, fmt.Errorf("no entity type has image %q", saved.Image)
+}
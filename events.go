@@ -0,0 +1,75 @@
+package main
+
+// EventKind represents the things that happen during a run that other parts of the game react to
+type EventKind int
+
+const (
+	// EventShipDied is published when a run ends with a ship destroyed
+	EventShipDied EventKind = iota
+	// EventAsteroidDestroyed is published when an asteroid is destroyed, carrying what destroyed it
+	EventAsteroidDestroyed
+	// EventStarCollected is published when a star is picked up
+	EventStarCollected
+	// EventSpeedIncreased is published when the world speeds up as the run goes on
+	EventSpeedIncreased
+	// EventBossDefeated is published when a boss is destroyed
+	EventBossDefeated
+	// EventUFODestroyed is published when a UFO is destroyed
+	EventUFODestroyed
+)
+
+// Event is passed to every handler subscribed to its kind
+type Event struct {
+	// Kind is what happened
+	Kind EventKind
+	// X and Y are the world position the event happened at, 0 for events that don't happen anywhere in particular
+	X, Y float64
+	// Destruction describes the destroyed asteroid, for EventAsteroidDestroyed
+	Destruction destructionEvent
+}
+
+// EventHandler is a function called with every event of the kind it is subscribed to
+type EventHandler func(event *Event)
+
+// Subscribe registers a handler that is called for every event of a kind, so systems and mods can react to what happens
+// in a run without the game calling into each of them
+func (g *Game) Subscribe(kind EventKind, handler EventHandler) {
+	if g.eventHandlers == nil {
+		g.eventHandlers = map[EventKind][]EventHandler{}
+	}
+	g.eventHandlers[kind] = append(g.eventHandlers[kind], handler)
+}
+
+// publish passes an event to every handler subscribed to its kind, in the order they subscribed
+func (g *Game) publish(event *Event) {
+	for _, handler := range g.eventHandlers[event.Kind] {
+		handler(event)
+	}
+}
+
+// subscribeSystems subscribes the audio, particles, camera, background and scoring to the events they react to.  The
+// title preview subscribes nothing, so its world stays silent and still
+func (g *Game) subscribeSystems() {
+	g.Subscribe(EventShipDied, func(*Event) { g.audio.play(soundDeath) })
+	g.Subscribe(EventShipDied, func(*Event) { g.addShake(deathShake) })
+
+	g.Subscribe(EventAsteroidDestroyed, func(*Event) { g.audio.play(soundExplosion) })
+	g.Subscribe(EventAsteroidDestroyed, func(event *Event) { g.emitExplosion(event.X, event.Y) })
+	g.Subscribe(EventAsteroidDestroyed, func(event *Event) { g.shakeFromExplosion(event.X, event.Y) })
+	g.Subscribe(EventAsteroidDestroyed, func(*Event) { g.addGraveyardDebris() })
+	g.Subscribe(EventAsteroidDestroyed, func(event *Event) { g.onAsteroidDestroyed(event.Destruction) })
+	g.Subscribe(EventAsteroidDestroyed, g.onNemesisDestroyed)
+
+	g.Subscribe(EventStarCollected, func(*Event) { g.audio.play(soundStar) })
+	g.Subscribe(EventStarCollected, func(*Event) { g.coolEngine() })
+
+	g.Subscribe(EventBossDefeated, func(*Event) { g.audio.play(soundExplosion) })
+	g.Subscribe(EventBossDefeated, func(event *Event) { g.emitBossExplosion(event.X, event.Y) })
+	g.Subscribe(EventBossDefeated, func(*Event) { g.addShake(bossShake) })
+	g.Subscribe(EventBossDefeated, func(event *Event) { g.onBossDefeated(event.X, event.Y) })
+
+	g.Subscribe(EventUFODestroyed, func(*Event) { g.audio.play(soundExplosion) })
+	g.Subscribe(EventUFODestroyed, func(event *Event) { g.emitExplosion(event.X, event.Y) })
+	g.Subscribe(EventUFODestroyed, func(event *Event) { g.shakeFromExplosion(event.X, event.Y) })
+	g.Subscribe(EventUFODestroyed, func(event *Event) { g.onUFODestroyed(event.X, event.Y) })
+}
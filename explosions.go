@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// explosionRadius is the distance from the center of an explosion that pickups will be destroyed within
+	explosionRadius = 80
+)
+
+// explosion is a short-lived area entity that destroys any pickups caught within its radius
+type explosion struct {
+	*spriteutils.TransientSprite
+	// sprite is the sprite the transient sprite wraps, kept typed so the explosion can be placed and measured
+	sprite *spriteutils.Sprite
+	// radius is the distance from the center of the explosion that pickups will be destroyed within
+	radius float64
+}
+
+// spriteCenter returns the position of the center of a sprite
+func spriteCenter(sprite *spriteutils.Sprite) (float64, float64) {
+	width, height := sprite.Image.Size()
+	return float64(sprite.X) + float64(width)/2, float64(sprite.Y) + float64(height)/2
+}
+
+// isWithinRadius returns whether the center of a sprite is within radius of the center of another
+func isWithinRadius(sprite *spriteutils.Sprite, other *spriteutils.Sprite, radius float64) bool {
+	x1, y1 := spriteCenter(sprite)
+	x2, y2 := spriteCenter(other)
+	return math.Hypot(x1-x2, y1-y2) <= radius
+}
+
+// checkExplosionDamage destroys any pickups caught within the radius of an explosion, replacing them with fizzles
+func (g *Game) checkExplosionDamage() {
+	for _, explosion := range g.asteroidExplosions {
+		g.filterEntities(func(e *entity) bool {
+			if e.collider != colliderPickup || !isWithinRadius(explosion.sprite, e.Sprite, explosion.radius) {
+				return true
+			}
+			g.emitFizzle(e.Sprite)
+			g.releaseEntity(e)
+			return false
+		})
+	}
+}
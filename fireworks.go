@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"math"
+	"math/rand"
+)
+
+const (
+	// fireworkBursts is the number of fireworks launched when a run beats the best distance
+	fireworkBursts = 6
+	// fireworkBurstFrames is how many frames apart each firework bursts
+	fireworkBurstFrames = 40
+	// fireworkSparks is the number of sparks in each firework burst
+	fireworkSparks = 48
+	// fireworkSparkFrames is how many frames each spark lasts
+	fireworkSparkFrames = 70
+	// fireworkSparkSize is the width and height of each spark
+	fireworkSparkSize = 3
+	// fireworkGravity is how much each spark falls every frame
+	fireworkGravity = 0.05
+)
+
+// fireworks is the fireworks show played on the game over screen after a new best distance
+type fireworks struct {
+	// burstsLeft is the number of fireworks still to burst
+	burstsLeft int
+	// nextBurst is the number of frames until the next firework bursts
+	nextBurst int
+	// sparks are the sparks of every firework burst so far
+	sparks particleSystem
+	// sparkImage is the image each spark is drawn with
+	sparkImage *ebiten.Image
+}
+
+// startFireworks starts the fireworks show and plays the jingle for a new best distance
+func (g *Game) startFireworks() {
+	g.fireworks = &fireworks{burstsLeft: fireworkBursts, sparkImage: g.assets.Image(imageBeam)}
+	g.audio.play(soundJingle)
+}
+
+// burst creates the sparks of a single firework at a random place in the top half of the screen
+func (f *fireworks) burst() {
+	x := float64(screenWidth) * (0.2 + rand.Float64()*0.6)
+	y := float64(screenHeight) * (0.15 + rand.Float64()*0.35)
+	red, green, blue := 0.5+rand.Float64()/2, 0.5+rand.Float64()/2, 0.5+rand.Float64()/2
+
+	for i := 0; i < fireworkSparks; i++ {
+		angle := 2 * math.Pi * float64(i) / fireworkSparks
+		speed := 2 + rand.Float64()*2
+		spark := f.sparks.emit(f.sparkImage, x, y, fireworkSparkFrames)
+		spark.xVelocity = math.Cos(angle) * speed
+		spark.yVelocity = math.Sin(angle) * speed
+		spark.drag = 0.97
+		spark.gravity = fireworkGravity
+		spark.startScale = fireworkSparkSize
+		spark.endScale = fireworkSparkSize
+		spark.red, spark.green, spark.blue = red, green, blue
+	}
+}
+
+// updateFireworks bursts the next firework when it is due and moves the sparks, destroying sparks that have burnt out
+func (g *Game) updateFireworks() {
+	f := g.fireworks
+	if f == nil {
+		return
+	}
+
+	if f.burstsLeft > 0 {
+		f.nextBurst--
+		if f.nextBurst <= 0 {
+			f.burst()
+			f.burstsLeft--
+			f.nextBurst = fireworkBurstFrames
+		}
+	}
+
+	f.sparks.update(0)
+
+	if f.burstsLeft == 0 && len(f.sparks.particles) == 0 {
+		g.fireworks = nil
+	}
+}
+
+// drawFireworks draws every spark, fading as it burns out
+func (g *Game) drawFireworks(r Renderer) {
+	if g.fireworks == nil {
+		return
+	}
+
+	g.fireworks.sparks.draw(r)
+}
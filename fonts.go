@@ -0,0 +1,137 @@
+package main
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fontsDirName is the name of the folder next to the profile that extra font files are loaded from
+const fontsDirName = "fonts"
+
+// fallbackFace is a face that draws each glyph with the first face in its chain that has it, so text mixing
+// scripts (such as CJK or Cyrillic) renders even when the theme font doesn't cover them
+type fallbackFace struct {
+	// faces are the faces tried in order, the first being the primary face
+	faces []font.Face
+	// metrics are the line metrics of the whole chain, tall enough for the tallest face
+	metrics font.Metrics
+}
+
+// newFallbackFace chains a primary face with fallback faces of the same size.  The primary face is returned as is
+// when there are no fallbacks
+func newFallbackFace(primary font.Face, fallbacks []font.Face) font.Face {
+	if len(fallbacks) == 0 {
+		return primary
+	}
+
+	face := &fallbackFace{faces: append([]font.Face{primary}, fallbacks...), metrics: primary.Metrics()}
+	for _, fallback := range fallbacks {
+		metrics := fallback.Metrics()
+		if metrics.Height > face.metrics.Height {
+			face.metrics.Height = metrics.Height
+		}
+		if metrics.Ascent > face.metrics.Ascent {
+			face.metrics.Ascent = metrics.Ascent
+		}
+		if metrics.Descent > face.metrics.Descent {
+			face.metrics.Descent = metrics.Descent
+		}
+	}
+	return face
+}
+
+// faceFor returns the first face in the chain that has a glyph for a rune, or the primary face if none do
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	for _, face := range f.faces {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return face
+		}
+	}
+	return f.faces[0]
+}
+
+// Close closes every face in the chain
+func (f *fallbackFace) Close() error {
+	for _, face := range f.faces {
+		if err := face.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Glyph returns the glyph of a rune from the first face that has it
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+// GlyphBounds returns the bounds of a rune from the first face that has it
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+// GlyphAdvance returns the advance of a rune from the first face that has it
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+// Kern returns the kerning between two runes, which is only known when both come from the same face
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.faceFor(r0)
+	if face != f.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+// Metrics returns the line metrics of the whole chain
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.metrics
+}
+
+// loadFallbackFonts parses every .ttf and .otf file in the fonts folder next to the profile, in file name order.
+// A missing folder isn't an error, and files that fail to parse are skipped with the first error returned
+func loadFallbackFonts() ([]*opentype.Font, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, fontsDirName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var fonts []*opentype.Font
+	var firstErr error
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".ttf" && ext != ".otf") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, fontsDirName, entry.Name()))
+		if err == nil {
+			var tt *opentype.Font
+			if tt, err = opentype.Parse(data); err == nil {
+				fonts = append(fonts, tt)
+				continue
+			}
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return fonts, firstErr
+}
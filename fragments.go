@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// fragmentScale is how big each fragment of a split asteroid is compared to the asteroid
+	fragmentScale = 0.6
+	// minFragmentWidth is the narrowest a fragment can be.  Asteroids whose fragments would be any smaller don't split
+	minFragmentWidth = 30
+	// minFragments is the fewest fragments an asteroid splits into
+	minFragments = 2
+	// maxFragments is the most fragments an asteroid splits into
+	maxFragments = 3
+	// fragmentSpeed is how fast fragments fly apart from where the asteroid was
+	fragmentSpeed = 3
+	// fragmentMomentum is the fraction of the asteroid's velocity its fragments carry on with
+	fragmentMomentum = 0.5
+)
+
+// fragmentImage returns the image of the fragments an asteroid drawn with an image splits into, or nil if they would be too small
+func fragmentImage(image *ebiten.Image) *ebiten.Image {
+	if width, _ := image.Size(); float64(width)*fragmentScale < minFragmentWidth {
+		return nil
+	}
+	return scaledImage(image, fragmentScale)
+}
+
+// fragmentImages returns the images of the fragments an asteroid drawn with an image splits into, then the fragments
+// those split into, and so on until they are too small to split
+func fragmentImages(image *ebiten.Image) []*ebiten.Image {
+	var images []*ebiten.Image
+	for image = fragmentImage(image); image != nil; image = fragmentImage(image) {
+		images = append(images, image)
+	}
+	return images
+}
+
+// splitDestroyedAsteroids breaks each asteroid destroyed this frame into smaller fragments flying apart from where it
+// was.  Fragments are asteroids too, so they split again when destroyed until they are too small.  The fragments are
+// added once the collision checks are done, as the collision loops can't have the world changed under them
+func (g *Game) splitDestroyedAsteroids() {
+	for _, asteroid := range g.destroyedAsteroids {
+		g.splitAsteroid(asteroid)
+	}
+}
+
+// splitAsteroid adds the fragments of a destroyed asteroid to the world, unless they would be too small
+func (g *Game) splitAsteroid(asteroid *spriteutils.Sprite) {
+	if asteroid.Image == nil {
+		return
+	}
+	image := fragmentImage(asteroid.Image)
+	if image == nil {
+		return
+	}
+	width, height := image.Size()
+
+	x, y := spriteCenter(asteroid)
+	count := minFragments + g.randIntn("fragment count", maxFragments-minFragments+1)
+	offset := float64(g.randIntn("fragment angle", 360)) * math.Pi / 180
+	for i := 0; i < count; i++ {
+		angle := offset + 2*math.Pi*float64(i)/float64(count)
+		fragment := g.sprites.get()
+		fragment.Image = image
+		fragment.X = int(x+math.Cos(angle)*float64(width)/2) - width/2
+		fragment.Y = int(y+math.Sin(angle)*float64(height)/2) - height/2
+		fragment.XVelocity = asteroid.XVelocity*fragmentMomentum + math.Cos(angle)*fragmentSpeed
+		fragment.YVelocity = asteroid.YVelocity*fragmentMomentum + math.Sin(angle)*fragmentSpeed
+		fragment.Rotation = asteroid.Rotation
+		g.addSpawn(Spawn{Kind: SpawnAsteroid, Sprite: fragment})
+	}
+}
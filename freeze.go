@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+)
+
+// frozenVelocity is the velocity a frozen asteroid had before it was frozen, restored when the freeze ends
+type frozenVelocity struct {
+	x float64
+	y float64
+}
+
+// isFreezeActive returns whether asteroids are currently frozen
+func (g *Game) isFreezeActive() bool {
+	return g.frozenAsteroids != nil
+}
+
+// freezeAsteroids freezes every current asteroid in place, adding to the frozen asteroids if a freeze is already active
+func (g *Game) freezeAsteroids() {
+	if g.frozenAsteroids == nil {
+		g.frozenAsteroids = map[*spriteutils.Sprite]frozenVelocity{}
+	}
+	for _, asteroid := range g.entitySprites(entityAsteroid) {
+		if _, ok := g.frozenAsteroids[asteroid]; !ok {
+			g.frozenAsteroids[asteroid] = frozenVelocity{x: asteroid.XVelocity, y: asteroid.YVelocity}
+		}
+	}
+}
+
+// unfreezeAsteroids ends the freeze, giving asteroids back their velocity
+func (g *Game) unfreezeAsteroids() {
+	for asteroid, velocity := range g.frozenAsteroids {
+		asteroid.XVelocity = velocity.x
+		asteroid.YVelocity = velocity.y
+	}
+	g.frozenAsteroids = nil
+}
+
+// isFrozen returns whether an asteroid is frozen
+func (g *Game) isFrozen(asteroid *spriteutils.Sprite) bool {
+	_, ok := g.frozenAsteroids[asteroid]
+	return ok
+}
+
+// spriteTransform returns a transform placing an image where a sprite is, rotated about its center
+func spriteTransform(sprite *spriteutils.Sprite) *Transform {
+	width, height := sprite.Image.Size()
+
+	op := &Transform{}
+	op.Translate(-float64(width)/2, -float64(height)/2)
+	op.Rotate(sprite.Rotation)
+	op.Translate(float64(sprite.X)+float64(width)/2, float64(sprite.Y)+float64(height)/2)
+	return op
+}
+
+// drawFrost draws a frost tint over every frozen asteroid
+func (g *Game) drawFrost(r Renderer) {
+	for asteroid := range g.frozenAsteroids {
+		if isFarOffScreen(asteroid) {
+			continue
+		}
+		op := spriteTransform(asteroid)
+		op.ScaleColor(0, 0, 0, 0.5)
+		op.TranslateColor(0.6, 0.85, 1, 0)
+		r.DrawImage(asteroid.Image, op)
+	}
+}
@@ -3,87 +3,347 @@ package main
 import (
 	"fmt"
 	"github.com/hajimehoshi/ebiten"
-	"github.com/hajimehoshi/ebiten/ebitenutil"
-	"github.com/hajimehoshi/ebiten/inpututil"
-	"github.com/hajimehoshi/ebiten/text"
 	"github.com/llrowat/spriteutils"
-	"image/color"
+	"log"
 	"math"
 	"math/rand"
 	"time"
 )
 
-const (
-	// outofBoundsX represents the location when sprites are considered out of bounds and will be destroyed
-	outOfBoundsX = -200
-)
-
 // Game represents the game state
 type Game struct {
 	// mode is the current game mode
 	mode Mode
 
+	// profile is the player data persisted between launches
+	profile *Profile
+	// assets are the images and fonts the game draws with
+	assets *Assets
+
 	// ship is the main character ship sprite
-	ship   *spriteutils.Sprite
+	ship *spriteutils.Sprite
 	// shield is the main character's ship shield sprite
 	shield *spriteutils.Sprite
-
-	// topGroundTiles are the floor tile sprites at the top of the screen
-	topGroundTiles    []*spriteutils.Sprite
-	// bottomGroundTiles are the floor tile sprites at the bottom of the screen
-	bottomGroundTiles []*spriteutils.Sprite
+	// twinShip is the second ship, in the bottom half of the screen, when playing the dual ship variant
+	twinShip *spriteutils.Sprite
+	// twinShield is the second ship's shield sprite
+	twinShield *spriteutils.Sprite
+	// variant is the selected way to play the game
+	variant Variant
+	// pickedLoadoutName is the name of the loadout last picked on the title screen, empty if none has been
+	pickedLoadoutName string
+	// isNamingLoadout represents whether the name of a new loadout is being typed on the title screen
+	isNamingLoadout bool
+	// loadoutNameInput is the name typed so far for a new loadout
+	loadoutNameInput string
+
+	// entities are the ground tiles, spires, asteroids, pickups and projectiles in the world, in the order they were added
+	entities []*entity
 
 	// topSpireFactory is a factory for generating spires at the top of the screen
-	topSpireFactory    *spriteutils.SpriteFactory
+	topSpireFactory *spriteutils.SpriteFactory
 	// bottomSpireFactory is the factory for generating spires at the bottom of the screen
 	bottomSpireFactory *spriteutils.SpriteFactory
+	// topLedgeSpireFactory is the factory for generating spires with a ledge at the top of the screen
+	topLedgeSpireFactory *spriteutils.SpriteFactory
+	// bottomLedgeSpireFactory is the factory for generating spires with a ledge at the bottom of the screen
+	bottomLedgeSpireFactory *spriteutils.SpriteFactory
 	// asteroidFactory is the factory for generating asteroids
-	asteroidFactory    *spriteutils.SpriteFactory
-	// starFactory is the factory for generating stars
-	starFactory        *spriteutils.SpriteFactory
-	// spires are all the spire sprites currently in the game
-	spires             []*spriteutils.Sprite
-	// asteroids are all the asteroid sprites currently in the game
-	asteroids          []*spriteutils.Sprite
-	// asteroidExplosions are transient sprites that exist temporarily when asteroids collide with other objects
-	asteroidExplosions []*spriteutils.TransientSprite
-	// stars are all the star sprites currently in the game
-	stars              []*spriteutils.Sprite
+	asteroidFactory *spriteutils.SpriteFactory
+	// asteroidSizeFactories are the factories for generating asteroids of each size class, in the order of asteroidSizes
+	asteroidSizeFactories []*spriteutils.SpriteFactory
+	// ufoFactory is the factory for generating UFOs
+	ufoFactory *spriteutils.SpriteFactory
+	// sprites recycles the sprites of spires, asteroids, pickups and projectiles
+	sprites spritePool
+	// explosions recycles asteroid explosions
+	explosions explosionPool
+	// asteroidGrid buckets the asteroids for collision checks, rebuilt every frame
+	asteroidGrid broadphase
+	// destroyedAsteroids are the asteroids destroyed this frame, released to the pool once collision checks are done
+	destroyedAsteroids []*spriteutils.Sprite
+	// asteroidExplosions are short-lived area entities that exist temporarily when asteroids collide with other objects
+	asteroidExplosions []*explosion
+	// lastLaserFrame is the frame the laser last fired on
+	lastLaserFrame int64
+	// cleanPlayStartFrame is the frame the current streak of clean play started on
+	cleanPlayStartFrame int64
+	// starShowerEndFrame is the frame the current star shower ends on
+	starShowerEndFrame int64
+	// chaosSpawnThreshold represents the distance that the next chaos event will start
+	chaosSpawnThreshold int
+	// chaosEndFrame is the frame the current chaos event ends on
+	chaosEndFrame int64
+	// chaosTelegraphs are the warnings of asteroids about to enter during a chaos event
+	chaosTelegraphs []*chaosTelegraph
+	// particles are the particle effects in the world, such as explosion debris, thruster flames and pickup fizzles
+	particles particleSystem
+	// activePowerUps are the collected power-ups whose effects haven't expired yet
+	activePowerUps []*activePowerUp
+	// powerUpSpawnThresholds represent the distance that the next pickup of each power-up will spawn
+	powerUpSpawnThresholds map[*PowerUp]int
+	// rings are all the asteroid rings currently in the game
+	rings []*asteroidRing
+	// frozenAsteroids are the asteroids currently frozen, with the velocity they had before freezing.  It is nil when no freeze is active
+	frozenAsteroids map[*spriteutils.Sprite]frozenVelocity
 
 	// distanceTravelled represents the current distance travelled in game (basically the score)
-	distanceTravelled      int
+	distanceTravelled int
 	// speed represents how fast the player moves through the world (or actually how fast the world moves around the player)
-	speed                  float64
+	speed float64
 	// speedIncreaseThreshold represents the distance that the next speed increase will occur
 	speedIncreaseThreshold int
+	// launchCharge is how much the launch boost has been charged on the title screen, from 0 to 1
+	launchCharge float64
+	// isChargingLaunch represents whether Space was pressed on the title screen and the launch boost is charging
+	isChargingLaunch bool
+	// scoreMultiplier is the amount the distance travelled is multiplied by to give the score
+	scoreMultiplier float64
+	// isAssisted represents whether the run has been played with the asteroid path assist
+	isAssisted bool
+	// cheats are the developer cheats
+	cheats *cheats
+	// isCheated represents whether a developer cheat has been used during the run, such runs don't count towards records
+	isCheated bool
+	// asteroidPaths are how long the predicted paths of recently spawned asteroids are shown for
+	asteroidPaths map[*spriteutils.Sprite]telegraphWindow
+	// starsCollected is the number of stars collected during the run, banked when the run ends
+	starsCollected int
+	// coinsCollected is the number of coins collected during the run, banked when the run ends
+	coinsCollected int
 	// boostFactor is the amount speed will increase when the player hits a star
-	boostFactor            float64
+	boostFactor float64
 	// boostSeconds is how long the boost will last
-	boostSeconds           int64
+	boostSeconds int64
 	// isBoosting represents whether the player is currently undergoing a boost
-	isBoosting             bool
-	// lastBoostTime represents the start duration (time that has elapsed since game started) of the last boost
-	lastBoostTime          time.Duration
+	isBoosting bool
+	// segment is the index of the segment of the run the player is currently in
+	segment int
+	// stageIndex is the index of the stage the run is currently in
+	stageIndex int
+	// previousStageIndex is the index of the stage the run was in before the current one
+	previousStageIndex int
+	// stageStartFrame is the frame the current stage was started on, 0 for the first stage
+	stageStartFrame int64
+	// segmentStartFrame is the frame the current segment was started on
+	segmentStartFrame int64
+	// splitMarker is the slide of the split marker banner, nil when no split marker is being shown
+	splitMarker *TweenSequence
+	// splitMarkerSegment is the index of the segment the split marker is being shown for
+	splitMarkerSegment int
+	// splitMarkerGain is the time the segment best was beaten by
+	splitMarkerGain time.Duration
 	// spireSpawnThreshold represents the distance that the next spire will spawn
-	spireSpawnThreshold    int
+	spireSpawnThreshold int
 	// asteroidSpawnThreshold represents the distance that the next asteroid will spawn
 	asteroidSpawnThreshold int
-	// starSpawnThreshold represents the distance that the next star will spawn
-	starSpawnThreshold     int
+	// ringSpawnThreshold represents the distance that the next asteroid ring will spawn
+	ringSpawnThreshold int
+	// twinSpawnThreshold represents the distance that the next twin asteroids will spawn
+	twinSpawnThreshold int
+	// tethers are the tethers linking twin asteroids in the world
+	tethers []*tether
+	// gateSpawnThreshold represents the distance that the next laser gate will spawn
+	gateSpawnThreshold int
+	// gates are the laser gates in the world
+	gates []*gate
+	// currentSpawnThreshold represents the distance that the next current will spawn
+	currentSpawnThreshold int
+	// currents are the currents in the world
+	currents []*current
+	// ufoSpawnThreshold represents the distance that the next UFO will spawn
+	ufoSpawnThreshold int
+	// nemesis is the asteroid that ended the last run, brought back in this one, nil when it isn't in the world
+	nemesis *spriteutils.Sprite
+	// isNemesisSpawned represents whether the nemesis has been brought back this run
+	isNemesisSpawned bool
+	// isNemesisBeaten represents whether the nemesis has been destroyed or survived this run
+	isNemesisBeaten bool
+	// killer is the saved form of the asteroid that hit the ship, nil if the run didn't end on an asteroid
+	killer *Nemesis
+	// unlockedAchievement is the achievement most recently unlocked this run, empty if none has been
+	unlockedAchievement achievement
+	// achievementFrame is the frame the most recent achievement was unlocked on
+	achievementFrame int64
+	// bossSpawnThreshold represents the distance that the next boss will arrive
+	bossSpawnThreshold int
+	// boss is the boss in the world, nil when there isn't one
+	boss *boss
+
+	// gamepadIDs are the IDs of the connected gamepads
+	gamepadIDs []int
+	// lastStickX and lastStickY are the directions the left stick was pushed in last update, -1, 0 or 1 along each axis
+	lastStickX, lastStickY int
+
+	// settings are the options chosen on the settings screen
+	settings *Settings
+	// settingSelected is the option selected on the settings screen
+	settingSelected settingOption
+	// settingCategory is the tab shown on the settings screen
+	settingCategory settingCategory
+	// settingsSearch is the text typed to search the settings screen, in upper case
+	settingsSearch string
+	// isEditingSetting represents whether the selected typed setting, such as the player name, is being typed
+	isEditingSetting bool
+	// settingInput is the value typed so far for the setting being edited
+	settingInput string
+	// shopSelected is the index of the item selected in the shop
+	shopSelected int
+	// shopNotice is the outcome of the last thing chosen in the shop, shown below the items
+	shopNotice string
+
+	// audio plays the sound effects, it is nil when there is no audio
+	audio *audioManager
+
+	// isNewBest represents whether the last finished run beat the best distance
+	isNewBest bool
+	// isRecordAnnounced represents whether the announcer has called out passing the best distance this run
+	isRecordAnnounced bool
+	// isChaosAnnounced represents whether the announcer has called out the next chaos event
+	isChaosAnnounced bool
+	// ghost is the trajectory of the best run, raced against during the run, nil if there is none yet
+	ghost *Ghost
+	// ghostRecording is the trajectory of the ship so far this run
+	ghostRecording []int
+	// asteroidsExploded is how many asteroids have exploded during the run
+	asteroidsExploded int
+	// graveyard is the debris left in the background by the run's explosions
+	graveyard []graveyardDebris
+	// basePressure is how hard the director makes the run, judged from the player's recent runs, 1 being unchanged
+	basePressure float64
+	// pressure is how hard the director is making the run right now, eased by recent near-misses
+	pressure float64
+	// nearMissFrames are the frames of the recent near-misses
+	nearMissFrames []int64
+	// nearMissAsteroids are the asteroids that have already counted as a near-miss
+	nearMissAsteroids map[*spriteutils.Sprite]bool
+	// isQuickMenuOpen represents whether the quick settings menu is open, holding the run
+	isQuickMenuOpen bool
+	// isAudioHeld represents whether the audio is suspended because the quick menu or frame stepping is holding the run
+	isAudioHeld bool
+	// quickMenuSelected is the toggle selected on the quick settings menu
+	quickMenuSelected quickToggle
+	// engineHeat is how hot the engine is from thrusting, from 0 to 1 when it overheats
+	engineHeat float64
+	// overheatEndFrame is the frame thrust is enabled again after the engine overheated
+	overheatEndFrame int64
+	// waterDragEndFrame is the frame the ship stops being slowed down after splashing into water
+	waterDragEndFrame int64
+	// waterSpeedLoss is the world speed taken away by the last splash, given back once the drag wears off
+	waterSpeedLoss float64
+	// stressTest is the stress test being timed, or nil if none is running
+	stressTest *stressTest
+	// input is where keyboard and mouse input is read from, the player's unless synthetic input is driving the game
+	input Input
+	// leaderboard is the online leaderboard runs are submitted to, nil if none is configured
+	leaderboard *leaderboard
+	// isShareCardSaved represents whether a share card of the last finished run has been saved
+	isShareCardSaved bool
+	// bonusScore is the score earned during the run on top of the distance score, such as for shield kills
+	bonusScore int
+	// floatingTexts are the pieces of floating score text currently shown
+	floatingTexts []*floatingText
+	// fireworks is the fireworks show for a new best distance, nil when there isn't one
+	fireworks *fireworks
+	// summary is the breakdown of the last finished run, shown on the game over screen
+	summary *runSummary
+
+	// shipScale is how much bigger than normal the ships are, 1 being normal size
+	shipScale float64
+	// shake is the strength of the camera shake, how far in pixels the camera can be thrown from its place
+	shake float64
+	// shakeX is the horizontal offset of the camera for the current frame of the shake
+	shakeX float64
+	// shakeY is the vertical offset of the camera for the current frame of the shake
+	shakeY float64
+	// cameraZoom is the tween of the camera zoom, easing out as speed increases.  It is nil before the run starts
+	cameraZoom *Tween
+
+	// pauseCapture is the scene captured when the game was paused, shown blurred behind the pause text
+	pauseCapture *ebiten.Image
+
+	// pursuitWallX is the position of the right edge of the pursuit wall on screen in pursuit mode
+	pursuitWallX float64
+
+	// seed is the seed every run's spawns are drawn from, 0 to seed each run randomly
+	seed int64
+	// runSeed is the seed the current run's spawns are drawn from
+	runSeed int64
+	// rng is the random number generator the current run's spawns are drawn from
+	rng *rand.Rand
+	// rngLog is every random number drawn during the current run, only kept in debug builds
+	rngLog []rngDraw
 
 	// frameCount is the current frame the game is on since it has started
 	frameCount int64
+
+	// killCam records the last few seconds of the run so they can be replayed after death
+	killCam killCam
+
+	// spawnHooks are called for every spawn, allowing mods to veto, modify or add to spawns
+	spawnHooks []SpawnHook
+	// eventHandlers are the handlers subscribed to each kind of event, in the order they subscribed
+	eventHandlers map[EventKind][]EventHandler
+
+	// magnetEnergy is the energy left for the magnet beam, from 0 to 1
+	magnetEnergy float64
+	// magnetTarget is the star being pulled by the magnet beam, or nil if the beam is not in use
+	magnetTarget *spriteutils.Sprite
+
+	// lastUpdateTime is the wall clock time of the last update, used to detect the game being suspended
+	lastUpdateTime time.Time
+	// isMinimized represents whether the window is minimized and the tick rate has been lowered
+	isMinimized bool
+	// resumeCountdown is the number of frames left before a suspended run continues
+	resumeCountdown int
+
+	// replay is the saved replay being played back, nil unless one is
+	replay *replayReader
+	// replayFrame are the sprites of the replay frame being shown
+	replayFrame []*spriteutils.Sprite
+
+	// titlePreview is the world simulation shown behind the title screen
+	titlePreview *Game
+	// isPreview represents whether this game is the title preview, which shares the player's profile but shouldn't play
+	// anything drawn from their past runs
+	isPreview bool
+
+	// season is the season whose skins and music are in use, nil if there isn't one
+	season *Season
+	// skinnedSprite is reused to draw sprites with their seasonal skins without allocating
+	skinnedSprite spriteutils.Sprite
+	// rotationOp is reused to draw the pre-rendered rotated variants of sprites without allocating
+	rotationOp Transform
+
+	// entityCount is the number of sprites drawn in the last frame, shown on the performance HUD
+	entityCount int
+	// peakEntities is the most entities in the world at once during the run
+	peakEntities int
+	// peakParticles is the most particles live at once during the run
+	peakParticles int
+	// lastMallocs is the total number of heap allocations made as of the last frame, for counting the allocations each frame
+	lastMallocs uint64
+	// allocsPerFrame is the number of heap allocations made during the last frame, shown on the performance HUD
+	allocsPerFrame uint64
+	// gcCycles is the number of garbage collections since the game started, shown on the performance HUD
+	gcCycles uint32
+
+	// renderer draws the game to the screen
+	renderer ebitenRenderer
 }
 
 // Initialize by resetting game state to initial
 func (g *Game) init() {
+	g.AddSpawnHook(g.runSpawnScript)
+	g.AddSpawnHook(g.spawnForTwin)
+	g.subscribeSystems()
 	g.resetGame()
 }
 
 // resetGame Resets game start to initial state
 func (g *Game) resetGame() {
 	g.ship = &spriteutils.Sprite{
-		Image:     shipImage,
+		Image:     g.assets.Image(imageShip),
 		X:         screenWidth / 4,
 		Y:         screenHeight / 2,
 		XVelocity: 0,
@@ -91,167 +351,268 @@ func (g *Game) resetGame() {
 		Rotation:  0,
 	}
 	g.shield = nil
+	g.twinShip = nil
+	g.twinShield = nil
+
+	// The dual ship variant has a ship in each half of the screen
+	if g.variant == VariantDual {
+		g.ship.Y = screenHeight / 4
+		g.twinShip = &spriteutils.Sprite{
+			Image: g.assets.Image(imageShip),
+			X:     screenWidth / 4,
+			Y:     screenHeight * 3 / 4,
+		}
+	}
+
+	g.magnetEnergy = 1
+	g.magnetTarget = nil
 
 	g.distanceTravelled = 0
 	g.frameCount = 0
+	g.segment = 0
+	g.segmentStartFrame = 0
+	g.stageIndex = 0
+	g.previousStageIndex = 0
+	g.stageStartFrame = 0
+	g.splitMarker = nil
 	g.isBoosting = false
-	g.boostFactor = 2
-	g.lastBoostTime = 0
-	g.speed = 1
-	g.speedIncreaseThreshold = 500
-	g.spireSpawnThreshold = 600
-	g.asteroidSpawnThreshold = 200
-	g.starSpawnThreshold = 50
+	g.starsCollected = 0
+	g.coinsCollected = 0
+	g.isAssisted = false
+	g.isCheated = false
+	g.asteroidPaths = nil
+	g.boostFactor = tuning.BoostFactor
+	g.speed = g.difficulty().StartSpeed
+	g.launchCharge = 0
+	g.isChargingLaunch = false
+	g.scoreMultiplier = 1
+	g.speedIncreaseThreshold = g.difficulty().SpeedIncreaseDistance
+	g.spireSpawnThreshold = g.difficulty().SpireSpawnInterval
+	g.asteroidSpawnThreshold = g.difficulty().AsteroidSpawnInterval
+	g.ringSpawnThreshold = tuning.RingSpawnInterval
+	g.rings = nil
+	g.twinSpawnThreshold = twinSpawnMinInterval
+	g.nemesis = nil
+	g.isNemesisSpawned = false
+	g.isNemesisBeaten = false
+	g.killer = nil
+	g.unlockedAchievement = ""
+	g.tethers = nil
+	g.gateSpawnThreshold = gateFirstDistance
+	g.gates = nil
+	g.currentSpawnThreshold = currentFirstDistance
+	g.currents = nil
+	g.bossSpawnThreshold = bossFirstDistance
+	g.ufoSpawnThreshold = ufoFirstDistance
+	g.boss = nil
+	g.lastLaserFrame = -laserCooldownFrames
+	g.cleanPlayStartFrame = 0
+	g.starShowerEndFrame = 0
+	g.chaosSpawnThreshold = tuning.ChaosEventInterval
+	g.chaosEndFrame = 0
+	g.chaosTelegraphs = nil
+	g.frozenAsteroids = nil
+	g.cameraZoom = nil
+	g.shake = 0
+	g.shipScale = 1
+	g.shakeX, g.shakeY = 0, 0
+	g.resetRNG()
+	g.pursuitWallX = pursuitWallStartX
+	g.isNewBest = false
+	g.ghostRecording = nil
+	g.isRecordAnnounced = false
+	g.isChaosAnnounced = false
+	g.isShareCardSaved = false
+	g.asteroidsExploded = 0
+	g.isQuickMenuOpen = false
+	g.engineHeat = 0
+	g.overheatEndFrame = 0
+	g.waterDragEndFrame = 0
+	g.waterSpeedLoss = 0
+	g.stressTest = nil
+	g.resetDirector()
+	g.graveyard = nil
+	g.fireworks = nil
+	g.bonusScore = 0
+	g.floatingTexts = nil
 
 	g.asteroidExplosions = nil
+	g.particles.clear()
+	g.killCam.reset()
 
+	g.entities = nil
+	g.peakEntities = 0
+	g.peakParticles = 0
+	g.preallocate()
 	g.initializeGround()
 	g.initializeSpireFactories()
 	g.initializeAsteroidFactories()
-	g.initializeStarFactory()
+	g.initializeUFOFactory()
+	g.resetPowerUps()
 }
 
 // Update runs the game loop logic
 func (g *Game) Update(screen *ebiten.Image) error {
+	if debugBuild {
+		defer g.dumpRNGLogOnPanic()
+	}
+	defer g.timeStressTestUpdate(time.Now())
 
-	switch g.mode {
-	case ModeTitle:
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-			g.mode = ModeGame
-		}
-	case ModeGame:
-		{
-			// Increase speed periodically
-			g.distanceTravelled += int(g.speed)
-			if g.distanceTravelled > g.speedIncreaseThreshold {
-				g.speedIncreaseThreshold += g.speedIncreaseThreshold
-				g.speed++
-			}
-
-			// Check whether boost duration has elapsed
-			if g.isBoosting && time.Duration(g.frameCount)*time.Second/60-g.lastBoostTime > time.Second*5 {
-				g.speed -= g.boostFactor
-				g.isBoosting = false
-			}
-
-			g.shipMovement()
-			g.checkShieldOn()
+	if g.updateSuspend() {
+		return nil
+	}
 
-			g.updateGround()
-			g.updateSpires()
-			g.updateAsteroids()
-			g.updateStars()
+	g.updateGamepads()
+	g.updatePerfHUD()
+	rotations.update()
+	g.updateRNGLog()
+	g.updateMusic()
 
-			g.checkCollisions()
+	previous := g.mode
+	g.scene().Update(g)
+	g.switchScene(previous)
 
-			// Generate Spires
-			if g.distanceTravelled > g.spireSpawnThreshold {
-				if rand.Intn(2)%2 == 0 {
-					g.spires = append(g.spires, g.topSpireFactory.GenerateSprite())
-				} else {
-					g.spires = append(g.spires, g.bottomSpireFactory.GenerateSprite())
-				}
-				g.spireSpawnThreshold += 600
-			}
+	return nil
+}
 
-			// Generate asteroids and apply random impulse
-			if g.distanceTravelled > g.asteroidSpawnThreshold {
-				g.asteroids = append(g.asteroids, g.asteroidFactory.GenerateSprite())
-				g.asteroids[len(g.asteroids)-1].ApplyImpulse(float64(rand.Intn(10))-15, float64(rand.Intn(6))-3)
-				g.asteroidSpawnThreshold += 200
-			}
+// advance moves the player through the world, increasing speed periodically
+func (g *Game) advance() {
+	g.distanceTravelled += int(g.speed)
+	if g.distanceTravelled > g.speedIncreaseThreshold {
+		g.speedIncreaseThreshold = int(float64(g.speedIncreaseThreshold) * g.speedIncreaseGrowth())
+		g.speed++
+		g.publish(&Event{Kind: EventSpeedIncreased})
+	}
+}
 
-			// Generate Stars
-			if g.distanceTravelled > g.starSpawnThreshold {
-				g.stars = append(g.stars, g.starFactory.GenerateSprite())
-				g.starSpawnThreshold += 2000
-			}
+// spawnHazards generates spires, asteroids and stars as the player reaches each spawn threshold
+func (g *Game) spawnHazards() {
+	g.holdSpawnsForBoss()
+
+	// Generate Spires
+	if g.distanceTravelled > g.spireSpawnThreshold {
+		if g.randIntn("spire side", 2) == 0 {
+			spire := g.generateSpire(true)
+			spire.Y -= g.spireRetreat()
+			g.spawn(SpawnSpire, spire)
+		} else {
+			spire := g.generateSpire(false)
+			spire.Y += g.spireRetreat()
+			g.spawn(SpawnSpire, spire)
+		}
+		g.spireSpawnThreshold += int(float64(g.difficulty().SpireSpawnInterval) * g.stage().spireSpawnFactor)
+	}
 
-			// Handle explosions
-			temp := g.asteroidExplosions[:0]
-			for _, asteroidExplosion := range g.asteroidExplosions {
-				asteroidExplosion.Update(time.Duration(g.frameCount) * time.Second / 60)
-				if !asteroidExplosion.IsExpired {
-					temp = append(temp, asteroidExplosion)
-				}
-			}
-			g.asteroidExplosions = temp
+	// Asteroid spawning is paused while asteroids are frozen
+	if g.isFreezeActive() {
+		g.asteroidSpawnThreshold += int(g.speed)
+	}
 
-			g.frameCount++
-		}
-	case ModeGameOver:
-		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-			g.resetGame()
-			g.mode = ModeTitle
+	// Generate asteroids, now and then one that homes in on the ship
+	if g.distanceTravelled > g.asteroidSpawnThreshold {
+		if g.isHomingSpawn() {
+			g.spawn(SpawnHoming, g.generateAsteroid())
+		} else {
+			g.spawn(SpawnAsteroid, g.generateAsteroid())
 		}
+		g.asteroidSpawnThreshold += g.asteroidSpawnInterval()
 	}
 
-	return nil
-}
-
-// Draw draws all the game assets to screen
-func (g *Game) Draw(screen *ebiten.Image) {
-	g.drawBackground(screen)
+	// Bring back the asteroid that ended the last run
+	g.spawnNemesis()
 
-	// Draw all stars
-	for _, star := range g.stars {
-		star.Draw(screen)
+	// Generate asteroid rings
+	if g.distanceTravelled > g.ringSpawnThreshold {
+		g.spawn(SpawnRing, g.generateRingCore())
+		g.ringSpawnThreshold += tuning.RingSpawnInterval
 	}
 
-	// Draw all spires
-	for _, spire := range g.spires {
-		spire.Draw(screen)
+	// Generate twin asteroids
+	if g.distanceTravelled > g.twinSpawnThreshold {
+		g.spawn(SpawnTwins, g.generateTwin())
+		g.twinSpawnThreshold += twinSpawnMinInterval + g.randIntn("twin interval", twinSpawnMaxInterval-twinSpawnMinInterval)
 	}
 
-	// Draw  floor tiles
-	for _, tile := range g.topGroundTiles {
-		tile.Draw(screen)
+	// Generate laser gates
+	if g.distanceTravelled > g.gateSpawnThreshold {
+		g.spawn(SpawnGate, g.generateGate())
+		g.gateSpawnThreshold += gateSpawnMinInterval + g.randIntn("gate interval", gateSpawnMaxInterval-gateSpawnMinInterval)
 	}
-	for _, tile := range g.bottomGroundTiles {
-		tile.Draw(screen)
+
+	// Generate currents
+	if g.distanceTravelled > g.currentSpawnThreshold {
+		g.spawnCurrent()
+		g.currentSpawnThreshold += currentSpawnMinInterval + g.randIntn("current interval", currentSpawnMaxInterval-currentSpawnMinInterval)
 	}
 
-	// Draw all asteroids
-	for _, asteroid := range g.asteroids {
-		asteroid.Draw(screen)
+	// Generate UFOs
+	if g.distanceTravelled > g.ufoSpawnThreshold {
+		g.spawn(SpawnUFO, g.generateSprite(g.ufoFactory))
+		g.ufoSpawnThreshold += ufoSpawnInterval
 	}
 
-	// Draw all asteroid explosions
+	// Generate power-up pickups
+	g.spawnPowerUps()
+}
+
+// updateExplosions updates explosions and particles, destroying any that have expired
+func (g *Game) updateExplosions() {
+	temp := g.asteroidExplosions[:0]
 	for _, asteroidExplosion := range g.asteroidExplosions {
-		asteroidExplosion.Draw(screen)
+		asteroidExplosion.Update(time.Duration(g.frameCount) * time.Second / 60)
+		// The transient sprite lets go of its sprite once it expires, without keeping IsExpired set
+		if asteroidExplosion.Sprite != nil {
+			temp = append(temp, asteroidExplosion)
+		} else {
+			g.explosions.put(asteroidExplosion)
+		}
 	}
+	g.asteroidExplosions = temp
+	g.particles.update(g.speed)
+}
 
-	// Draw ship and shield is it is enabled
-	g.ship.Draw(screen)
-	if g.shield != nil {
-		g.shield.Draw(screen)
+// Draw draws all the game assets to screen
+func (g *Game) Draw(screen *ebiten.Image) {
+	defer g.timeStressTestDraw(time.Now())
+	// The paused scene is frozen, so it is captured once and shown blurred
+	if g.mode == ModePause && g.pauseCapture == nil {
+		g.pauseCapture = g.capturePausedScene()
 	}
 
-	var titleTexts []string
-	var texts []string
-
-	// Draw game text
-	switch g.mode {
-	case ModeTitle:
-		titleTexts = []string{"GALACTIC ASTEROID BELT"}
-		texts = []string{"", "", "", "", "", "", "", "PRESS SPACE KEY"}
-	case ModeGame:
-		g.drawScore(screen)
-	case ModeGameOver:
-		titleTexts = []string{"GAME OVER!"}
-		texts = []string{"", "", "", "", "", "", fmt.Sprintf("DISTANCE TRAVELLED: %d M", g.distanceTravelled), "", "", "", "PRESS 'R' KEY TO RESTART"}
-	}
-	for i, l := range titleTexts {
-		x := (screenWidth - len(l)/2*titleFontSize) / 2
-		text.Draw(screen, l, titleFont, x, screenHeight/4+(i+4)*titleFontSize, color.White)
-	}
-	for i, l := range texts {
-		x := (screenWidth - len(l)/2*fontSize) / 2
-		text.Draw(screen, l, normalFont, x, screenHeight/4+(i+4)*fontSize, color.White)
-	}
+	g.renderer.begin(screen, g.palette())
+	g.draw(&g.renderer)
+	g.renderer.end()
+}
 
+// draw draws all the game assets using the given renderer
+func (g *Game) draw(r Renderer) {
+	g.scene().Draw(g, r)
+	g.drawPerfHUD(r)
+}
 
-	ebitenutil.DebugPrint(screen, fmt.Sprintf("FPS: %0.2f", ebiten.CurrentFPS()))
+// drawWorld draws all the sprites in the game world
+func (g *Game) drawWorld(r Renderer) {
+	// The debris is part of the background, so it stays still when the camera moves
+	g.drawGraveyard(r)
+	r.SetCamera(g.camera())
+	g.drawCurrents(r)
+	g.drawGhost(r)
+	sprites := g.worldSprites()
+	for _, sprite := range sprites {
+		g.drawSprite(r, sprite)
+	}
+	g.drawTethers(r)
+	g.drawGates(r)
+	g.drawFrost(r)
+	g.drawHomingGlow(r)
+	g.drawNemesisMark(r)
+	g.particles.draw(r)
+	g.drawMagnetBeam(r)
+	g.drawAsteroidPaths(r)
+	g.drawPursuitWall(r)
+	r.SetCamera(defaultCamera)
+
+	g.entityCount = len(sprites) + len(g.particles.particles)
 }
 
 // Layout scales the logical game size with the window size.  We don't do anything here, just return the fixed window size
@@ -261,75 +622,92 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 // shipMovement handles all the logic for moving the player character ship
 func (g *Game) shipMovement() {
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) || ebiten.IsKeyPressed(ebiten.KeySpace) {
-		g.ship.YVelocity -= 0.5
+	isThrusting := g.isThrustPressed() && !g.isOverheated()
+	g.updateHeat(isThrusting)
+	g.audio.setThrusting(isThrusting)
+	if isThrusting {
+		g.emitThrustFlame()
 	}
 
-	// Gravity
-	g.ship.YVelocity += 0.25
+	// Every ship responds to the same input
+	for _, ship := range g.ships() {
+		if isThrusting {
+			ship.YVelocity -= g.thrust() * g.gravityDirection()
+		}
+
+		// Gravity
+		ship.YVelocity += tuning.ShipGravity * g.gravityDirection()
+		g.applyCurrents(ship)
+		g.applyWaterDrag(ship)
 
-	g.ship.Update()
+		ship.Update()
 
-	// The ship rotates a little bit when moving up/down to give it some "floatiness"
-	g.ship.Rotation = float64(g.ship.YVelocity) / 96.0 * math.Pi / 2
+		// The ship rotates a little bit when moving up/down to give it some "floatiness"
+		ship.Rotation = float64(ship.YVelocity) / 96.0 * math.Pi / 2
+	}
 }
 
 // checkShieldOn checks whether the ship shield should be enabled
 func (g *Game) checkShieldOn() {
-	if g.isBoosting {
-		g.shield = &spriteutils.Sprite{
-			Image:     shieldImage,
-			X:         g.ship.X - 17,
-			Y:         g.ship.Y - 15,
-		}
-	} else {
-		g.shield = nil
+	g.shield = nil
+	g.twinShield = nil
+	if !g.isBoosting {
+		return
+	}
+
+	g.shield = g.createShield(g.ship)
+	if g.twinShip != nil {
+		g.twinShield = g.createShield(g.twinShip)
 	}
 }
 
-// drawBackground draws the background image
-func (g *Game) drawBackground(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-	imageWidth, imageHeight := backgroundImage.Size()
+// drawBackground draws the background image, in the colors of the current stage
+func (g *Game) drawBackground(r Renderer) {
+	op := &Transform{}
+	imageWidth, imageHeight := g.assets.Image(imageBackground).Size()
 	maxScale := math.Max(float64(screenWidth)/float64(imageWidth), float64(screenHeight)/float64(imageHeight))
-	op.GeoM.Scale(maxScale, maxScale)
-	screen.DrawImage(backgroundImage, op)
+	op.Scale(maxScale, maxScale)
+	red, green, blue := g.stageTint()
+	op.ScaleColor(red, green, blue, 1)
+	r.DrawImage(g.assets.Image(imageBackground), op)
 }
 
 // initializeGround sets the initial state of the floor tiles
 func (g *Game) initializeGround() {
-	imageWidth, imageHeight := floorImage.Size()
+	imageWidth, imageHeight := g.assets.Image(imageFloor).Size()
 
-	g.topGroundTiles = nil
-	g.bottomGroundTiles = nil
+	// Each row of tiles is one tile wider than the screen either side, so a tile scrolling off the left can wrap around to the right
+	tiles := 0
+	for (tiles * imageWidth) < (screenWidth + imageWidth*2) {
+		tiles++
+	}
 
-	for i := 0; (i * imageWidth) < (screenWidth + imageWidth*2); i++ {
-		topTile := &spriteutils.Sprite{
-			Image:     floorImage,
+	for i := 0; i < tiles; i++ {
+		topTile := g.addEntity(entityGround, &spriteutils.Sprite{
+			Image:     g.assets.Image(imageFloor),
 			X:         imageWidth*i - g.distanceTravelled,
 			XVelocity: -g.speed,
 			Rotation:  math.Pi,
-		}
+		})
+		topTile.wrapWidth = imageWidth * tiles
 
-		bottomTile := &spriteutils.Sprite{
-			Image:     floorImage,
+		// The bottom row is whatever ground the biome has
+		bottomTile := g.addEntity(g.biome().bottomGround, &spriteutils.Sprite{
+			Image:     g.assets.Image(g.biome().bottomImage),
 			X:         imageWidth*i - g.distanceTravelled,
 			Y:         screenHeight - imageHeight,
 			XVelocity: -g.speed,
-		}
-
-		g.topGroundTiles = append(g.topGroundTiles, topTile)
-		g.bottomGroundTiles = append(g.bottomGroundTiles, bottomTile)
+		})
+		bottomTile.wrapWidth = imageWidth * tiles
 	}
 }
 
-// initializeSpireFactories sets the options of the spire sprite factory
+// initializeSpireFactories sets the options of the spire sprite factories
 func (g *Game) initializeSpireFactories() {
-	_, spireHeight := topSpire.Size()
+	_, spireHeight := g.assets.Image(imageTopSpire).Size()
 
-	g.spires = nil
 	g.topSpireFactory = &spriteutils.SpriteFactory{
-		Images: []*ebiten.Image{topSpire},
+		Images: []*ebiten.Image{g.stageImage(imageTopSpire)},
 		MaxX:   screenWidth + 150,
 		MinX:   screenWidth + 150,
 		MaxY:   0,
@@ -337,197 +715,172 @@ func (g *Game) initializeSpireFactories() {
 	}
 
 	g.bottomSpireFactory = &spriteutils.SpriteFactory{
-		Images: []*ebiten.Image{bottomSpire},
+		Images: []*ebiten.Image{g.stageImage(imageBottomSpire)},
 		MaxX:   screenWidth + 150,
 		MinX:   screenWidth + 150,
 		MaxY:   screenHeight - spireHeight + 200,
 		MinY:   screenHeight - spireHeight,
 	}
+
+	// Ledge spires are placed like plain spires, the ledge jutting back from where a plain spire would be
+	g.topLedgeSpireFactory = &spriteutils.SpriteFactory{
+		Images: []*ebiten.Image{g.stageImage(imageTopLedgeSpire)},
+		MaxX:   screenWidth + 150 - ledgeLength,
+		MinX:   screenWidth + 150 - ledgeLength,
+		MaxY:   0,
+		MinY:   -200,
+	}
+
+	g.bottomLedgeSpireFactory = &spriteutils.SpriteFactory{
+		Images: []*ebiten.Image{g.stageImage(imageBottomLedgeSpire)},
+		MaxX:   screenWidth + 150 - ledgeLength,
+		MinX:   screenWidth + 150 - ledgeLength,
+		MaxY:   screenHeight - spireHeight + 200,
+		MinY:   screenHeight - spireHeight,
+	}
 }
 
-// initializeAsteroidFactories sets the options of the asteroid sprite factory
+// initializeAsteroidFactories sets the options of the asteroid sprite factory, and of the factory for each size class
 func (g *Game) initializeAsteroidFactories() {
-	g.asteroids = nil
 	g.asteroidFactory = &spriteutils.SpriteFactory{
-		Images: []*ebiten.Image{asteroid1, asteroid2, asteroid3, asteroid4},
+		Images: []*ebiten.Image{g.stageImage(imageAsteroid1), g.stageImage(imageAsteroid2), g.stageImage(imageAsteroid3), g.stageImage(imageAsteroid4)},
 		MaxX:   screenWidth + 100,
 		MinX:   screenWidth + 100,
 		MaxY:   screenHeight - 100,
 		MinY:   100,
 	}
-}
 
-// initializeStarFactory sets the options of the star sprite factory
-func (g *Game) initializeStarFactory() {
-	g.stars = nil
-	g.starFactory = &spriteutils.SpriteFactory{
-		Images: []*ebiten.Image{starImage},
-		MaxX:   screenWidth + 100,
-		MinX:   screenWidth + 100,
-		MaxY:   screenHeight - 100,
-		MinY:   100,
+	g.asteroidSizeFactories = make([]*spriteutils.SpriteFactory, len(asteroidSizes))
+	for i, size := range asteroidSizes {
+		factory := *g.asteroidFactory
+		factory.Images = make([]*ebiten.Image, len(g.asteroidFactory.Images))
+		for j, image := range g.asteroidFactory.Images {
+			factory.Images[j] = scaledImage(image, size.scale)
+		}
+		g.asteroidSizeFactories[i] = &factory
 	}
 }
 
-// drawScore draws the score (distance travelled)
-func (g *Game) drawScore(screen *ebiten.Image) {
-	scoreStr := fmt.Sprintf("Distance: %8d m", g.distanceTravelled)
-	text.Draw(screen, scoreStr, normalFont, screenWidth-(len(scoreStr)*fontSize/2), fontSize, color.White)
-}
-
-// createAsteroidExplosion creates the sprites for asteroid explosion, given an asteroid
-func (g *Game) createAsteroidExplosion(asteroid *spriteutils.Sprite) *spriteutils.TransientSprite {
-	return &spriteutils.TransientSprite{
-		CreatedAtGameTime: time.Duration(g.frameCount) * time.Second / 60,
-		LifetimeDuration:  time.Millisecond * 100,
-		Sprite: &spriteutils.Sprite{
-			Image:     asteroidExplosionImage,
-			X:         asteroid.X,
-			Y:         asteroid.Y,
-			XVelocity: -g.speed,
-			Rotation:  rand.Float64() * math.Pi,
-		},
+// endRun saves the progress made during the run once the ship has been destroyed
+func (g *Game) endRun() {
+	if g.isCheated {
+		g.summary = g.newRunSummary()
+		return
 	}
-}
 
-// checkCollisions does all the collision handling logic
-func (g *Game) checkCollisions() {
-	// Ground collisions
-	for _, tile := range g.topGroundTiles {
-		if g.ship.IsColliding(tile) {
-			g.mode = ModeGameOver
+	if best := g.profile.BestDistance; g.distanceTravelled > best {
+		g.profile.BestDistance = g.distanceTravelled
+		g.isNewBest = true
+		if err := g.saveGhost(); err != nil {
+			log.Println(err)
 		}
 
-		for i, asteroid := range g.asteroids {
-			if asteroid.IsColliding(tile) {
-				g.asteroidExplosions = append(g.asteroidExplosions, g.createAsteroidExplosion(asteroid))
-				g.asteroids = append(g.asteroids[:i], g.asteroids[i+1:]...)
-			}
+		// Only celebrate beating an existing best, not the first run
+		if best != 0 {
+			g.startFireworks()
 		}
 	}
 
-	for _, tile := range g.bottomGroundTiles {
-		if g.ship.IsColliding(tile) {
-			g.mode = ModeGameOver
-		}
-
-		for i, asteroid := range g.asteroids {
-			if asteroid.IsColliding(tile) {
-				g.asteroidExplosions = append(g.asteroidExplosions, g.createAsteroidExplosion(asteroid))
-				g.asteroids = append(g.asteroids[:i], g.asteroids[i+1:]...)
-			}
-		}
+	g.summary = g.newRunSummary()
+	g.submitRun()
+	g.recordRecentDistance()
+	g.recordProgress()
+	g.recordPeaks()
+	g.recordNemesis()
+	g.profile.Wallet.deposit(int64(g.starsCollected))
+	g.profile.Wallet.depositCoins(g.coinsBanked())
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
 	}
+}
 
-	// spire collisions
-	for _, spire := range g.spires {
-		if g.ship.IsColliding(spire) {
-			g.mode = ModeGameOver
-		}
-
-		for i, asteroid := range g.asteroids {
-			if asteroid.IsColliding(spire) {
-				g.asteroidExplosions = append(g.asteroidExplosions, g.createAsteroidExplosion(asteroid))
-				g.asteroids = append(g.asteroids[:i], g.asteroids[i+1:]...)
-			}
-		}
+// dismissWhatsNew records that the "what's new" screen has been seen for this version and moves on to the title screen
+func (g *Game) dismissWhatsNew() {
+	g.profile.LastSeenVersion = gameVersion
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
 	}
+	g.mode = ModeTitle
+}
 
-	// asteroid collisions
-	for i, asteroid := range g.asteroids {
-		if g.shield != nil && g.shield.IsColliding(asteroid) {
-			g.asteroidExplosions = append(g.asteroidExplosions, g.createAsteroidExplosion(asteroid))
-			g.asteroids = append(g.asteroids[:i], g.asteroids[i+1:]...)
-		}
-
-		if g.ship.IsColliding(asteroid) {
-			g.mode = ModeGameOver
-		}
-	}
+// drawScore draws the score (distance travelled)
+func (g *Game) drawScore(r Renderer) {
+	theme := g.theme()
+	scoreStr := fmt.Sprintf("Distance: %8d m", g.distanceTravelled)
+	r.DrawText(scoreStr, g.fonts().Normal, g.hudX(len(scoreStr)*g.ui(fontSize)/2, 0), g.ui(fontSize), theme.Text())
 
-	// star collisions
-	for i, star := range g.stars {
-		if g.ship.IsColliding(star) {
-			g.stars = append(g.stars[:i], g.stars[i+1:]...)
-			g.isBoosting = true
-			g.lastBoostTime = time.Duration(g.frameCount) * time.Second / 60
-			g.speed += g.boostFactor
-		}
-	}
+	multiplierStr := fmt.Sprintf("Score: %8d x%.2f", g.score(), g.scoreMultiplier)
+	r.DrawText(multiplierStr, g.fonts().Small, g.hudX(len(multiplierStr)*g.ui(smallFontSize)/2, 0), g.ui(fontSize)+g.ui(smallFontSize)*2, theme.Text())
 }
 
-//updateGround updates the ground positions and ensures that the ground loops properly
-func (g *Game) updateGround() {
-	imageWidth, imageHeight := floorImage.Size()
+// createAsteroidExplosion creates the explosion for an asteroid, given an asteroid
+func (g *Game) createAsteroidExplosion(asteroid *spriteutils.Sprite) *explosion {
+	explosion := g.explosions.get()
+	explosion.CreatedAtGameTime = time.Duration(g.frameCount) * time.Second / 60
+	explosion.LifetimeDuration = time.Millisecond * 100
+	explosion.sprite.Image = g.assets.Image(imageAsteroidExplosion)
+	explosion.sprite.X = asteroid.X
+	explosion.sprite.Y = asteroid.Y
+	explosion.sprite.XVelocity = -g.speed
+	explosion.radius = explosionRadius
+	return explosion
+}
 
-	for _, tile := range g.topGroundTiles {
-		tile.XVelocity = -g.speed
-		tile.Update()
+// checkCollisions does all the collision handling logic
+func (g *Game) checkCollisions() {
+	g.asteroidGrid.rebuild(g.entitySprites(entityAsteroid))
+
+	// ground, spire and laser gate collisions
+	g.checkTerrainCollisions()
+	g.checkGateCollisions()
+	g.checkWaterCollisions()
+
+	// asteroid collisions, shields first so they destroy asteroids before they reach the ships
+	for _, shield := range g.shields() {
+		g.destroyAsteroidsColliding(shield, destroyedByShield)
+	}
+	for _, ship := range g.ships() {
+		for _, asteroid := range g.asteroidGrid.nearby(ship) {
+			if ship.IsColliding(asteroid) && isTouchingHull(ship, asteroid) {
+				g.mode = ModeGameOver
+				g.recordKiller(asteroid)
+			}
+		}
 	}
 
-	for _, tile := range g.bottomGroundTiles {
-		tile.XVelocity = -g.speed
-		tile.Update()
-	}
+	// asteroid ring collisions
+	g.checkRingCollisions()
 
-	if g.topGroundTiles[0].X <= -imageWidth {
-		g.topGroundTiles = append(g.topGroundTiles[:0], g.topGroundTiles[1:]...)
-		g.topGroundTiles = append(g.topGroundTiles, &spriteutils.Sprite{
-			Image:     floorImage,
-			X:         g.topGroundTiles[len(g.topGroundTiles)-1].X + imageWidth,
-			XVelocity: -g.speed,
-			Rotation:  math.Pi,
-		})
-	}
+	// boss, bolt and UFO collisions
+	g.checkBossCollisions()
+	g.checkUFOCollisions()
 
-	if g.bottomGroundTiles[0].X <= -imageWidth {
-		g.bottomGroundTiles = append(g.bottomGroundTiles[:0], g.bottomGroundTiles[1:]...)
-		g.bottomGroundTiles = append(g.bottomGroundTiles, &spriteutils.Sprite{
-			Image:     floorImage,
-			X:         g.bottomGroundTiles[len(g.bottomGroundTiles)-1].X + imageWidth,
-			Y:         screenHeight - imageHeight,
-			XVelocity: -g.speed,
-		})
-	}
-}
+	// projectile collisions
+	g.checkProjectileCollisions()
 
-// updateSpires updates the spire positions and destroys out of bounds spires
-func (g *Game) updateSpires() {
-	temp := g.spires[:0]
-	for _, spire := range g.spires {
-		spire.XVelocity = -g.speed
-		spire.Update()
+	g.filterEntities(func(e *entity) bool {
+		return !g.asteroidGrid.isRemoved(e.Sprite)
+	})
 
-		if spire.X > outOfBoundsX {
-			temp = append(temp, spire)
-		}
-	}
-	g.spires = temp
-}
+	// explosions destroy nearby pickups
+	g.checkExplosionDamage()
 
-// updateAsteroids updates the asteroid positions and destroys out of bounds asteroids
-func (g *Game) updateAsteroids() {
-	temp := g.asteroids[:0]
-	for _, asteroid := range g.asteroids {
-		asteroid.Update()
+	// pickup collisions
+	g.checkPickupCollisions()
 
-		if asteroid.X > outOfBoundsX {
-			temp = append(temp, asteroid)
-		}
-	}
-	g.asteroids = temp
+	g.splitDestroyedAsteroids()
+	g.releaseDestroyedAsteroids()
 }
 
-// updateStars updates the star positions and destroys out of bounds stars
-func (g *Game) updateStars() {
-	temp := g.stars[:0]
-	for _, star := range g.stars {
-		star.XVelocity = -g.speed
-		star.Update()
-
-		if star.X > outOfBoundsX {
-			temp = append(temp, star)
+// destroyAsteroidsColliding destroys every asteroid colliding with a sprite, returning whether any were destroyed
+func (g *Game) destroyAsteroidsColliding(sprite *spriteutils.Sprite, cause destructionCause) bool {
+	destroyed := false
+	for _, asteroid := range g.asteroidGrid.nearby(sprite) {
+		if asteroid.IsColliding(sprite) && isTouchingHull(asteroid, sprite) {
+			g.destroyAsteroid(asteroid, cause)
+			g.asteroidGrid.remove(asteroid)
+			destroyed = true
 		}
 	}
-	g.stars = temp
+	return destroyed
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"log"
+)
+
+const (
+	// gamepadButtonA is the A button on a standard controller, it thrusts and confirms
+	gamepadButtonA = ebiten.GamepadButton0
+	// gamepadButtonStart is the Start button on a standard controller, it pauses and starts
+	gamepadButtonStart = ebiten.GamepadButton7
+)
+
+// updateGamepads keeps track of which gamepads are connected, so any connected controller can be used
+func (g *Game) updateGamepads() {
+	temp := g.gamepadIDs[:0]
+	for _, id := range g.gamepadIDs {
+		if g.input.IsGamepadJustDisconnected(id) {
+			log.Printf("gamepad %d disconnected", id)
+			continue
+		}
+		temp = append(temp, id)
+	}
+	g.gamepadIDs = temp
+
+	for _, id := range g.input.JustConnectedGamepadIDs() {
+		log.Printf("gamepad %d connected: %s", id, g.input.GamepadName(id))
+		g.gamepadIDs = append(g.gamepadIDs, id)
+	}
+}
+
+// isGamepadPressed returns whether a button is held on any connected gamepad
+func (g *Game) isGamepadPressed(button ebiten.GamepadButton) bool {
+	for _, id := range g.gamepadIDs {
+		if g.input.IsGamepadButtonPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGamepadJustPressed returns whether a button was just pressed on any connected gamepad
+func (g *Game) isGamepadJustPressed(button ebiten.GamepadButton) bool {
+	for _, id := range g.gamepadIDs {
+		if g.input.IsGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
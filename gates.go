@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"image"
+	"image/color"
+	"math"
+)
+
+const (
+	// gateBeamFrames is how many frames the animation of a laser gate's beam has
+	gateBeamFrames = 4
+	// gateBeamFrameLength is how many frames each frame of the beam animation is shown for
+	gateBeamFrameLength = 4
+	// gateBeamWidth is the width of a laser gate's beam
+	gateBeamWidth = 16
+	// gateBeamTextureHeight is the height of each frame of the beam animation, stretched to the length of the beam
+	gateBeamTextureHeight = 64
+	// gateFirstDistance is the distance of the first laser gate
+	gateFirstDistance = 3000
+	// gateSpawnMinInterval is the shortest distance between laser gates
+	gateSpawnMinInterval = 4000
+	// gateSpawnMaxInterval is the longest distance between laser gates
+	gateSpawnMaxInterval = 6000
+	// gateGap is the distance between the tips of a laser gate's spires, spanned by its beam
+	gateGap = 220
+	// gateMaxTopY is the lowest a laser gate's top spire is placed, leaving room below for the gap and bottom spire
+	gateMaxTopY = -100
+	// gateCycleFrames is how many frames a laser gate's beam takes to go through being on and off
+	gateCycleFrames = 180
+	// gateActiveFrames is how many frames of each cycle the beam is on for
+	gateActiveFrames = 90
+	// gateWarningFrames is how many frames before the beam turns on it flickers as a warning
+	gateWarningFrames = 30
+)
+
+// gate is a laser gate: a top and bottom spire lined up with an energy beam between their tips that turns on and off.
+// Touching the beam while it is on ends the run
+type gate struct {
+	// top and bottom are the spires at either end of the beam
+	top, bottom *entity
+	// startFrame is the frame the beam's current cycle started on
+	startFrame int64
+}
+
+// gateBeamImageKey returns the key of a frame of the beam animation
+func gateBeamImageKey(frame int) string {
+	return fmt.Sprintf("gateBeam%d", frame)
+}
+
+// loadGateImages creates the frames of the laser gate beam animation, a bright core with bands of light running along it
+func (a *Assets) loadGateImages() error {
+	for frame := 0; frame < gateBeamFrames; frame++ {
+		img := image.NewRGBA(image.Rect(0, 0, gateBeamWidth, gateBeamTextureHeight))
+		for y := 0; y < gateBeamTextureHeight; y++ {
+			band := 0.6 + 0.4*math.Sin(2*math.Pi*float64(y*2+frame*gateBeamTextureHeight/gateBeamFrames)/gateBeamTextureHeight*2)
+			for x := 0; x < gateBeamWidth; x++ {
+				// The beam is brightest along its middle, fading out to its edges
+				core := 1 - math.Abs(float64(x)-float64(gateBeamWidth-1)/2)/(float64(gateBeamWidth)/2)
+				alpha := core * core * band
+				img.Set(x, y, color.NRGBA{R: 255, G: uint8(80 + 120*core), B: uint8(80 + 120*core), A: uint8(255 * alpha)})
+			}
+		}
+
+		beam, err := ebiten.NewImageFromImage(img, ebiten.FilterDefault)
+		if err != nil {
+			return err
+		}
+		a.images[gateBeamImageKey(frame)] = beam
+	}
+	return nil
+}
+
+// generateGate creates the top spire of a new laser gate just off the right of the screen, high enough to leave room for
+// the gap and the bottom spire
+func (g *Game) generateGate() *spriteutils.Sprite {
+	spire := g.generateSprite(g.topSpireFactory)
+	_, height := spire.Image.Size()
+	minTopY := screenHeight - height*2 - gateGap
+	spire.Y = minTopY + g.randIntn("gate y", gateMaxTopY-minTopY+1)
+	return spire
+}
+
+// addGate adds a laser gate's top spire and the bottom spire lined up below it to the world, the beam starting at a
+// random point in its cycle
+func (g *Game) addGate(top *spriteutils.Sprite) {
+	_, height := top.Image.Size()
+	bottom := g.sprites.get()
+	*bottom = *top
+	bottom.Image = g.stageImage(imageBottomSpire)
+	bottom.Y = top.Y + height + gateGap
+
+	g.gates = append(g.gates, &gate{
+		top:        g.addEntity(entitySpire, top),
+		bottom:     g.addEntity(entitySpire, bottom),
+		startFrame: g.frameCount - int64(g.randIntn("gate phase", gateCycleFrames)),
+	})
+}
+
+// cycleFrame returns how many frames into its current on and off cycle the gate is
+func (t *gate) cycleFrame(frame int64) int64 {
+	return (frame - t.startFrame) % gateCycleFrames
+}
+
+// isActive returns whether the gate's beam is on
+func (t *gate) isActive(frame int64) bool {
+	return t.cycleFrame(frame) < gateActiveFrames
+}
+
+// beam returns the left, top, width and height of the gate's beam, between the tips of its spires
+func (t *gate) beam() (float64, float64, float64, float64) {
+	width, height := t.top.Image.Size()
+	x := float64(t.top.X) + float64(width-gateBeamWidth)/2
+	y := float64(t.top.Y + height)
+	return x, y, gateBeamWidth, float64(t.bottom.Y) - y
+}
+
+// updateGates drops the laser gates whose spires aren't both in the world anymore
+func (g *Game) updateGates() {
+	if len(g.gates) == 0 {
+		return
+	}
+
+	inWorld := map[*entity]bool{}
+	for _, e := range g.entities {
+		inWorld[e] = true
+	}
+
+	temp := g.gates[:0]
+	for _, t := range g.gates {
+		if inWorld[t.top] && inWorld[t.bottom] {
+			temp = append(temp, t)
+		}
+	}
+	for i := len(temp); i < len(g.gates); i++ {
+		g.gates[i] = nil
+	}
+	g.gates = temp
+}
+
+// checkGateCollisions ends the run if a ship touches the beam of a laser gate while it is on.  Shields don't protect from it
+func (g *Game) checkGateCollisions() {
+	for _, t := range g.gates {
+		if !t.isActive(g.frameCount) {
+			continue
+		}
+		x, y, width, height := t.beam()
+		beam := image.Rect(int(x), int(y), int(x+width), int(y+height))
+		for _, ship := range g.ships() {
+			shipWidth, shipHeight := ship.Image.Size()
+			if beam.Overlaps(image.Rect(ship.X, ship.Y, ship.X+shipWidth, ship.Y+shipHeight)) {
+				g.mode = ModeGameOver
+			}
+		}
+	}
+}
+
+// drawGates draws the beam of each laser gate while it is on, pulsing, and flickering faintly just before it turns on
+func (g *Game) drawGates(r Renderer) {
+	texture := g.assets.Image(gateBeamImageKey(int(g.frameCount/gateBeamFrameLength) % gateBeamFrames))
+	for _, t := range g.gates {
+		var alpha float64
+		switch frame := t.cycleFrame(g.frameCount); {
+		case frame < gateActiveFrames:
+			alpha = 0.8 + 0.2*math.Sin(float64(g.frameCount)*0.5)
+		case frame >= gateCycleFrames-gateWarningFrames && g.frameCount%6 < 3:
+			alpha = 0.25
+		default:
+			continue
+		}
+
+		x, y, _, height := t.beam()
+		op := &Transform{}
+		op.Scale(1, height/gateBeamTextureHeight)
+		op.Translate(x, y)
+		op.ScaleColor(1, 1, 1, alpha)
+		r.DrawImage(texture, op)
+	}
+}
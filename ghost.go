@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ghostFileName is the name of the file the best run's trajectory is saved to, kept apart from the signed save file
+	ghostFileName = "ghost.json"
+	// ghostSampleMeters is how many meters apart the ship's position is recorded
+	ghostSampleMeters = 10
+	// ghostAlpha is the opacity the ghost ship is drawn with
+	ghostAlpha = 0.35
+)
+
+// Ghost is the trajectory of the ship during the best run, raced against as a translucent ship in later runs
+type Ghost struct {
+	// SampleMeters is how many meters apart each position was recorded
+	SampleMeters int `json:"sampleMeters"`
+	// Ys are the vertical positions of the ship, one every SampleMeters from the start of the run
+	Ys []int `json:"ys"`
+}
+
+// ghostPath returns the location of the ghost file
+func ghostPath() (string, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ghostFileName), nil
+}
+
+// loadGhost reads the ghost file, returning nil if no best run has been recorded yet
+func loadGhost() (*Ghost, error) {
+	path, err := ghostPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ghost := &Ghost{}
+	if err := json.Unmarshal(data, ghost); err != nil {
+		return nil, err
+	}
+	if ghost.SampleMeters <= 0 {
+		return nil, errors.New("ghost file has no sample distance")
+	}
+	return ghost, nil
+}
+
+// save writes the ghost to the ghost file, creating its directory if required
+func (gh *Ghost) save() error {
+	path, err := ghostPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(gh)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// yAt returns where the ship was at the given distance, interpolated between the recorded positions.  It returns false past
+// the end of the run
+func (gh *Ghost) yAt(distance int) (float64, bool) {
+	i := distance / gh.SampleMeters
+	if distance < 0 || i+1 >= len(gh.Ys) {
+		return 0, false
+	}
+	t := float64(distance%gh.SampleMeters) / float64(gh.SampleMeters)
+	return float64(gh.Ys[i]) + float64(gh.Ys[i+1]-gh.Ys[i])*t, true
+}
+
+// recordGhost records the ship's position every ghostSampleMeters, so the run can become the new ghost
+func (g *Game) recordGhost() {
+	for len(g.ghostRecording)*ghostSampleMeters <= g.distanceTravelled {
+		g.ghostRecording = append(g.ghostRecording, g.ship.Y)
+	}
+}
+
+// saveGhost makes the run just finished the ghost raced in later runs
+func (g *Game) saveGhost() error {
+	g.ghost = &Ghost{SampleMeters: ghostSampleMeters, Ys: g.ghostRecording}
+	return g.ghost.save()
+}
+
+// drawGhost draws a translucent ship where the best run's ship was at the same distance
+func (g *Game) drawGhost(r Renderer) {
+	if g.ghost == nil || g.mode != ModeGame {
+		return
+	}
+
+	y, ok := g.ghost.yAt(g.distanceTravelled)
+	if !ok {
+		return
+	}
+
+	op := &Transform{}
+	op.Translate(float64(g.ship.X), y)
+	op.ScaleColor(0.6, 0.8, 1, ghostAlpha)
+	r.DrawImage(g.assets.Image(imageShip), op)
+}
@@ -5,11 +5,13 @@ go 1.17
 require (
 	github.com/hajimehoshi/ebiten v1.12.12
 	github.com/llrowat/spriteutils v0.1.1
+	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/image v0.0.0-20211028202545-6944b10bf410
 )
 
 require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20200707082815-5321531c36a2 // indirect
+	github.com/hajimehoshi/oto v0.6.8 // indirect
 	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
 	golang.org/x/mobile v0.0.0-20210208171126-f462b3930c8f // indirect
 	golang.org/x/sys v0.0.0-20211117180635-dee7805ff2e1 // indirect
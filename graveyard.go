@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	// graveyardExplosionsPerDebris is how many asteroids have to explode during a run for each piece of background debris
+	graveyardExplosionsPerDebris = 3
+	// maxGraveyardDebris is the most background debris a run collects
+	maxGraveyardDebris = 120
+	// graveyardParallax is how fast background debris drifts compared to the world, so it looks far away
+	graveyardParallax = 0.2
+)
+
+// graveyardDebris is a piece of a destroyed asteroid drifting in the background, left behind by the run's explosions
+type graveyardDebris struct {
+	// x and y are the position of the debris on screen
+	x, y float64
+	// yVelocity is how fast the debris drifts up or down
+	yVelocity float64
+	// rotation is the angle of the debris
+	rotation float64
+	// spin is how much the debris rotates each frame
+	spin float64
+	// scale is the size of the debris compared to a small asteroid
+	scale float64
+	// shade is how bright the debris is drawn, darker pieces look further away
+	shade float64
+}
+
+// addGraveyardDebris counts an exploded asteroid, adding a piece of debris to the background every graveyardExplosionsPerDebris.
+// The debris enters from the right edge, so the background fills up as the run goes on
+func (g *Game) addGraveyardDebris() {
+	g.asteroidsExploded++
+	if g.asteroidsExploded%graveyardExplosionsPerDebris != 0 || len(g.graveyard) >= maxGraveyardDebris {
+		return
+	}
+
+	width, _ := g.assets.Image(imageSmallAsteroid).Size()
+	g.graveyard = append(g.graveyard, graveyardDebris{
+		x:         float64(screenWidth + width),
+		y:         rand.Float64() * float64(screenHeight),
+		yVelocity: (rand.Float64() - 0.5) * 0.2,
+		rotation:  rand.Float64() * 2 * math.Pi,
+		spin:      (rand.Float64() - 0.5) * 0.02,
+		scale:     0.3 + rand.Float64()*0.7,
+		shade:     0.2 + rand.Float64()*0.3,
+	})
+}
+
+// updateGraveyard drifts the background debris slower than the world, wrapping it around the screen so it stays for the whole run
+func (g *Game) updateGraveyard() {
+	width, height := g.assets.Image(imageSmallAsteroid).Size()
+	for i := range g.graveyard {
+		debris := &g.graveyard[i]
+		debris.x -= g.speed * graveyardParallax
+		debris.y += debris.yVelocity
+		debris.rotation += debris.spin
+
+		if debris.x < -float64(width) {
+			debris.x += float64(screenWidth + width*2)
+		}
+		if debris.y < -float64(height) {
+			debris.y += float64(screenHeight + height*2)
+		} else if debris.y > float64(screenHeight+height) {
+			debris.y -= float64(screenHeight + height*2)
+		}
+	}
+}
+
+// drawGraveyard draws the background debris, dimmed behind the world
+func (g *Game) drawGraveyard(r Renderer) {
+	width, height := g.assets.Image(imageSmallAsteroid).Size()
+	for _, debris := range g.graveyard {
+		op := &Transform{}
+		op.Translate(-float64(width)/2, -float64(height)/2)
+		op.Rotate(debris.rotation)
+		op.Scale(debris.scale, debris.scale)
+		op.Translate(debris.x, debris.y)
+		op.ScaleColor(debris.shade, debris.shade, debris.shade, 0.8)
+		r.DrawImage(g.assets.Image(imageSmallAsteroid), op)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"math"
+)
+
+const (
+	// growScale is how much bigger the ship is while enlarged
+	growScale = 1.5
+)
+
+// scaleKey identifies a scaled copy of an image
+type scaleKey struct {
+	image *ebiten.Image
+	scale float64
+}
+
+// scaledImages are the scaled copies of images made so far, so each is only made once
+var scaledImages = map[scaleKey]*ebiten.Image{}
+
+// scaledImage returns a copy of an image scaled at runtime.  Sprites drawn with the copy collide with its scaled size.
+// The image itself is returned for a scale of 1, or if the copy can't be made
+func scaledImage(image *ebiten.Image, scale float64) *ebiten.Image {
+	if scale == 1 {
+		return image
+	}
+	key := scaleKey{image: image, scale: scale}
+	if scaled, ok := scaledImages[key]; ok {
+		return scaled
+	}
+
+	width, height := image.Size()
+	scaled, err := ebiten.NewImage(int(math.Round(float64(width)*scale)), int(math.Round(float64(height)*scale)), ebiten.FilterDefault)
+	if err != nil {
+		log.Println(err)
+		return image
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Filter = ebiten.FilterLinear
+	scaled.DrawImage(image, op)
+
+	scaledImages[key] = scaled
+	return scaled
+}
+
+// setShipScale resizes every ship about its center, which also resizes its hitbox
+func (g *Game) setShipScale(scale float64) {
+	g.shipScale = scale
+	for _, ship := range g.ships() {
+		oldWidth, oldHeight := ship.Image.Size()
+		ship.Image = scaledImage(g.assets.Image(imageShip), scale)
+		width, height := ship.Image.Size()
+		ship.X -= (width - oldWidth) / 2
+		ship.Y -= (height - oldHeight) / 2
+	}
+}
+
+// growEffect is the effect of the grow hazard, enlarging the ships so they are harder to steer between hazards
+type growEffect struct{}
+
+// Apply enlarges the ships
+func (growEffect) Apply(g *Game) {
+	g.setShipScale(growScale)
+}
+
+// Expire returns the ships to their normal size
+func (growEffect) Expire(g *Game) {
+	g.setShipScale(1)
+}
+
+// updateDisguise shows a disguised pickup as its disguise until a ship gets within its reveal range
+func (g *Game) updateDisguise(pickup *entity) {
+	disguise := pickup.powerUp.Disguise
+	if disguise == nil {
+		return
+	}
+
+	pickup.Image = disguise
+	pickupX, pickupY := spriteCenter(pickup.Sprite)
+	for _, ship := range g.ships() {
+		shipX, shipY := spriteCenter(ship)
+		if math.Hypot(shipX-pickupX, shipY-pickupY) <= pickup.powerUp.RevealRange {
+			pickup.Image = pickup.powerUp.Factory.Images[0]
+			return
+		}
+	}
+}
+
+// createShield creates the shield sprite around a ship, scaled with the ship
+func (g *Game) createShield(ship *spriteutils.Sprite) *spriteutils.Sprite {
+	scale := g.shipScale
+	if scale == 0 {
+		scale = 1
+	}
+	return &spriteutils.Sprite{
+		Image: scaledImage(g.assets.Image(imageShield), scale),
+		X:     ship.X - int(17*scale),
+		Y:     ship.Y - int(15*scale),
+	}
+}
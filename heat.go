@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+const (
+	// overheatFrames is how many frames thrust is disabled for after the engine overheats
+	overheatFrames = 90
+	// heatMeterWidth is the width of the engine heat gauge
+	heatMeterWidth = 150
+	// heatMeterHeight is the height of the engine heat gauge
+	heatMeterHeight = 8
+)
+
+// overheatColor is the color of the engine heat gauge while the engine is overheated
+var overheatColor = color.RGBA{0xff, 0x44, 0x22, 0xff}
+
+// isHeatEnabled returns whether the engine heat mechanic is part of the selected difficulty
+func (g *Game) isHeatEnabled() bool {
+	return g.difficulty().HeatPerFrame > 0
+}
+
+// isOverheated returns whether thrust is disabled because the engine overheated
+func (g *Game) isOverheated() bool {
+	return g.frameCount < g.overheatEndFrame
+}
+
+// updateHeat builds engine heat while thrusting and dissipates it otherwise.  Reaching full heat overheats the engine,
+// disabling thrust for overheatFrames and resetting the heat once it recovers
+func (g *Game) updateHeat(isThrusting bool) {
+	if !g.isHeatEnabled() || g.isOverheated() {
+		return
+	}
+
+	difficulty := g.difficulty()
+	if isThrusting {
+		g.engineHeat += difficulty.HeatPerFrame
+	} else {
+		g.engineHeat -= difficulty.CoolingPerFrame * g.upgradeFactor(shopHeatSink, 1.5)
+	}
+	g.engineHeat = math.Max(0, g.engineHeat)
+
+	if g.engineHeat >= 1 {
+		g.engineHeat = 0
+		g.overheatEndFrame = g.frameCount + overheatFrames
+	}
+}
+
+// coolEngine dissipates all engine heat at once
+func (g *Game) coolEngine() {
+	g.engineHeat = 0
+}
+
+// drawHeatMeter draws the engine heat gauge below the magnet energy meter, flashing while the engine is overheated
+func (g *Game) drawHeatMeter(r Renderer) {
+	if !g.isHeatEnabled() {
+		return
+	}
+
+	theme := g.theme()
+	width, height := g.uiFloat(heatMeterWidth), g.uiFloat(heatMeterHeight)
+	x := g.hudXFloat(width, g.uiFloat(fontSize))
+	y := g.uiFloat(fontSize + smallFontSize*7)
+
+	r.DrawRect(x, y, width, height, theme.Panel())
+	if g.isOverheated() {
+		if (g.frameCount/8)%2 == 0 {
+			r.DrawRect(x, y, width, height, overheatColor)
+		}
+		return
+	}
+	r.DrawRect(x, y, width*g.engineHeat, height, theme.Accent())
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// homingChanceDistance is how far into a run each 1 in 1000 chance of an asteroid homing in on the ship comes
+	homingChanceDistance = 100
+	// homingMaxChance is the highest chance out of 1000 an asteroid has of homing in on the ship
+	homingMaxChance = 150
+	// homingSteer is the fraction of the distance to the ship's height a homing asteroid's vertical velocity moves by each frame
+	homingSteer = 0.002
+	// homingMaxAcceleration is the most a homing asteroid's vertical velocity changes by in a frame
+	homingMaxAcceleration = 0.08
+	// homingMaxSpeed is the fastest a homing asteroid moves up or down
+	homingMaxSpeed = 3
+)
+
+// isHomingSpawn decides whether the next asteroid homes in on the ship.  They are rare at first, getting more common the
+// further the run goes
+func (g *Game) isHomingSpawn() bool {
+	chance := int(math.Min(float64(g.distanceTravelled/homingChanceDistance), homingMaxChance))
+	return g.randIntn("homing asteroid", 1000) < chance
+}
+
+// addHomingAsteroid adds a homing asteroid to the world.  It is an asteroid like any other, that also steers toward the ship
+func (g *Game) addHomingAsteroid(sprite *spriteutils.Sprite) {
+	g.addEntity(entityAsteroid, sprite).isHoming = true
+	g.trackAsteroidPath(sprite)
+}
+
+// updateHomingAsteroids gently steers every homing asteroid toward the nearest ship's height.  They give up once they
+// have passed the ship, and don't steer while frozen
+func (g *Game) updateHomingAsteroids() {
+	for _, e := range g.entities {
+		if !e.isHoming || g.isFrozen(e.Sprite) {
+			continue
+		}
+
+		x, y := spriteCenter(e.Sprite)
+		shipX, shipY := spriteCenter(g.nearestShip(x, y))
+		if x < shipX {
+			continue
+		}
+		steer := math.Max(-homingMaxAcceleration, math.Min(homingMaxAcceleration, (shipY-y)*homingSteer))
+		e.YVelocity = math.Max(-homingMaxSpeed, math.Min(homingMaxSpeed, e.YVelocity+steer))
+	}
+}
+
+// drawHomingGlow draws a red glow over every homing asteroid, so they can be told apart from the asteroids that fly straight
+func (g *Game) drawHomingGlow(r Renderer) {
+	for _, e := range g.entities {
+		if !e.isHoming || isFarOffScreen(e.Sprite) {
+			continue
+		}
+		op := spriteTransform(e.Sprite)
+		op.ScaleColor(0, 0, 0, 0.45)
+		op.TranslateColor(1, 0.15, 0.1, 0)
+		r.DrawImage(e.Image, op)
+	}
+}
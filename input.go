@@ -0,0 +1,294 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/inpututil"
+)
+
+// Input reports the keyboard, mouse and gamepad state the game reads each update, so synthetic input can drive the game in place of the player
+type Input interface {
+	// IsKeyPressed returns whether a key is held down
+	IsKeyPressed(key ebiten.Key) bool
+	// IsKeyJustPressed returns whether a key was pressed this update
+	IsKeyJustPressed(key ebiten.Key) bool
+	// IsMouseButtonPressed returns whether a mouse button is held down
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	// InputChars returns the characters typed this update
+	InputChars() []rune
+	// JustConnectedGamepadIDs returns the IDs of the gamepads connected this update
+	JustConnectedGamepadIDs() []int
+	// IsGamepadJustDisconnected returns whether a gamepad was disconnected this update
+	IsGamepadJustDisconnected(id int) bool
+	// GamepadName returns the name of a connected gamepad
+	GamepadName(id int) string
+	// IsGamepadButtonPressed returns whether a button is held on a gamepad
+	IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool
+	// IsGamepadButtonJustPressed returns whether a button was pressed on a gamepad this update
+	IsGamepadButtonJustPressed(id int, button ebiten.GamepadButton) bool
+	// GamepadAxis returns how far a stick axis of a gamepad is pushed, from -1 to 1
+	GamepadAxis(id int, axis int) float64
+}
+
+// ebitenInput is the Input read from the player's keyboard, mouse and gamepads
+type ebitenInput struct{}
+
+// IsKeyPressed returns whether a key is held down
+func (ebitenInput) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+// IsKeyJustPressed returns whether a key was pressed this update
+func (ebitenInput) IsKeyJustPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key)
+}
+
+// IsMouseButtonPressed returns whether a mouse button is held down
+func (ebitenInput) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+// InputChars returns the characters typed this update
+func (ebitenInput) InputChars() []rune {
+	return ebiten.InputChars()
+}
+
+// JustConnectedGamepadIDs returns the IDs of the gamepads connected this update
+func (ebitenInput) JustConnectedGamepadIDs() []int {
+	return inpututil.JustConnectedGamepadIDs()
+}
+
+// IsGamepadJustDisconnected returns whether a gamepad was disconnected this update
+func (ebitenInput) IsGamepadJustDisconnected(id int) bool {
+	return inpututil.IsGamepadJustDisconnected(id)
+}
+
+// GamepadName returns the name of a connected gamepad
+func (ebitenInput) GamepadName(id int) string {
+	return ebiten.GamepadName(id)
+}
+
+// IsGamepadButtonPressed returns whether a button is held on a gamepad
+func (ebitenInput) IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool {
+	return ebiten.IsGamepadButtonPressed(id, button)
+}
+
+// IsGamepadButtonJustPressed returns whether a button was pressed on a gamepad this update
+func (ebitenInput) IsGamepadButtonJustPressed(id int, button ebiten.GamepadButton) bool {
+	return inpututil.IsGamepadButtonJustPressed(id, button)
+}
+
+// GamepadAxis returns how far a stick axis of a gamepad is pushed, from -1 to 1
+func (ebitenInput) GamepadAxis(id int, axis int) float64 {
+	return ebiten.GamepadAxis(id, axis)
+}
+
+// scriptedGamepadButton is a button on a particular gamepad
+type scriptedGamepadButton struct {
+	// id is the ID of the gamepad
+	id int
+	// button is the button on the gamepad
+	button ebiten.GamepadButton
+}
+
+// scriptedGamepadAxis is a stick axis on a particular gamepad
+type scriptedGamepadAxis struct {
+	// id is the ID of the gamepad
+	id int
+	// axis is the axis of the stick
+	axis int
+}
+
+// scriptedInput is an Input fed synthetic key, mouse and gamepad events, for driving the game deterministically from
+// automated runs.  Events take effect on the next update, and step must be called after each update to move time on
+type scriptedInput struct {
+	// keyFrames is how many updates each held key has been held for, 0 on the update it was pressed
+	keyFrames map[ebiten.Key]int
+	// buttons are the mouse buttons held down
+	buttons map[ebiten.MouseButton]bool
+	// chars are the characters typed for the next update
+	chars []rune
+	// connected are the IDs of the gamepads connected for the next update
+	connected []int
+	// disconnected are the IDs of the gamepads disconnected for the next update
+	disconnected map[int]bool
+	// gamepadButtonFrames is how many updates each held gamepad button has been held for, 0 on the update it was pressed
+	gamepadButtonFrames map[scriptedGamepadButton]int
+	// gamepadAxes are how far each pushed stick axis is pushed
+	gamepadAxes map[scriptedGamepadAxis]float64
+}
+
+// newScriptedInput creates a scripted input with nothing held down and no gamepads connected
+func newScriptedInput() *scriptedInput {
+	return &scriptedInput{
+		keyFrames:           map[ebiten.Key]int{},
+		buttons:             map[ebiten.MouseButton]bool{},
+		disconnected:        map[int]bool{},
+		gamepadButtonFrames: map[scriptedGamepadButton]int{},
+		gamepadAxes:         map[scriptedGamepadAxis]float64{},
+	}
+}
+
+// press holds a key down from the next update until it is released
+func (s *scriptedInput) press(key ebiten.Key) {
+	if _, ok := s.keyFrames[key]; !ok {
+		s.keyFrames[key] = 0
+	}
+}
+
+// release lets go of a key
+func (s *scriptedInput) release(key ebiten.Key) {
+	delete(s.keyFrames, key)
+}
+
+// tap presses a key for a single update
+func (s *scriptedInput) tap(g *Game, key ebiten.Key) error {
+	s.press(key)
+	err := s.run(g, 1)
+	s.release(key)
+	return err
+}
+
+// pressMouseButton holds a mouse button down from the next update until it is released
+func (s *scriptedInput) pressMouseButton(button ebiten.MouseButton) {
+	s.buttons[button] = true
+}
+
+// releaseMouseButton lets go of a mouse button
+func (s *scriptedInput) releaseMouseButton(button ebiten.MouseButton) {
+	delete(s.buttons, button)
+}
+
+// typeChars types characters for the next update
+func (s *scriptedInput) typeChars(chars string) {
+	s.chars = append(s.chars, []rune(chars)...)
+}
+
+// connectGamepad connects a gamepad on the next update
+func (s *scriptedInput) connectGamepad(id int) {
+	s.connected = append(s.connected, id)
+}
+
+// disconnectGamepad disconnects a gamepad on the next update, letting go of everything held on it
+func (s *scriptedInput) disconnectGamepad(id int) {
+	s.disconnected[id] = true
+	for held := range s.gamepadButtonFrames {
+		if held.id == id {
+			delete(s.gamepadButtonFrames, held)
+		}
+	}
+	for pushed := range s.gamepadAxes {
+		if pushed.id == id {
+			delete(s.gamepadAxes, pushed)
+		}
+	}
+}
+
+// pressGamepadButton holds a button on a gamepad down from the next update until it is released
+func (s *scriptedInput) pressGamepadButton(id int, button ebiten.GamepadButton) {
+	held := scriptedGamepadButton{id: id, button: button}
+	if _, ok := s.gamepadButtonFrames[held]; !ok {
+		s.gamepadButtonFrames[held] = 0
+	}
+}
+
+// releaseGamepadButton lets go of a button on a gamepad
+func (s *scriptedInput) releaseGamepadButton(id int, button ebiten.GamepadButton) {
+	delete(s.gamepadButtonFrames, scriptedGamepadButton{id: id, button: button})
+}
+
+// tapGamepadButton presses a button on a gamepad for a single update
+func (s *scriptedInput) tapGamepadButton(g *Game, id int, button ebiten.GamepadButton) error {
+	s.pressGamepadButton(id, button)
+	err := s.run(g, 1)
+	s.releaseGamepadButton(id, button)
+	return err
+}
+
+// pushGamepadAxis pushes a stick axis of a gamepad from the next update, 0 letting it go
+func (s *scriptedInput) pushGamepadAxis(id int, axis int, value float64) {
+	if value == 0 {
+		delete(s.gamepadAxes, scriptedGamepadAxis{id: id, axis: axis})
+	} else {
+		s.gamepadAxes[scriptedGamepadAxis{id: id, axis: axis}] = value
+	}
+}
+
+// step moves the input on by one update, so held keys and buttons are no longer just pressed, and typed characters and
+// gamepad connections are used up
+func (s *scriptedInput) step() {
+	for key := range s.keyFrames {
+		s.keyFrames[key]++
+	}
+	for held := range s.gamepadButtonFrames {
+		s.gamepadButtonFrames[held]++
+	}
+	s.chars = nil
+	s.connected = nil
+	s.disconnected = map[int]bool{}
+}
+
+// run updates the game for a number of frames with this input, stopping at the first error
+func (s *scriptedInput) run(g *Game, frames int) error {
+	g.input = s
+	for i := 0; i < frames; i++ {
+		if err := g.Update(nil); err != nil {
+			return err
+		}
+		s.step()
+	}
+	return nil
+}
+
+// IsKeyPressed returns whether a key is held down
+func (s *scriptedInput) IsKeyPressed(key ebiten.Key) bool {
+	_, ok := s.keyFrames[key]
+	return ok
+}
+
+// IsKeyJustPressed returns whether a key was pressed this update
+func (s *scriptedInput) IsKeyJustPressed(key ebiten.Key) bool {
+	frames, ok := s.keyFrames[key]
+	return ok && frames == 0
+}
+
+// IsMouseButtonPressed returns whether a mouse button is held down
+func (s *scriptedInput) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return s.buttons[button]
+}
+
+// InputChars returns the characters typed this update
+func (s *scriptedInput) InputChars() []rune {
+	return s.chars
+}
+
+// JustConnectedGamepadIDs returns the IDs of the gamepads connected this update
+func (s *scriptedInput) JustConnectedGamepadIDs() []int {
+	return s.connected
+}
+
+// IsGamepadJustDisconnected returns whether a gamepad was disconnected this update
+func (s *scriptedInput) IsGamepadJustDisconnected(id int) bool {
+	return s.disconnected[id]
+}
+
+// GamepadName returns the name of a connected gamepad
+func (s *scriptedInput) GamepadName(id int) string {
+	return "scripted"
+}
+
+// IsGamepadButtonPressed returns whether a button is held on a gamepad
+func (s *scriptedInput) IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool {
+	_, ok := s.gamepadButtonFrames[scriptedGamepadButton{id: id, button: button}]
+	return ok
+}
+
+// IsGamepadButtonJustPressed returns whether a button was pressed on a gamepad this update
+func (s *scriptedInput) IsGamepadButtonJustPressed(id int, button ebiten.GamepadButton) bool {
+	frames, ok := s.gamepadButtonFrames[scriptedGamepadButton{id: id, button: button}]
+	return ok && frames == 0
+}
+
+// GamepadAxis returns how far a stick axis of a gamepad is pushed, from -1 to 1
+func (s *scriptedInput) GamepadAxis(id int, axis int) float64 {
+	return s.gamepadAxes[scriptedGamepadAxis{id: id, axis: axis}]
+}
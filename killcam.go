@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// killCamFrames is the number of frames kept for the kill-cam (5 seconds at 60 frames per second)
+	killCamFrames = 5 * 60
+	// killCamZoom is the scale the kill-cam is drawn at, slightly zoomed out so more of the world is visible
+	killCamZoom = 0.85
+	// replayDirName is the directory under the profile directory that saved replays are written to
+	replayDirName = "replays"
+)
+
+// killCamInput is the player's input during a single frame
+type killCamInput struct {
+	// thrust represents whether thrust was held
+	thrust bool
+	// laser represents whether the laser was held
+	laser bool
+}
+
+// killCamFrame is a snapshot of every sprite in the world and the player's input during a single frame
+type killCamFrame struct {
+	// sprites are copies of the world sprites, in draw order
+	sprites []spriteutils.Sprite
+	// input is the player's input that frame
+	input killCamInput
+}
+
+// killCam is a ring buffer of the most recent world snapshots and inputs, used to replay the moments leading up to death
+type killCam struct {
+	// frames is the ring buffer of snapshots
+	frames [killCamFrames]killCamFrame
+	// next is the index in frames that the next snapshot will be written to
+	next int
+	// count is the number of snapshots currently held in frames
+	count int
+	// playbackFrame is the number of frames that have been played back since playback started
+	playbackFrame int
+	// saved represents whether the current kill-cam has been saved as a replay
+	saved bool
+}
+
+// reset discards all recorded snapshots
+func (k *killCam) reset() {
+	k.next = 0
+	k.count = 0
+	k.playbackFrame = 0
+	k.saved = false
+}
+
+// record stores a snapshot of the given sprites and input, overwriting the oldest snapshot once the buffer is full
+func (k *killCam) record(sprites []*spriteutils.Sprite, input killCamInput) {
+	frame := &k.frames[k.next]
+	frame.sprites = frame.sprites[:0]
+	for _, sprite := range sprites {
+		frame.sprites = append(frame.sprites, *sprite)
+	}
+	frame.input = input
+
+	k.next = (k.next + 1) % killCamFrames
+	if k.count < killCamFrames {
+		k.count++
+	}
+}
+
+// startPlayback rewinds playback to the oldest recorded snapshot
+func (k *killCam) startPlayback() {
+	k.playbackFrame = 0
+}
+
+// isFinished returns whether every recorded snapshot has been played back
+func (k *killCam) isFinished() bool {
+	return k.playbackFrame >= k.count
+}
+
+// frameAt returns the i-th recorded snapshot, counting from the oldest
+func (k *killCam) frameAt(i int) *killCamFrame {
+	return &k.frames[(k.next-k.count+i+killCamFrames)%killCamFrames]
+}
+
+// currentFrame returns the snapshot that should be shown for the current point in playback
+func (k *killCam) currentFrame() *killCamFrame {
+	if k.count == 0 {
+		return nil
+	}
+	i := k.playbackFrame
+	if i >= k.count {
+		i = k.count - 1
+	}
+	return k.frameAt(i)
+}
+
+// worldSprites returns every sprite in the world that could be on screen, in the order they are drawn.
+// Sprites far off screen still move, but are left out so no time is spent drawing or recording them
+func (g *Game) worldSprites() []*spriteutils.Sprite {
+	var sprites []*spriteutils.Sprite
+	appendVisible := func(candidates ...*spriteutils.Sprite) {
+		for _, sprite := range candidates {
+			if !isFarOffScreen(sprite) {
+				sprites = append(sprites, sprite)
+			}
+		}
+	}
+
+	appendVisible(g.entitySprites(entityPickup)...)
+	appendVisible(g.entitySprites(entitySpire)...)
+	appendVisible(g.entitySprites(entityGround)...)
+	appendVisible(g.entitySprites(entityWater)...)
+	appendVisible(g.entitySprites(entityAsteroid)...)
+	for _, ring := range g.rings {
+		appendVisible(ring.sprites()...)
+	}
+	appendVisible(g.entitySprites(entityProjectile)...)
+	appendVisible(g.entitySprites(entityBoss)...)
+	appendVisible(g.entitySprites(entityUFO)...)
+	appendVisible(g.entitySprites(entityBolt)...)
+	sprites = append(sprites, g.ships()...)
+	sprites = append(sprites, g.shields()...)
+	return sprites
+}
+
+// recordKillCamFrame records the current state of the world and the player's input into the kill-cam
+func (g *Game) recordKillCamFrame() {
+	input := killCamInput{thrust: g.isThrustPressed(), laser: g.isLaserPressed()}
+	g.killCam.record(append(g.worldSprites(), g.particles.sprites()...), input)
+}
+
+// updateKillCam advances kill-cam playback and handles the skip and save options
+func (g *Game) updateKillCam() {
+	if g.input.IsKeyJustPressed(ebiten.KeySpace) {
+		g.mode = ModeGameOver
+		return
+	}
+
+	if g.input.IsKeyJustPressed(ebiten.KeyS) && !g.killCam.saved {
+		if err := g.killCam.save(g.currentLoadout()); err != nil {
+			log.Println(err)
+		} else {
+			g.killCam.saved = true
+		}
+	}
+
+	g.killCam.playbackFrame++
+	if g.killCam.isFinished() {
+		g.mode = ModeGameOver
+	}
+}
+
+// drawKillCam draws the current kill-cam snapshot, zoomed out around the center of the screen
+func (g *Game) drawKillCam(r Renderer) {
+	frame := g.killCam.currentFrame()
+	if frame == nil {
+		return
+	}
+
+	r.SetCamera(Camera{Zoom: killCamZoom})
+	for i := range frame.sprites {
+		r.DrawSprite(&frame.sprites[i])
+	}
+	r.SetCamera(defaultCamera)
+
+	g.entityCount = len(frame.sprites)
+}
+
+// drawKillCamText draws the kill-cam caption and controls
+func (g *Game) drawKillCamText(r Renderer) {
+	theme := g.theme()
+	r.DrawText("REPLAY", g.fonts().Normal, g.ui(fontSize), g.ui(fontSize)*2, theme.Text())
+
+	// Show what the player was holding, so the replay explains the moments leading up to death
+	if frame := g.killCam.currentFrame(); frame != nil {
+		held := ""
+		if frame.input.thrust {
+			held += "THRUST "
+		}
+		if frame.input.laser {
+			held += "LASER"
+		}
+		r.DrawText(held, g.fonts().Small, g.ui(fontSize), g.ui(fontSize)*3, theme.Text())
+	}
+
+	controls := "SPACE: SKIP   S: SAVE REPLAY"
+	if g.killCam.saved {
+		controls = "SPACE: SKIP   REPLAY SAVED"
+	}
+	r.DrawText(controls, g.fonts().Small, g.ui(fontSize), screenHeight-g.ui(fontSize), theme.Text())
+}
+
+// save writes the recorded snapshots to a replay file in the profile directory, along with the loadout the run was played with
+func (k *killCam) save(loadout Loadout) error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, replayDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("replay-%d.json.gz", time.Now().Unix())))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer, err := newReplayWriter(file, loadout)
+	if err != nil {
+		return err
+	}
+	var entities []savedEntity
+	for i := 0; i < k.count; i++ {
+		frame := k.frameAt(i)
+		entities = entities[:0]
+		for j := range frame.sprites {
+			entity, err := encodeEntity(&frame.sprites[j])
+			if err != nil {
+				return err
+			}
+			entities = append(entities, entity)
+		}
+		if err := writer.writeFrame(entities); err != nil {
+			return err
+		}
+	}
+	if err := writer.close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,54 @@
+package main
+
+import "github.com/hajimehoshi/ebiten"
+
+const (
+	// laserKey is the key held to fire the laser
+	laserKey = ebiten.KeyX
+	// laserMouseButton is the mouse button held to fire the laser
+	laserMouseButton = ebiten.MouseButtonRight
+	// gamepadButtonX is the X button on a standard controller, it fires the laser
+	gamepadButtonX = ebiten.GamepadButton2
+	// laserCooldownFrames is the fewest frames between two shots
+	laserCooldownFrames = 20
+	// laserSpeed is how far a projectile travels across the screen each frame
+	laserSpeed = 12
+	// maxProjectiles is the most projectiles allowed in the world at once
+	maxProjectiles = 30
+)
+
+// isLaserPressed returns whether the laser is being fired with the keyboard, mouse or a gamepad
+func (g *Game) isLaserPressed() bool {
+	return g.input.IsKeyPressed(laserKey) || g.input.IsMouseButtonPressed(laserMouseButton) || g.isGamepadPressed(gamepadButtonX)
+}
+
+// updateLaser fires a projectile from the front of every ship while the laser is held, no faster than the cooldown allows
+func (g *Game) updateLaser() {
+	if !g.isLaserPressed() || g.frameCount-g.lastLaserFrame < laserCooldownFrames {
+		return
+	}
+	g.lastLaserFrame = g.frameCount
+
+	laserWidth, laserHeight := g.assets.Image(imageLaser).Size()
+	for _, ship := range g.ships() {
+		shipWidth, shipHeight := ship.Image.Size()
+		projectile := g.sprites.get()
+		projectile.Image = g.assets.Image(imageLaser)
+		projectile.X = ship.X + shipWidth - laserWidth/2
+		projectile.Y = ship.Y + (shipHeight-laserHeight)/2
+		projectile.XVelocity = laserSpeed
+		g.addEntity(entityProjectile, projectile)
+	}
+	g.audio.play(soundLaser)
+}
+
+// checkProjectileCollisions destroys every asteroid hit by a projectile, along with the projectile
+func (g *Game) checkProjectileCollisions() {
+	g.filterEntities(func(e *entity) bool {
+		if e.collider != colliderProjectile || !g.destroyAsteroidsColliding(e.Sprite, destroyedByLaser) {
+			return true
+		}
+		g.releaseEntity(e)
+		return false
+	})
+}
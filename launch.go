@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// launchChargeFrames is how many frames thrust must be held to fully charge the launch boost
+	launchChargeFrames = 120
+	// maxLaunchSpeed is the extra starting speed given by a fully charged launch
+	maxLaunchSpeed = 2
+	// maxLaunchMultiplier is the extra score multiplier given by a fully charged launch
+	maxLaunchMultiplier = 0.5
+	// launchMeterWidth is the width of the launch charge meter
+	launchMeterWidth = 300
+	// launchMeterHeight is the height of the launch charge meter
+	launchMeterHeight = 20
+)
+
+// updateLaunch charges the launch boost while thrust is held on the title screen and starts the run when it is released.
+// Start launches straight away with whatever charge has built up.  Nothing happens while a locked variant is selected
+func (g *Game) updateLaunch() {
+	if !g.isVariantUnlocked(g.variant) {
+		return
+	}
+
+	if g.isGamepadJustPressed(gamepadButtonStart) {
+		g.launch()
+		return
+	}
+
+	if g.isThrustJustPressed() {
+		g.isChargingLaunch = true
+	}
+	if !g.isChargingLaunch {
+		return
+	}
+
+	if g.isThrustPressed() {
+		g.launchCharge = math.Min(g.launchCharge+1.0/launchChargeFrames, 1)
+	} else {
+		g.launch()
+	}
+}
+
+// launchSpeedBonus returns the extra starting speed the launch charge has built up.  The world moves in whole steps, so it is
+// rounded to them
+func (g *Game) launchSpeedBonus() float64 {
+	return math.Round(g.launchCharge * maxLaunchSpeed)
+}
+
+// launchMultiplierBonus returns the extra score multiplier the launch charge has built up, in proportion to the speed bonus
+// actually given
+func (g *Game) launchMultiplierBonus() float64 {
+	return g.launchSpeedBonus() / maxLaunchSpeed * maxLaunchMultiplier
+}
+
+// launch starts the run, applying the charged launch boost to the starting speed and score multiplier
+func (g *Game) launch() {
+	g.speed += g.launchSpeedBonus()
+	g.scoreMultiplier += g.launchMultiplierBonus()
+	g.isChargingLaunch = false
+	g.mode = ModeGame
+}
+
+// score returns the score for the run, the distance travelled scaled by the score multiplier plus any bonus score
+func (g *Game) score() int {
+	return int(float64(g.distanceTravelled)*g.scoreMultiplier) + g.bonusScore
+}
+
+// drawLaunchMeter draws the launch charge meter on the title screen
+func (g *Game) drawLaunchMeter(r Renderer) {
+	width, height := g.uiFloat(launchMeterWidth), g.uiFloat(launchMeterHeight)
+	x := (float64(screenWidth) - width) / 2
+	y := float64(screenHeight) * 3 / 4
+
+	theme := g.theme()
+	r.DrawRect(x, y, width, height, theme.Panel())
+	r.DrawRect(x, y, width*g.launchCharge, height, theme.Accent())
+
+	label := fmt.Sprintf("LAUNCH BOOST: X%.2f SCORE", 1+g.launchMultiplierBonus())
+	r.DrawText(label, g.fonts().Small, int(x), int(y)-g.ui(smallFontSize)/2, theme.Text())
+}
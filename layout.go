@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"image"
+)
+
+// Orientation represents which way round the screen is laid out
+type Orientation int
+
+const (
+	// OrientationLandscape lays the screen out wider than it is tall, the way the game was designed
+	OrientationLandscape Orientation = iota
+	// OrientationPortrait lays the screen out taller than it is wide, for rotated monitors
+	OrientationPortrait
+)
+
+// orientationNames are the names of the orientations, shown in the settings menu
+var orientationNames = map[Orientation]string{
+	OrientationLandscape: "LANDSCAPE",
+	OrientationPortrait:  "PORTRAIT",
+}
+
+// orientationSizes are the sizes of the screen in each orientation
+var orientationSizes = map[Orientation]image.Point{
+	OrientationLandscape: {X: 1028, Y: 720},
+	OrientationPortrait:  {X: 540, Y: 960},
+}
+
+var (
+	// screenWidth is the width of the screen in the current orientation.  The playfield, spawn ranges, ground and HUD are all laid
+	// out from it, so it only changes between runs
+	screenWidth = orientationSizes[OrientationLandscape].X
+	// screenHeight is the height of the screen in the current orientation
+	screenHeight = orientationSizes[OrientationLandscape].Y
+)
+
+// applyOrientation lays the screen out in an orientation and resizes the window to match
+func applyOrientation(orientation Orientation) {
+	size, ok := orientationSizes[orientation]
+	if !ok {
+		size = orientationSizes[OrientationLandscape]
+	}
+	screenWidth, screenHeight = size.X, size.Y
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+
+	// The power-up factories are created once when the rules are loaded, so their spawn ranges are laid out again here
+	for _, powerUp := range powerUps {
+		powerUp.Factory = newPickupFactory(powerUp.Factory.Images[0])
+	}
+}
+
+// stepOrientation switches to the next or previous orientation.  Settings are only opened from the title screen, so the game and
+// the title preview are simply started again, laying out the ground and spawn ranges for the new screen
+func (g *Game) stepOrientation(step int) {
+	count := Orientation(len(orientationNames))
+	g.settings.Orientation = (g.settings.Orientation + Orientation(step) + count) % count
+	applyOrientation(g.settings.Orientation)
+	g.resetGame()
+	g.titlePreview.resetGame()
+}
+
+// isHUDMirrored returns whether the HUD is laid out against the left of the screen and warnings along the opposite edges, for
+// players who scan the screen the other way round
+func (g *Game) isHUDMirrored() bool {
+	return g.settings != nil && g.settings.MirroredHUD
+}
+
+// hudX returns where a HUD element of a width starts, a margin in from the right of the screen, or from the left when the HUD is mirrored
+func (g *Game) hudX(width, margin int) int {
+	if g.isHUDMirrored() {
+		return margin
+	}
+	return screenWidth - width - margin
+}
+
+// hudXFloat returns where a HUD element of a width starts, for drawing shapes
+func (g *Game) hudXFloat(width, margin float64) float64 {
+	if g.isHUDMirrored() {
+		return margin
+	}
+	return float64(screenWidth) - width - margin
+}
+
+// hudSideText returns how the side of the screen the HUD is laid out against is shown to the player
+func hudSideText(isMirrored bool) string {
+	if isMirrored {
+		return "LEFT"
+	}
+	return "RIGHT"
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// leaderboardKey is the key that opens the leaderboard screen from the title screen
+	leaderboardKey = ebiten.KeyL
+	// leaderboardSize is how many of the top entries are fetched and shown
+	leaderboardSize = 10
+	// leaderboardTimeout is how long a leaderboard request can take before it is given up on
+	leaderboardTimeout = 10 * time.Second
+	// defaultPlayerName is the name submitted to the leaderboard when none has been set
+	defaultPlayerName = "PLAYER"
+	// maxPlayerNameLength is the longest player name that can be typed, the width of the name column on the leaderboard
+	maxPlayerNameLength = 12
+	// maxLeaderboardURLLength is the longest leaderboard endpoint that can be typed
+	maxLeaderboardURLLength = 200
+)
+
+// leaderboardEntry is a single distance on the leaderboard
+type leaderboardEntry struct {
+	// Name is the name of the player who travelled the distance
+	Name string `json:"name"`
+	// Distance is the distance travelled in the run
+	Distance int `json:"distance"`
+	// Assisted represents whether the run was played with the asteroid path assist
+	Assisted bool `json:"assisted"`
+	// Difficulty is the name of the difficulty the run was played on
+	Difficulty string `json:"difficulty"`
+	// Variant is the name of the game variant the run was played in
+	Variant string `json:"variant"`
+}
+
+// leaderboardResult is the outcome of fetching the top entries
+type leaderboardResult struct {
+	// entries are the top entries, best first
+	entries []leaderboardEntry
+	// err is the error the fetch failed with, if any
+	err error
+}
+
+// leaderboard posts distances to and fetches the top distances from a leaderboard endpoint.  Every request runs in the
+// background so the game loop never waits on the network
+type leaderboard struct {
+	// url is the endpoint distances are posted to and the top distances fetched from
+	url string
+	// client is the HTTP client requests are made with
+	client *http.Client
+	// results receives the outcome of a fetch once it finishes
+	results chan leaderboardResult
+	// entries are the top entries from the last fetch, best first
+	entries []leaderboardEntry
+	// err is the error the last fetch failed with, if any
+	err error
+	// isLoading represents whether a fetch is underway
+	isLoading bool
+}
+
+// newLeaderboard creates a leaderboard for an endpoint, or nil if no endpoint is configured
+func newLeaderboard(url string) *leaderboard {
+	if url == "" {
+		return nil
+	}
+	return &leaderboard{
+		url:     url,
+		client:  &http.Client{Timeout: leaderboardTimeout},
+		results: make(chan leaderboardResult, 1),
+	}
+}
+
+// submit posts an entry to the leaderboard in the background, logging any failure
+func (l *leaderboard) submit(entry leaderboardEntry) {
+	go func() {
+		if err := l.post(entry); err != nil {
+			log.Println(err)
+		}
+	}()
+}
+
+// post posts an entry to the leaderboard
+func (l *leaderboard) post(entry leaderboardEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.client.Post(l.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("leaderboard submission failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// fetch starts fetching the top entries in the background, unless a fetch is already underway
+func (l *leaderboard) fetch() {
+	if l.isLoading {
+		return
+	}
+	l.isLoading = true
+	l.err = nil
+
+	go func() {
+		entries, err := l.get()
+		l.results <- leaderboardResult{entries: entries, err: err}
+	}()
+}
+
+// get fetches the top entries from the leaderboard, best first
+func (l *leaderboard) get() ([]leaderboardEntry, error) {
+	endpoint, err := url.Parse(l.url)
+	if err != nil {
+		return nil, err
+	}
+	values := endpoint.Query()
+	values.Set("limit", strconv.Itoa(leaderboardSize))
+	endpoint.RawQuery = values.Encode()
+
+	resp, err := l.client.Get(endpoint.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("leaderboard fetch failed: %s", resp.Status)
+	}
+
+	var entries []leaderboardEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	if len(entries) > leaderboardSize {
+		entries = entries[:leaderboardSize]
+	}
+	return entries, nil
+}
+
+// poll picks up the outcome of a fetch if it has finished, without waiting for it
+func (l *leaderboard) poll() {
+	select {
+	case result := <-l.results:
+		l.isLoading = false
+		l.entries, l.err = result.entries, result.err
+		if l.err != nil {
+			log.Println(l.err)
+		}
+	default:
+	}
+}
+
+// submitRun posts the distance of the run just finished to the leaderboard, along with the difficulty and variant it was
+// played on.  Runs that aren't trusted aren't submitted, and runs played with the path assist are submitted marked as assisted
+func (g *Game) submitRun() {
+	if g.leaderboard == nil || g.isCheated || isTuned || isModded || g.profile.Tampered {
+		return
+	}
+
+	name := g.settings.PlayerName
+	if name == "" {
+		name = defaultPlayerName
+	}
+	g.leaderboard.submit(leaderboardEntry{
+		Name:       name,
+		Distance:   g.distanceTravelled,
+		Assisted:   g.isAssisted,
+		Difficulty: g.difficulty().Name,
+		Variant:    variantNames[g.variant],
+	})
+}
+
+// updateLeaderboardShortcut opens the leaderboard screen when its key is pressed on the title screen
+func (g *Game) updateLeaderboardShortcut() {
+	if g.leaderboard == nil || !g.input.IsKeyJustPressed(leaderboardKey) {
+		return
+	}
+	g.mode = ModeLeaderboard
+}
+
+// updateLeaderboard picks up fetched entries and goes back to the title screen when asked
+func (g *Game) updateLeaderboard() {
+	g.leaderboard.poll()
+	if g.input.IsKeyJustPressed(leaderboardKey) || g.input.IsKeyJustPressed(ebiten.KeyEscape) || g.isGamepadJustPressed(gamepadButtonB) {
+		g.mode = ModeTitle
+	}
+}
+
+// leaderboardTexts returns the lines of the leaderboard screen
+func (g *Game) leaderboardTexts() []string {
+	texts := []string{"", "", ""}
+	switch {
+	case g.leaderboard.isLoading:
+		texts = append(texts, "LOADING...")
+	case g.leaderboard.err != nil:
+		texts = append(texts, "COULDN'T REACH THE LEADERBOARD")
+	case len(g.leaderboard.entries) == 0:
+		texts = append(texts, "NO ENTRIES YET")
+	}
+	for i, entry := range g.leaderboard.entries {
+		text := fmt.Sprintf("%2d. %-12s %8d M", i+1, entry.Name, entry.Distance)
+		// Entries submitted before the difficulty and variant were sent don't have them
+		if entry.Variant != "" && entry.Difficulty != "" {
+			text += " " + entry.Variant + " / " + entry.Difficulty
+		}
+		if entry.Assisted {
+			text += " (ASSISTED)"
+		}
+		texts = append(texts, text)
+	}
+	return append(texts, "", "", "PRESS ESCAPE TO GO BACK")
+}
+
+// drawLeaderboardHint draws how to open the leaderboard screen at the bottom of the title screen, if a leaderboard is configured
+func (g *Game) drawLeaderboardHint(r Renderer) {
+	if g.leaderboard == nil {
+		return
+	}
+	theme := g.theme()
+	hint := "PRESS 'L' KEY FOR LEADERBOARD"
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*7, theme.Text())
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"image"
+)
+
+const (
+	// imageTopLedgeSpire is the key of the spires hanging from the top of the screen with a ledge jutting back from their tip
+	imageTopLedgeSpire = "topLedgeSpire"
+	// imageBottomLedgeSpire is the key of the spires rising from the bottom of the screen with a ledge jutting back from their tip
+	imageBottomLedgeSpire = "bottomLedgeSpire"
+	// ledgeLength is how far a ledge juts back from the side of its spire
+	ledgeLength = 160
+	// ledgeThickness is the thickness of a ledge
+	ledgeThickness = 24
+	// ledgeSpireChance is the chance out of 10 that a spire is spawned with a ledge
+	ledgeSpireChance = 3
+)
+
+// hulls are the rectangles making up the shapes of sprites too far from rectangular for their bounds to collide with, by
+// image and relative to the top left of the sprite.  Sprites drawn with any other image collide with their bounds
+var hulls = map[*ebiten.Image][]image.Rectangle{}
+
+// loadLedgeImages creates the ledge spires, each a spire with a strip of ground jutting back from its tip toward the ship
+// so the way past it is L-shaped
+func (a *Assets) loadLedgeImages() error {
+	spireWidth, spireHeight := a.images[imageTopSpire].Size()
+	groundWidth, groundHeight := a.images[imageFloor].Size()
+
+	for _, ledge := range []struct {
+		key, spire string
+		y          int
+	}{
+		{imageTopLedgeSpire, imageTopSpire, spireHeight - ledgeThickness},
+		{imageBottomLedgeSpire, imageBottomSpire, 0},
+	} {
+		img, err := ebiten.NewImage(ledgeLength+spireWidth, spireHeight, ebiten.FilterDefault)
+		if err != nil {
+			return err
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(ledgeLength+spireWidth/2)/float64(groundWidth), float64(ledgeThickness)/float64(groundHeight))
+		op.GeoM.Translate(0, float64(ledge.y))
+		img.DrawImage(a.images[imageFloor], op)
+
+		op = &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(ledgeLength, 0)
+		img.DrawImage(a.images[ledge.spire], op)
+		a.images[ledge.key] = img
+	}
+	return nil
+}
+
+// initializeHulls sets the hulls of the ledge spires, for every stage's copy of them
+func initializeHulls(assets *Assets) {
+	spireWidth, spireHeight := assets.Image(imageTopSpire).Size()
+	spire := image.Rect(ledgeLength, 0, ledgeLength+spireWidth, spireHeight)
+	for _, stage := range stages {
+		hulls[assets.Image(stage.prefix+imageTopLedgeSpire)] = []image.Rectangle{
+			spire,
+			image.Rect(0, spireHeight-ledgeThickness, ledgeLength+spireWidth/2, spireHeight),
+		}
+		hulls[assets.Image(stage.prefix+imageBottomLedgeSpire)] = []image.Rectangle{
+			spire,
+			image.Rect(0, 0, ledgeLength+spireWidth/2, ledgeThickness),
+		}
+	}
+}
+
+// isTouchingHull returns whether a sprite colliding with the bounds of another is touching its hull, always true if the
+// other sprite has no hull
+func isTouchingHull(sprite, other *spriteutils.Sprite) bool {
+	hull, ok := hulls[other.Image]
+	if !ok {
+		return true
+	}
+
+	width, height := sprite.Image.Size()
+	bounds := image.Rect(sprite.X, sprite.Y, sprite.X+width, sprite.Y+height)
+	for _, rect := range hull {
+		if rect.Add(image.Pt(other.X, other.Y)).Overlaps(bounds) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSpire creates a spire at the top or bottom of the screen, sometimes one with a ledge
+func (g *Game) generateSpire(isTop bool) *spriteutils.Sprite {
+	hasLedge := g.randIntn("spire ledge", 10) < ledgeSpireChance
+	switch {
+	case isTop && hasLedge:
+		return g.generateSprite(g.topLedgeSpireFactory)
+	case isTop:
+		return g.generateSprite(g.topSpireFactory)
+	case hasLedge:
+		return g.generateSprite(g.bottomLedgeSpireFactory)
+	default:
+		return g.generateSprite(g.bottomSpireFactory)
+	}
+}
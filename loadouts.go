@@ -0,0 +1,200 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"strings"
+	"unicode"
+)
+
+const (
+	// loadoutKey is the key that switches to the next saved loadout on the title screen
+	loadoutKey = ebiten.KeyO
+	// newLoadoutKey is the key that starts saving the options selected on the title screen as a new loadout
+	newLoadoutKey = ebiten.KeyN
+	// maxLoadouts is the most loadouts that can be saved, saving another replacing the oldest
+	maxLoadouts = 5
+	// maxLoadoutNameLength is the longest a loadout's name can be
+	maxLoadoutNameLength = 16
+)
+
+// Loadout is a named set of the options a run is played with, saved in the profile so practice setups can be picked
+// again in one key press.  There are no ships or upgrades to choose between, so the game variant stands in for the ship,
+// and the difficulty, biome and path assist are the mutators
+type Loadout struct {
+	// Name is the name the player gave the loadout
+	Name string `json:"name"`
+	// Variant is the game variant played
+	Variant Variant `json:"variant"`
+	// Difficulty is the name of the difficulty, empty for the default
+	Difficulty string `json:"difficulty,omitempty"`
+	// Biome is the name of the biome, empty for the default
+	Biome string `json:"biome,omitempty"`
+	// PathAssist represents whether the asteroid path assist is enabled
+	PathAssist bool `json:"pathAssist,omitempty"`
+	// ControlScheme is the keys used to thrust
+	ControlScheme ControlScheme `json:"controlScheme"`
+}
+
+// currentLoadout returns the options selected for the next run as a loadout, named after the loadout last picked
+func (g *Game) currentLoadout() Loadout {
+	loadout := Loadout{
+		Variant:       g.variant,
+		Difficulty:    g.profile.Difficulty,
+		Biome:         g.profile.Biome,
+		PathAssist:    g.settings.PathAssist,
+		ControlScheme: g.settings.ControlScheme,
+	}
+
+	// The name is only kept while the options still match the loadout, so a changed setup isn't passed off as it
+	if picked := g.pickedLoadout(); picked != nil {
+		named := loadout
+		named.Name = picked.Name
+		if named == *picked {
+			loadout.Name = picked.Name
+		}
+	}
+	return loadout
+}
+
+// pickedLoadoutIndex returns the index in the profile of the loadout last picked on the title screen, or -1 if none has been
+func (g *Game) pickedLoadoutIndex() int {
+	for i, loadout := range g.profile.Loadouts {
+		if loadout.Name == g.pickedLoadoutName {
+			return i
+		}
+	}
+	return -1
+}
+
+// pickedLoadout returns the loadout last picked on the title screen, or nil if none has been
+func (g *Game) pickedLoadout() *Loadout {
+	i := g.pickedLoadoutIndex()
+	if i < 0 {
+		return nil
+	}
+	return &g.profile.Loadouts[i]
+}
+
+// applyLoadout selects every option of a loadout, remembering them in the profile and settings
+func (g *Game) applyLoadout(loadout Loadout) {
+	g.variant = loadout.Variant
+	g.profile.Difficulty = loadout.Difficulty
+	g.profile.Biome = loadout.Biome
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
+	}
+
+	g.settings.PathAssist = loadout.PathAssist
+	g.settings.ControlScheme = loadout.ControlScheme
+	g.applySettings()
+
+	// The title preview shares the profile, so it plays the loadout's difficulty and biome from the start too
+	g.resetGame()
+	g.titlePreview.resetGame()
+}
+
+// updateLoadout switches to the next saved loadout when its key is pressed on the title screen
+func (g *Game) updateLoadout() {
+	if !g.input.IsKeyJustPressed(loadoutKey) || len(g.profile.Loadouts) == 0 {
+		return
+	}
+
+	loadout := g.profile.Loadouts[(g.pickedLoadoutIndex()+1)%len(g.profile.Loadouts)]
+	g.pickedLoadoutName = loadout.Name
+	g.applyLoadout(loadout)
+}
+
+// updateLoadoutNaming starts naming a new loadout when its key is pressed on the title screen, then takes the typed name
+// until it is saved with Enter or given up on with Escape.  It returns whether a name is being typed, so the other title
+// screen keys are left alone while it is
+func (g *Game) updateLoadoutNaming() bool {
+	if !g.isNamingLoadout {
+		if g.input.IsKeyJustPressed(newLoadoutKey) {
+			g.isNamingLoadout = true
+			g.loadoutNameInput = ""
+			return true
+		}
+		return false
+	}
+
+	for _, char := range g.input.InputChars() {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || char == ' ' {
+			g.loadoutNameInput += strings.ToUpper(string(char))
+		}
+	}
+	if g.input.IsKeyJustPressed(ebiten.KeyBackspace) && g.loadoutNameInput != "" {
+		g.loadoutNameInput = g.loadoutNameInput[:len(g.loadoutNameInput)-1]
+	}
+	if len(g.loadoutNameInput) > maxLoadoutNameLength {
+		g.loadoutNameInput = g.loadoutNameInput[:maxLoadoutNameLength]
+	}
+
+	switch {
+	case g.input.IsKeyJustPressed(ebiten.KeyEscape):
+		g.isNamingLoadout = false
+	case g.input.IsKeyJustPressed(ebiten.KeyEnter) && strings.TrimSpace(g.loadoutNameInput) != "":
+		g.isNamingLoadout = false
+		g.saveLoadout(strings.TrimSpace(g.loadoutNameInput))
+	}
+	return true
+}
+
+// saveLoadout saves the options selected on the title screen as a loadout in the profile, replacing any loadout with the
+// same name, or the oldest if there are already as many as can be saved
+func (g *Game) saveLoadout(name string) {
+	loadout := g.currentLoadout()
+	loadout.Name = name
+
+	loadouts := g.profile.Loadouts[:0]
+	for _, saved := range g.profile.Loadouts {
+		if saved.Name != name {
+			loadouts = append(loadouts, saved)
+		}
+	}
+	if len(loadouts) >= maxLoadouts {
+		loadouts = loadouts[len(loadouts)-maxLoadouts+1:]
+	}
+	g.profile.Loadouts = append(loadouts, loadout)
+	g.pickedLoadoutName = name
+
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// loadoutSummary returns a loadout's options as a line of text
+func loadoutSummary(loadout Loadout) string {
+	difficulty, biome := loadout.Difficulty, loadout.Biome
+	if difficulty == "" {
+		difficulty = tuning.defaultDifficulty().Name
+	}
+	if biome == "" {
+		biome = biomes[0].Name
+	}
+	summary := variantNames[loadout.Variant] + " / " + difficulty + " / " + biome + " / " + controlSchemeNames[loadout.ControlScheme]
+	if loadout.PathAssist {
+		summary += " / ASSIST"
+	}
+	if loadout.Name != "" {
+		summary = loadout.Name + ": " + summary
+	}
+	return summary
+}
+
+// drawLoadoutHint draws the picked loadout and how to switch or save loadouts at the bottom of the title screen, or the
+// name being typed for a new loadout
+func (g *Game) drawLoadoutHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'N' KEY TO SAVE A LOADOUT"
+	switch {
+	case g.isNamingLoadout:
+		hint = "LOADOUT NAME: " + g.loadoutNameInput + "_   (ENTER TO SAVE, ESCAPE TO CANCEL)"
+	case len(g.profile.Loadouts) > 0:
+		hint = "PRESS 'O' KEY TO SWITCH LOADOUT, 'N' TO SAVE ONE"
+		if loadout := g.pickedLoadout(); loadout != nil {
+			hint += ": " + loadout.Name
+		}
+	}
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*8, theme.Text())
+}
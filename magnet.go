@@ -0,0 +1,110 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// magnetKey is the key held to use the magnet beam
+	magnetKey = ebiten.KeyE
+	// magnetRange is the furthest a star can be from the ship to be caught by the magnet beam
+	magnetRange = 500
+	// magnetPullSpeed is how far the magnet beam pulls a star toward the ship each frame
+	magnetPullSpeed = 3
+	// magnetThrustFactor is how effective thrust is while the magnet beam is in use
+	magnetThrustFactor = 0.5
+	// magnetDrainRate is the energy used each frame while the magnet beam is in use
+	magnetDrainRate = 1.0 / 180
+	// magnetRechargeRate is the energy regained each frame while the magnet beam is not in use
+	magnetRechargeRate = 1.0 / 600
+	// magnetBeamWidth is the width of the magnet beam
+	magnetBeamWidth = 3
+	// magnetMeterWidth is the width of the magnet energy meter
+	magnetMeterWidth = 150
+	// magnetMeterHeight is the height of the magnet energy meter
+	magnetMeterHeight = 8
+)
+
+// updateMagnet uses the magnet beam to pull the nearest star toward the ship while its key is held and there is energy left
+func (g *Game) updateMagnet() {
+	g.magnetTarget = nil
+	if g.input.IsKeyPressed(magnetKey) && g.magnetEnergy > 0 {
+		g.magnetTarget = g.nearestStar()
+	}
+
+	if g.magnetTarget == nil {
+		g.magnetEnergy = math.Min(g.magnetEnergy+magnetRechargeRate*g.upgradeFactor(shopMagnetCapacitor, 2), 1)
+		return
+	}
+	g.magnetEnergy = math.Max(g.magnetEnergy-magnetDrainRate, 0)
+
+	shipX, shipY := spriteCenter(g.ship)
+	starX, starY := spriteCenter(g.magnetTarget)
+	distance := math.Hypot(shipX-starX, shipY-starY)
+	if distance == 0 {
+		return
+	}
+	pull := math.Min(magnetPullSpeed, distance)
+	g.magnetTarget.X += int(math.Round((shipX - starX) / distance * pull))
+	g.magnetTarget.Y += int(math.Round((shipY - starY) / distance * pull))
+}
+
+// nearestStar returns the star closest to the ship within range of the magnet beam, or nil if there isn't one.
+// The magnet can't tell a disguised hazard from a real star, so it pulls anything that looks like one
+func (g *Game) nearestStar() *spriteutils.Sprite {
+	var nearest *spriteutils.Sprite
+	nearestDistance := float64(magnetRange)
+
+	shipX, shipY := spriteCenter(g.ship)
+	for _, star := range g.pickupSprites(nil) {
+		if star.Image != g.assets.Image(imageStar) {
+			continue
+		}
+		starX, starY := spriteCenter(star)
+		distance := math.Hypot(shipX-starX, shipY-starY)
+		if distance <= nearestDistance {
+			nearest = star
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// thrust returns how much upward velocity the ship gains each frame while thrusting
+func (g *Game) thrust() float64 {
+	if g.magnetTarget != nil {
+		return tuning.ShipThrust * magnetThrustFactor
+	}
+	return tuning.ShipThrust
+}
+
+// drawMagnetBeam draws the magnet beam between the ship and the star it is pulling
+func (g *Game) drawMagnetBeam(r Renderer) {
+	if g.magnetTarget == nil {
+		return
+	}
+
+	shipX, shipY := spriteCenter(g.ship)
+	starX, starY := spriteCenter(g.magnetTarget)
+
+	op := &Transform{}
+	op.Translate(0, -0.5)
+	op.Scale(math.Hypot(starX-shipX, starY-shipY), magnetBeamWidth)
+	op.Rotate(math.Atan2(starY-shipY, starX-shipX))
+	op.Translate(shipX, shipY)
+	op.ScaleColor(0.4, 0.8, 1, 0.6)
+	r.DrawImage(g.assets.Image(imageBeam), op)
+}
+
+// drawMagnetMeter draws the magnet beam energy meter below the score
+func (g *Game) drawMagnetMeter(r Renderer) {
+	theme := g.theme()
+	width, height := g.uiFloat(magnetMeterWidth), g.uiFloat(magnetMeterHeight)
+	x := g.hudXFloat(width, g.uiFloat(fontSize))
+	y := g.uiFloat(fontSize + smallFontSize*5)
+
+	r.DrawRect(x, y, width, height, theme.Panel())
+	r.DrawRect(x, y, width*g.magnetEnergy, height, theme.Accent())
+}
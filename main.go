@@ -1,40 +1,22 @@
 package main
 
 import (
+	"flag"
 	"github.com/hajimehoshi/ebiten"
-	"github.com/hajimehoshi/ebiten/ebitenutil"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/gofont/goregular"
-	"golang.org/x/image/font/opentype"
 	"log"
 	"math/rand"
 	"time"
 )
 
 const (
-	screenWidth   = 1028
-	screenHeight  = 720
 	fontSize      = 24
 	titleFontSize = fontSize * 1.5
 	smallFontSize = fontSize / 2
 )
 
 var (
-	backgroundImage        *ebiten.Image
-	shipImage              *ebiten.Image
-	floorImage             *ebiten.Image
-	topSpire               *ebiten.Image
-	bottomSpire            *ebiten.Image
-	asteroid1              *ebiten.Image
-	asteroid2              *ebiten.Image
-	asteroid3              *ebiten.Image
-	asteroid4              *ebiten.Image
-	asteroidExplosionImage *ebiten.Image
-	starImage              *ebiten.Image
-	shieldImage            *ebiten.Image
-	titleFont              font.Face
-	normalFont             font.Face
-	smallFont              font.Face
+	musicBaseLayer    []byte
+	musicIntenseLayer []byte
 )
 
 // Seed random number generator
@@ -42,115 +24,114 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-// Initialize all images
+// Initialize music
 func init() {
-	var err error
-	backgroundImage, _, err = ebitenutil.NewImageFromFile("assets/background.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
-	}
+	musicBaseLayer, musicIntenseLayer = synthesizeMusic(musicRoots)
+}
 
-	shipImage, _, err = ebitenutil.NewImageFromFile("assets/spaceship.png", ebiten.FilterDefault)
+// Initialize game with the loaded assets
+func newGame(settings *Settings, assets *Assets) *Game {
+	isNewPlayer := !hasSavedProfile()
+	profile, err := loadProfile()
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
 
-	floorImage, _, err = ebitenutil.NewImageFromFile("assets/groundDirt.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
+	if interest := profile.Wallet.applyDailyInterest(time.Now()); interest > 0 {
+		if err := profile.save(); err != nil {
+			log.Println(err)
+		}
 	}
 
-	topSpire, _, err = ebitenutil.NewImageFromFile("assets/rock-top.png", ebiten.FilterDefault)
+	audio, err := newAudioManager(settings.Volume)
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
+	audio.setMuted(!settings.Music, !settings.Sound)
+	audio.setAnnouncer(settings.Announcer, settings.AnnouncerVolume)
 
-	bottomSpire, _, err = ebitenutil.NewImageFromFile("assets/rock-bottom.png", ebiten.FilterDefault)
+	cheats, err := loadCheats()
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
 
-	asteroid1, _, err = ebitenutil.NewImageFromFile("assets/meteorBrown_big1.png", ebiten.FilterDefault)
+	ghost, err := loadGhost()
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
 
-	asteroid2, _, err = ebitenutil.NewImageFromFile("assets/meteorBrown_big2.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
+	game := &Game{
+		settings:     settings,
+		assets:       assets,
+		input:        ebitenInput{},
+		seed:         *seedFlag,
+		cheats:       cheats,
+		ghost:        ghost,
+		leaderboard:  newLeaderboard(settings.LeaderboardURL),
+		audio:        audio,
+		profile:      profile,
+		titlePreview: newTitlePreview(profile, settings, assets),
 	}
+	game.init()
+	game.applySeason()
 
-	asteroid3, _, err = ebitenutil.NewImageFromFile("assets/meteorBrown_big3.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
+	if *replayFlag != "" {
+		if err := game.playReplay(*replayFlag); err != nil {
+			log.Println(err)
+		}
 	}
 
-	asteroid4, _, err = ebitenutil.NewImageFromFile("assets/meteorBrown_big4.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
+	// Only show the "what's new" screen once per version, and never on the first launch, when everything is new
+	if isNewPlayer {
+		profile.LastSeenVersion = gameVersion
+	} else if _, ok := currentChangelogEntry(); ok && profile.LastSeenVersion != gameVersion {
+		game.mode = ModeWhatsNew
 	}
+	return game
+}
 
-	asteroidExplosionImage, _, err = ebitenutil.NewImageFromFile("assets/meteorExplosion.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
-	}
+// Entry point
+func main() {
+	flag.Parse()
 
-	starImage, _, err = ebitenutil.NewImageFromFile("assets/starGold.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
+	// The game is still playable with the compiled in tuning if the tuning file can't be read
+	if err := loadTuning(); err != nil {
+		log.Println(err)
 	}
 
-	shieldImage, _, err = ebitenutil.NewImageFromFile("assets/shield.png", ebiten.FilterDefault)
-	if err != nil {
-		log.Fatal(err)
-	}
-}
+	ebiten.SetWindowTitle("Galactic Asteroid Belt")
+	// Stop updating while the window is unfocused, the game resumes with a countdown when focus returns
+	ebiten.SetRunnableOnUnfocused(false)
 
-// Initialize fonts
-func init() {
-	tt, err := opentype.Parse(goregular.TTF)
+	settings, err := loadSettings()
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
-	const dpi = 72
-	titleFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    titleFontSize,
-		DPI:     dpi,
-		Hinting: font.HintingFull,
-	})
+	settings.apply()
+	// Everything laid out from the screen size is created after this, so it is all laid out in the chosen orientation
+	applyOrientation(settings.Orientation)
+
+	assets, err := loadAssets()
 	if err != nil {
 		log.Fatal(err)
 	}
-	normalFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    fontSize,
-		DPI:     dpi,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
+	// The game is still playable with the default rules if the mod script can't be loaded
+	script, err := loadRules(assets)
+	if script == nil {
 		log.Fatal(err)
 	}
-	smallFont, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    smallFontSize,
-		DPI:     dpi,
-		Hinting: font.HintingFull,
-	})
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
 	}
-}
+	rules = script
+	initializePowerUps(rules)
+	initializeEntityTypes(assets)
+	initializeHulls(assets)
+	initializeAsteroidHulls(assets)
+	initializeSeasons(assets)
+	initializeRotations(assets)
 
-// Initialize game
-func newGame() *Game {
-	game := &Game{}
-	game.init()
-	return game
-}
-
-// Entry point
-func main() {
-	ebiten.SetWindowSize(screenWidth, screenHeight)
-	ebiten.SetWindowTitle("Galactic Asteroid Belt")
-	if err := ebiten.RunGame(newGame()); err != nil {
+	if err := ebiten.RunGame(newGame(settings, assets)); err != nil {
 		log.Fatal(err)
 	}
 }
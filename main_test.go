@@ -0,0 +1,202 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"math"
+	"os"
+	"testing"
+)
+
+const (
+	// testSeed is the seed every test run's spawns are drawn from, so the runs play out the same every time
+	testSeed = 1
+	// testGamepad is the ID of the gamepad connected by the tests
+	testGamepad = 0
+	// testMaxFrames is the most frames a test waits for something to happen before failing
+	testMaxFrames = 3600
+)
+
+// testAssets are the assets shared by every test game, loaded once as the game does at startup
+var testAssets *Assets
+
+// TestMain keeps the save files of the tests out of the player's profile and loads the assets and rules, as main does
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "galactic-asteroid-belt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	// os.UserConfigDir reads a different variable on each platform
+	for _, name := range []string{"XDG_CONFIG_HOME", "APPDATA", "HOME"} {
+		if err := os.Setenv(name, dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	applyOrientation(OrientationLandscape)
+	testAssets, err = loadAssets()
+	if err != nil {
+		log.Fatal(err)
+	}
+	rules, err = loadRules(testAssets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	initializePowerUps(rules)
+	initializeEntityTypes(testAssets)
+	initializeHulls(testAssets)
+	initializeAsteroidHulls(testAssets)
+	initializeSeasons(testAssets)
+	initializeRotations(testAssets)
+
+	code := m.Run()
+	if err := os.RemoveAll(dir); err != nil {
+		log.Println(err)
+	}
+	os.Exit(code)
+}
+
+// newTestGame creates a seeded game on the title screen with a fresh profile, driven by scripted input with a gamepad
+// connected.  It has no audio and no leaderboard
+func newTestGame(t *testing.T) (*Game, *scriptedInput) {
+	t.Helper()
+
+	cheats, err := loadCheats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := defaultSettings()
+	profile := &Profile{}
+	input := newScriptedInput()
+	input.connectGamepad(testGamepad)
+
+	g := &Game{
+		settings:     settings,
+		assets:       testAssets,
+		input:        input,
+		seed:         testSeed,
+		cheats:       cheats,
+		leaderboard:  newLeaderboard(""),
+		profile:      profile,
+		titlePreview: newTitlePreview(profile, settings, testAssets),
+	}
+	g.init()
+	return g, input
+}
+
+// runUntil updates the game until a condition holds, failing the test if it doesn't within testMaxFrames
+func runUntil(t *testing.T, g *Game, input *scriptedInput, what string, condition func() bool) {
+	t.Helper()
+	for frame := 0; !condition(); frame++ {
+		if frame == testMaxFrames {
+			t.Fatalf("%s didn't happen within %d frames", what, testMaxFrames)
+		}
+		if err := input.run(g, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// launch starts a run from the title screen by tapping thrust
+func launch(t *testing.T, g *Game, input *scriptedInput) {
+	t.Helper()
+	if err := input.tap(g, ebiten.KeySpace); err != nil {
+		t.Fatal(err)
+	}
+	if err := input.run(g, 1); err != nil {
+		t.Fatal(err)
+	}
+	if g.mode != ModeGame {
+		t.Fatalf("mode after tapping thrust on the title screen = %d, want %d", g.mode, ModeGame)
+	}
+}
+
+// expectMode fails the test if the game isn't in a mode
+func expectMode(t *testing.T, g *Game, want Mode, after string) {
+	t.Helper()
+	if g.mode != want {
+		t.Fatalf("mode after %s = %d, want %d", after, g.mode, want)
+	}
+}
+
+// TestModeTransitions plays through the title screen, pausing and resuming, game over and restarting
+func TestModeTransitions(t *testing.T) {
+	g, input := newTestGame(t)
+	expectMode(t, g, ModeTitle, "starting")
+
+	launch(t, g, input)
+
+	if err := input.tap(g, ebiten.KeyP); err != nil {
+		t.Fatal(err)
+	}
+	expectMode(t, g, ModePause, "pausing with P")
+	distance := g.distanceTravelled
+	if err := input.run(g, 60); err != nil {
+		t.Fatal(err)
+	}
+	if g.distanceTravelled != distance {
+		t.Fatalf("distance travelled while paused = %d, want %d", g.distanceTravelled, distance)
+	}
+
+	if err := input.tapGamepadButton(g, testGamepad, gamepadButtonStart); err != nil {
+		t.Fatal(err)
+	}
+	expectMode(t, g, ModeGame, "resuming with Start")
+
+	runUntil(t, g, input, "game over", func() bool { return g.mode == ModeGameOver })
+
+	if err := input.tap(g, ebiten.KeyR); err != nil {
+		t.Fatal(err)
+	}
+	expectMode(t, g, ModeTitle, "restarting with R")
+	if g.distanceTravelled != 0 {
+		t.Fatalf("distance travelled after restarting = %d, want 0", g.distanceTravelled)
+	}
+}
+
+// TestCollisionEndsRun checks that a ship left to fall crashes, ending the run and banking it in the profile
+func TestCollisionEndsRun(t *testing.T) {
+	g, input := newTestGame(t)
+	launch(t, g, input)
+
+	runUntil(t, g, input, "crashing", func() bool { return g.mode != ModeGame })
+	expectMode(t, g, ModeGameOver, "crashing")
+	if g.summary == nil {
+		t.Fatal("no summary of the run after crashing")
+	}
+	if g.profile.BestDistance != g.distanceTravelled {
+		t.Fatalf("best distance after the first run = %d, want %d", g.profile.BestDistance, g.distanceTravelled)
+	}
+}
+
+// TestBoostExpires checks that a star's boost lasts its duration and then wears off
+func TestBoostExpires(t *testing.T) {
+	g, input := newTestGame(t)
+	launch(t, g, input)
+
+	// Nothing can end the run or spawn another star while the boost runs down
+	g.cheats.invulnerable = true
+	for powerUp := range g.powerUpSpawnThresholds {
+		g.powerUpSpawnThresholds[powerUp] = math.MaxInt32
+	}
+
+	g.collectPowerUp(starPowerUp)
+	if !g.isBoosting {
+		t.Fatal("not boosting after collecting a star")
+	}
+
+	duration := int(g.powerUpDuration(starPowerUp))
+	if err := input.run(g, duration); err != nil {
+		t.Fatal(err)
+	}
+	if !g.isBoosting {
+		t.Fatalf("boost wore off before its %d frames", duration)
+	}
+
+	if err := input.run(g, 1); err != nil {
+		t.Fatal(err)
+	}
+	if g.isBoosting {
+		t.Fatalf("still boosting %d frames after collecting a star", duration+1)
+	}
+}
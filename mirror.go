@@ -0,0 +1,22 @@
+package main
+
+// gravityDirection returns which way gravity pulls the ships, 1 for down and -1 for up in the mirror universe
+func (g *Game) gravityDirection() float64 {
+	if g.variant == VariantMirror {
+		return -1
+	}
+	return 1
+}
+
+// palette returns the palette frames are shown in, inverted in the mirror universe.  Replays are shown in the palette of
+// the variant they were played in
+func (g *Game) palette() Palette {
+	variant := g.variant
+	if g.mode == ModeReplay && g.replay != nil && g.replay.header.Loadout != nil {
+		variant = g.replay.header.Loadout.Variant
+	}
+	if variant == VariantMirror {
+		return PaletteInverted
+	}
+	return PaletteNormal
+}
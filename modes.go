@@ -10,4 +10,18 @@ const (
 	ModeGame
 	// ModeGameOver represents the state when game is on "game over" screen
 	ModeGameOver
+	// ModeWhatsNew represents the state when the "what's new" screen is shown after an update
+	ModeWhatsNew
+	// ModeKillCam represents the state when the final seconds of a run are being replayed after death
+	ModeKillCam
+	// ModePause represents the state when a run is paused
+	ModePause
+	// ModeSettings represents the state when the settings screen is shown
+	ModeSettings
+	// ModeLeaderboard represents the state when the leaderboard screen is shown
+	ModeLeaderboard
+	// ModeReplay represents the state when a saved replay is being played back
+	ModeReplay
+	// ModeShop represents the state when the shop is shown
+	ModeShop
 )
@@ -0,0 +1,328 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	lua "github.com/yuin/gopher-lua"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// modScriptFileName is the name of the mod script, next to the profile, that replaces the default rules
+	modScriptFileName = "mod.lua"
+	// defaultScriptName is the name the default script is reported by in errors
+	defaultScriptName = "default.lua"
+)
+
+//go:embed scripts/default.lua
+var defaultScript string
+
+// rules is the script the spawn, power-up and scoring rules are run by, the default script unless a mod script replaced it
+var rules *script
+
+// isModded represents whether a mod script has replaced the default rules.  Runs played with it aren't submitted to the leaderboard
+var isModded bool
+
+// spawnKindNames are the names of each spawn kind in scripts
+var spawnKindNames = map[SpawnKind]string{
+	SpawnSpire:    "spire",
+	SpawnAsteroid: "asteroid",
+	SpawnPickup:   "pickup",
+	SpawnRing:     "ring",
+	SpawnTwins:    "twins",
+	SpawnUFO:      "ufo",
+	SpawnHoming:   "homing",
+	SpawnNemesis:  "nemesis",
+	SpawnGate:     "gate",
+}
+
+// destructionCauseNames are the names of each destruction cause in scripts
+var destructionCauseNames = map[destructionCause]string{
+	destroyedByTerrain: "terrain",
+	destroyedByShield:  "shield",
+	destroyedByLaser:   "laser",
+}
+
+// script is a loaded Lua script defining the rules modders can change
+type script struct {
+	// name is the name of the file the script was loaded from, used in errors
+	name string
+	// state is the Lua state the script runs in
+	state *lua.LState
+	// powerUps are the power-ups the script defines
+	powerUps []*PowerUp
+}
+
+// loadRules loads the mod script next to the profile, or the default script if there is no mod script.  If the mod script
+// can't be loaded the error is returned along with the default script, so the game is still playable with the default rules
+func loadRules(assets *Assets) (*script, error) {
+	defaults, err := newScript(defaultScriptName, defaultScript, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := profileDir()
+	if err != nil {
+		return defaults, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, modScriptFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return defaults, nil
+	}
+	if err != nil {
+		return defaults, err
+	}
+
+	mod, err := newScript(modScriptFileName, string(data), assets)
+	if err != nil {
+		return defaults, err
+	}
+	isModded = true
+	return mod, nil
+}
+
+// newScript runs a script and reads the power-ups it defines.  Only the base, table, string and math libraries are
+// open to scripts
+func newScript(name, source string, assets *Assets) (*script, error) {
+	state := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		state.Push(state.NewFunction(lib.open))
+		state.Push(lua.LString(lib.name))
+		state.Call(1, 0)
+	}
+
+	s := &script{name: name, state: state}
+	if err := state.DoString(source); err != nil {
+		state.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := s.loadPowerUps(assets); err != nil {
+		state.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return s, nil
+}
+
+// loadPowerUps reads the power_ups table of the script, checking every image and effect exists
+func (s *script) loadPowerUps(assets *Assets) error {
+	table, ok := s.state.GetGlobal("power_ups").(*lua.LTable)
+	if !ok {
+		return errors.New("power_ups must be a table")
+	}
+
+	boosts := 0
+	for i := 1; i <= table.Len(); i++ {
+		entry, ok := table.RawGetInt(i).(*lua.LTable)
+		if !ok {
+			return fmt.Errorf("power_ups[%d] must be a table", i)
+		}
+
+		name := lua.LVAsString(entry.RawGetString("name"))
+		image := assets.Image(lua.LVAsString(entry.RawGetString("image")))
+		if image == nil {
+			return fmt.Errorf("power-up %s has unknown image %q", name, lua.LVAsString(entry.RawGetString("image")))
+		}
+
+		var effect PowerUpEffect
+		switch effectName := lua.LVAsString(entry.RawGetString("effect")); effectName {
+		case "boost":
+			effect = boostEffect{}
+			boosts++
+		case "coin":
+			effect = coinEffect{}
+		case "freeze":
+			effect = freezeEffect{}
+		case "grow":
+			effect = growEffect{}
+		case "score":
+			effect = scoreEffect{points: int(lua.LVAsNumber(entry.RawGetString("points")))}
+		default:
+			return fmt.Errorf("power-up %s has unknown effect %q", name, effectName)
+		}
+
+		powerUp := &PowerUp{
+			Name:          name,
+			Factory:       newPickupFactory(image),
+			FirstSpawn:    int(lua.LVAsNumber(entry.RawGetString("first_spawn"))),
+			SpawnInterval: int(lua.LVAsNumber(entry.RawGetString("spawn_interval"))),
+			Duration:      int64(lua.LVAsNumber(entry.RawGetString("duration"))),
+			Effect:        effect,
+			RevealRange:   float64(lua.LVAsNumber(entry.RawGetString("reveal_range"))),
+		}
+		if powerUp.SpawnInterval <= 0 {
+			return fmt.Errorf("power-up %s must have a spawn_interval above 0", name)
+		}
+		if disguise := lua.LVAsString(entry.RawGetString("disguise")); disguise != "" {
+			if powerUp.Disguise = assets.Image(disguise); powerUp.Disguise == nil {
+				return fmt.Errorf("power-up %s has unknown disguise %q", name, disguise)
+			}
+		}
+		s.powerUps = append(s.powerUps, powerUp)
+	}
+
+	if boosts != 1 {
+		return errors.New("exactly one power-up must have the boost effect")
+	}
+	return nil
+}
+
+// call calls a function the script defines, returning nret results.  A function the script leaves out returns nils
+func (s *script) call(function string, nret int, args ...lua.LValue) ([]lua.LValue, error) {
+	results := make([]lua.LValue, nret)
+	fn := s.state.GetGlobal(function)
+	if fn.Type() != lua.LTFunction {
+		for i := range results {
+			results[i] = lua.LNil
+		}
+		return results, nil
+	}
+
+	if err := s.state.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.name, err)
+	}
+	for i := range results {
+		results[i] = s.state.Get(i - nret)
+	}
+	s.state.Pop(nret)
+	return results, nil
+}
+
+// killScore returns the points and bonus distance the script gives for destroying an asteroid
+func (s *script) killScore(event destructionEvent) (int, int, error) {
+	results, err := s.call("kill_score", 2,
+		lua.LString(destructionCauseNames[event.cause]),
+		lua.LNumber(event.sizeTier),
+		lua.LNumber(event.relativeSpeed),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(lua.LVAsNumber(results[0])), int(lua.LVAsNumber(results[1])), nil
+}
+
+// runSpawnScript is a spawn hook that passes every spawn through the script's spawn function, which can move it, cancel
+// it or add spawns of its own
+func (g *Game) runSpawnScript(event *SpawnEvent) {
+	if event.Sprite == nil {
+		return
+	}
+
+	spawn := rules.state.NewTable()
+	g.writeSpawn(spawn, event.Spawn)
+	results, err := rules.call("spawn", 1, spawn)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if results[0] == lua.LFalse {
+		event.Vetoed = true
+	}
+	g.readSpawn(spawn, &event.Spawn)
+
+	extra, ok := spawn.RawGetString("extra").(*lua.LTable)
+	if !ok {
+		return
+	}
+	for i := 1; i <= extra.Len(); i++ {
+		table, ok := extra.RawGetInt(i).(*lua.LTable)
+		if !ok {
+			continue
+		}
+		added, err := g.newScriptedSpawn(table)
+		if err != nil {
+			log.Println(fmt.Errorf("%s: %w", rules.name, err))
+			continue
+		}
+		event.Added = append(event.Added, added)
+	}
+}
+
+// writeSpawn sets the fields of a script spawn table from a spawn
+func (g *Game) writeSpawn(table *lua.LTable, spawn Spawn) {
+	table.RawSetString("kind", lua.LString(spawnKindNames[spawn.Kind]))
+	table.RawSetString("x", lua.LNumber(spawn.Sprite.X))
+	table.RawSetString("y", lua.LNumber(spawn.Sprite.Y))
+	table.RawSetString("x_velocity", lua.LNumber(spawn.Sprite.XVelocity))
+	table.RawSetString("y_velocity", lua.LNumber(spawn.Sprite.YVelocity))
+	if spawn.PowerUp != nil {
+		table.RawSetString("power_up", lua.LString(spawn.PowerUp.Name))
+	}
+}
+
+// readSpawn moves a spawn to where a script spawn table says it should be
+func (g *Game) readSpawn(table *lua.LTable, spawn *Spawn) {
+	spawn.Sprite.X = int(lua.LVAsNumber(table.RawGetString("x")))
+	spawn.Sprite.Y = int(lua.LVAsNumber(table.RawGetString("y")))
+	spawn.Sprite.XVelocity = float64(lua.LVAsNumber(table.RawGetString("x_velocity")))
+	spawn.Sprite.YVelocity = float64(lua.LVAsNumber(table.RawGetString("y_velocity")))
+}
+
+// newScriptedSpawn creates a spawn a script added, drawn with the image it names or the usual image of its kind
+func (g *Game) newScriptedSpawn(table *lua.LTable) (Spawn, error) {
+	var spawn Spawn
+	kindName := lua.LVAsString(table.RawGetString("kind"))
+	found := false
+	for kind, name := range spawnKindNames {
+		if name == kindName {
+			spawn.Kind, found = kind, true
+		}
+	}
+	if !found {
+		return Spawn{}, fmt.Errorf("extra spawn has unknown kind %q", kindName)
+	}
+
+	var image *ebiten.Image
+	switch spawn.Kind {
+	case SpawnPickup:
+		powerUpName := lua.LVAsString(table.RawGetString("power_up"))
+		for _, powerUp := range powerUps {
+			if powerUp.Name == powerUpName {
+				spawn.PowerUp = powerUp
+			}
+		}
+		if spawn.PowerUp == nil {
+			return Spawn{}, fmt.Errorf("extra pickup has unknown power-up %q", powerUpName)
+		}
+		image = spawn.PowerUp.Factory.Images[0]
+	default:
+		key := lua.LVAsString(table.RawGetString("image"))
+		if key == "" {
+			key = map[SpawnKind]string{SpawnSpire: imageBottomSpire, SpawnAsteroid: imageAsteroid1, SpawnRing: imageAsteroid2, SpawnTwins: imageAsteroid3, SpawnUFO: imageUFO, SpawnHoming: imageAsteroid4, SpawnNemesis: imageAsteroid1, SpawnGate: imageTopSpire}[spawn.Kind]
+		}
+		if image = g.assets.Image(key); image == nil {
+			return Spawn{}, fmt.Errorf("extra %s has unknown image %q", kindName, key)
+		}
+	}
+
+	spawn.Sprite = g.sprites.get()
+	spawn.Sprite.Image = image
+	g.readSpawn(table, &spawn)
+	return spawn, nil
+}
+
+// scoreEffect is the effect of a scripted power-up that adds points to the score
+type scoreEffect struct {
+	// points are the points added
+	points int
+}
+
+// Apply adds the points to the score
+func (e scoreEffect) Apply(g *Game) {
+	g.bonusScore += e.points
+}
+
+// Expire does nothing, the points are kept
+func (scoreEffect) Expire(g *Game) {}
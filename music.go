@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// musicBeat is the length of a beat of the background music
+	musicBeat = time.Millisecond * 500
+	// musicBars is the number of bars in the background music loop, four beats to a bar
+	musicBars = 4
+	// musicLength is the length of the background music loop
+	musicLength = musicBeat * 4 * musicBars
+	// musicVolume is the volume of the base layer of the background music
+	musicVolume = 0.3
+	// musicIntenseVolume is the volume of the intense layer of the background music at full intensity
+	musicIntenseVolume = 0.3
+	// musicIntensitySpeed is the speed the background music reaches full intensity at
+	musicIntensitySpeed = 8
+)
+
+// musicRoots are the root notes of each bar of the background music, in Hz
+var musicRoots = [musicBars]float64{110, 87.31, 130.81, 98}
+
+// musicPosition returns the bar, the beat within the bar and the time since the beat started for a time in the music loop
+func musicPosition(t float64) (int, int, float64) {
+	beat := int(t / musicBeat.Seconds())
+	return (beat / 4) % musicBars, beat % 4, t - float64(beat)*musicBeat.Seconds()
+}
+
+// synthesizeMusic generates the base and intense layers of the background music, following the given root of each bar
+func synthesizeMusic(roots [musicBars]float64) ([]byte, []byte) {
+	base := func(t float64, progress float64) float64 { return musicBaseSample(roots, t) }
+	intense := func(t float64, progress float64) float64 { return musicIntenseSample(roots, t) }
+	return synthesize(musicLength, base), synthesize(musicLength, intense)
+}
+
+// musicBaseSample is the base layer of the background music, a bass line and a soft pad following the root of each bar
+func musicBaseSample(roots [musicBars]float64, t float64) float64 {
+	bar, _, sinceBeat := musicPosition(t)
+	root := roots[bar]
+
+	bass := math.Sin(2*math.Pi*root*t) + 0.3*math.Sin(2*math.Pi*root*2*t)
+	bass *= math.Exp(-sinceBeat * 4)
+	pad := math.Sin(2*math.Pi*root*2*t) + math.Sin(2*math.Pi*root*3*t) + math.Sin(2*math.Pi*root*5/2*t)
+	return bass*0.5 + pad*0.08
+}
+
+// musicIntenseSample is the intense layer of the background music, an arpeggio and hi-hats that come in as speed rises
+func musicIntenseSample(roots [musicBars]float64, t float64) float64 {
+	bar, _, _ := musicPosition(t)
+	root := roots[bar]
+
+	// The arpeggio steps through the chord on sixteenth notes
+	sixteenth := musicBeat.Seconds() / 4
+	step := int(t/sixteenth) % 4
+	sinceStep := t - float64(int(t/sixteenth))*sixteenth
+	note := root * 4 * [4]float64{1, 1.2, 1.5, 2}[step]
+	arpeggio := math.Copysign(1, math.Sin(2*math.Pi*note*t)) * math.Exp(-sinceStep*20)
+
+	// The hi-hats are short bursts of noise on eighth notes
+	eighth := musicBeat.Seconds() / 2
+	sinceEighth := t - float64(int(t/eighth))*eighth
+	hiHat := (rand.Float64()*2 - 1) * math.Exp(-sinceEighth*60)
+
+	return arpeggio*0.15 + hiHat*0.3
+}
+
+// musicIntensity returns how intense the background music should be, from 0 to 1, rising with speed during a run
+func (g *Game) musicIntensity() float64 {
+	if g.mode != ModeGame {
+		return 0
+	}
+
+	intensity := (g.speed - 1) / (musicIntensitySpeed - 1)
+	if intensity < 0 {
+		return 0
+	} else if intensity > 1 {
+		return 1
+	}
+	return intensity
+}
+
+// updateMusic keeps the background music playing and sets its intensity for the current speed
+func (g *Game) updateMusic() {
+	g.audio.tick()
+	g.audio.playMusic(g.musicIntensity())
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"math"
+)
+
+const (
+	// nemesisSpawnDistance is how far into a run the nemesis comes back
+	nemesisSpawnDistance = 800
+	// nemesisBonusPoints is the bonus for destroying or surviving the nemesis
+	nemesisBonusPoints = 500
+)
+
+// Nemesis is the saved form of the asteroid that ended the last run it ended, brought back early in the next run for a rematch
+type Nemesis struct {
+	// Image is the name the asteroid's image is saved under
+	Image string `json:"image"`
+	// Y is the height the asteroid was at
+	Y int `json:"y"`
+	// XVelocity is the horizontal velocity the asteroid was moving at
+	XVelocity float64 `json:"xVelocity"`
+	// YVelocity is the vertical velocity the asteroid was moving at
+	YVelocity float64 `json:"yVelocity"`
+	// Homing represents whether the asteroid was steering toward the ship
+	Homing bool `json:"homing,omitempty"`
+}
+
+// recordKiller remembers the asteroid that hit the ship, to become the nemesis of the next run once this one ends
+func (g *Game) recordKiller(asteroid *spriteutils.Sprite) {
+	if g.killer != nil {
+		return
+	}
+	saved, err := encodeEntity(asteroid)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	g.killer = &Nemesis{Image: saved.Image, Y: asteroid.Y, XVelocity: asteroid.XVelocity, YVelocity: asteroid.YVelocity}
+	for _, e := range g.entities {
+		if e.Sprite == asteroid {
+			g.killer.Homing = e.isHoming
+		}
+	}
+}
+
+// recordNemesis saves the asteroid that ended the run as the nemesis of the next.  A nemesis that was beaten is
+// forgotten, unless another asteroid has taken its place
+func (g *Game) recordNemesis() {
+	if g.killer != nil {
+		g.profile.Nemesis = g.killer
+	} else if g.isNemesisBeaten {
+		g.profile.Nemesis = nil
+	}
+}
+
+// spawnNemesis brings back the asteroid that ended the last run, once the run is far enough along.  The title preview
+// doesn't have one, it isn't the player's run
+func (g *Game) spawnNemesis() {
+	if g.isPreview || g.profile.Nemesis == nil || g.isNemesisSpawned || g.distanceTravelled <= nemesisSpawnDistance {
+		return
+	}
+	g.isNemesisSpawned = true
+
+	nemesis := g.profile.Nemesis
+	sprite, err := decodeEntity(savedEntity{Type: entityAsteroid, Image: nemesis.Image, X: screenWidth + 100, Y: nemesis.Y})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	sprite.XVelocity, sprite.YVelocity = nemesis.XVelocity, nemesis.YVelocity
+	g.spawn(SpawnNemesis, sprite)
+}
+
+// addNemesis adds the nemesis to the world.  It is an asteroid like any other, marked so it can be told apart
+func (g *Game) addNemesis(sprite *spriteutils.Sprite) {
+	e := g.addEntity(entityAsteroid, sprite)
+	e.isHoming = g.profile.Nemesis != nil && g.profile.Nemesis.Homing
+	g.trackAsteroidPath(sprite)
+	g.nemesis = sprite
+}
+
+// updateNemesis counts the nemesis as survived once it has flown past every ship, and forgets it if it leaves the world some other way
+func (g *Game) updateNemesis() {
+	if g.nemesis == nil {
+		return
+	}
+
+	isInWorld := false
+	for _, e := range g.entities {
+		if e.Sprite == g.nemesis {
+			isInWorld = true
+		}
+	}
+	if !isInWorld {
+		g.nemesis = nil
+		return
+	}
+
+	width, _ := g.nemesis.Image.Size()
+	for _, ship := range g.ships() {
+		if g.nemesis.X+width >= ship.X {
+			return
+		}
+	}
+	x, y := spriteCenter(g.nemesis)
+	g.beatNemesis("SURVIVED", x, y)
+}
+
+// onNemesisDestroyed counts the nemesis as beaten when it is the asteroid destroyed
+func (g *Game) onNemesisDestroyed(event *Event) {
+	if g.nemesis != nil && event.Destruction.asteroid == g.nemesis {
+		g.beatNemesis("DESTROYED", event.X, event.Y)
+	}
+}
+
+// beatNemesis gives the bonus and achievement for destroying or surviving the nemesis
+func (g *Game) beatNemesis(how string, x, y float64) {
+	g.nemesis = nil
+	g.isNemesisBeaten = true
+	g.bonusScore += nemesisBonusPoints
+	g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d NEMESIS %s", nemesisBonusPoints, how), x: x, y: y})
+	g.unlockAchievement(achievementNemesis)
+}
+
+// drawNemesisMark draws a pulsing purple glow over the nemesis, so it is recognized as the asteroid from last time
+func (g *Game) drawNemesisMark(r Renderer) {
+	if g.nemesis == nil || isFarOffScreen(g.nemesis) {
+		return
+	}
+	pulse := 0.35 + 0.25*math.Sin(float64(g.frameCount)/8)
+	op := spriteTransform(g.nemesis)
+	op.ScaleColor(0, 0, 0, pulse)
+	op.TranslateColor(0.7, 0.2, 1, 0)
+	r.DrawImage(g.nemesis.Image, op)
+}
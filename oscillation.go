@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+)
+
+const (
+	// oscillationMinAmplitude is the least an oscillating spire pulls back from where it spawned
+	oscillationMinAmplitude = 60
+	// oscillationMaxAmplitude is the most an oscillating spire pulls back from where it spawned
+	oscillationMaxAmplitude = 160
+	// oscillationMinPeriod is the fewest frames an oscillating spire takes to pull back and return
+	oscillationMinPeriod = 120
+	// oscillationMaxPeriod is the most frames an oscillating spire takes to pull back and return
+	oscillationMaxPeriod = 240
+)
+
+// oscillateSpire decides whether a newly added spire moves up and down, at the difficulty's chance, picking how far and
+// how fast it moves.  Spires pull back toward their edge of the screen and return, so they never come loose from it
+func (g *Game) oscillateSpire(e *entity) {
+	chance := g.difficulty().OscillatingSpireChance
+	if chance == 0 || g.randIntn("spire oscillation", 10) >= chance {
+		return
+	}
+
+	_, height := e.Image.Size()
+	e.baseY = e.Y
+	e.oscillationAmplitude = float64(oscillationMinAmplitude + g.randIntn("spire amplitude", oscillationMaxAmplitude-oscillationMinAmplitude+1))
+	if e.Y+height/2 < screenHeight/2 {
+		e.oscillationAmplitude = -e.oscillationAmplitude
+	}
+	e.oscillationPeriod = int64(oscillationMinPeriod + g.randIntn("spire period", oscillationMaxPeriod-oscillationMinPeriod+1))
+	e.oscillationStart = g.frameCount - int64(g.randIntn("spire phase", int(e.oscillationPeriod)))
+}
+
+// updateOscillatingSpires moves every oscillating spire along its cycle, from where it spawned to its furthest pulled
+// back and back again.  The cycle follows the frame count, so spires far off screen are left until they come into view
+func (g *Game) updateOscillatingSpires() {
+	for _, e := range g.entities {
+		if e.oscillationPeriod == 0 || isFarOffScreen(e.Sprite) {
+			continue
+		}
+		phase := 2 * math.Pi * float64(g.frameCount-e.oscillationStart) / float64(e.oscillationPeriod)
+		e.Y = e.baseY + int(math.Round(e.oscillationAmplitude*(1-math.Cos(phase))/2))
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"math"
+	"math/rand"
+)
+
+const (
+	// fizzleFrames is how many frames the fizzle of a destroyed pickup lasts
+	fizzleFrames = 18
+	// thrustFlameFrames is how many frames each thruster flame particle lasts
+	thrustFlameFrames = 15
+)
+
+// particle is a single short-lived image moving freely, fading and changing size as it ages
+type particle struct {
+	// image is the image drawn for the particle, centered on its position
+	image *ebiten.Image
+	// x is the horizontal position of the center of the particle
+	x float64
+	// y is the vertical position of the center of the particle
+	y float64
+	// xVelocity is how far the particle moves horizontally every frame
+	xVelocity float64
+	// yVelocity is how far the particle moves vertically every frame
+	yVelocity float64
+	// gravity is added to the vertical velocity every frame
+	gravity float64
+	// drag scales the velocity every frame, 1 being no drag
+	drag float64
+	// rotation is the rotation of the particle
+	rotation float64
+	// spin is added to the rotation every frame
+	spin float64
+	// startScale is the scale of the particle when it is created
+	startScale float64
+	// endScale is the scale of the particle when it dies
+	endScale float64
+	// red, green and blue are the color the particle image is tinted with
+	red, green, blue float64
+	// lifetime is how many frames the particle lasts
+	lifetime int
+	// frames is the number of frames the particle has existed for
+	frames int
+	// scrolls represents whether the particle moves along with the world
+	scrolls bool
+}
+
+// progress returns how far through its lifetime the particle is, from 0 to 1
+func (p *particle) progress() float64 {
+	return float64(p.frames) / float64(p.lifetime)
+}
+
+// particleSystem updates and draws particles, recycling dead particles for the next ones emitted
+type particleSystem struct {
+	// particles are the live particles
+	particles []*particle
+	// free are the dead particles waiting to be reused
+	free []*particle
+	// snapshot holds the sprites of the particles from the last call to sprites, reused between calls
+	snapshot []spriteutils.Sprite
+}
+
+// emit adds a particle drawn with an image at a position, lasting for lifetime frames.  The particle has no velocity,
+// no drag, no tint and a scale of 1 until changed
+func (s *particleSystem) emit(image *ebiten.Image, x, y float64, lifetime int) *particle {
+	var p *particle
+	if len(s.free) == 0 {
+		p = &particle{}
+	} else {
+		p = s.free[len(s.free)-1]
+		s.free = s.free[:len(s.free)-1]
+	}
+
+	*p = particle{
+		image:      image,
+		x:          x,
+		y:          y,
+		drag:       1,
+		startScale: 1,
+		endScale:   1,
+		red:        1,
+		green:      1,
+		blue:       1,
+		lifetime:   lifetime,
+	}
+	s.particles = append(s.particles, p)
+	return p
+}
+
+// update moves every particle, scrolling particles that move with the world by the world speed, and recycles dead particles
+func (s *particleSystem) update(worldSpeed float64) {
+	temp := s.particles[:0]
+	for _, p := range s.particles {
+		p.x += p.xVelocity
+		p.y += p.yVelocity
+		if p.scrolls {
+			p.x -= worldSpeed
+		}
+		p.xVelocity *= p.drag
+		p.yVelocity = p.yVelocity*p.drag + p.gravity
+		p.rotation += p.spin
+		p.frames++
+
+		if p.frames < p.lifetime {
+			temp = append(temp, p)
+		} else {
+			s.free = append(s.free, p)
+		}
+	}
+	s.particles = temp
+}
+
+// reserve makes sure there are at least count particles either live or waiting to be reused, so emitting that many
+// at once doesn't allocate
+func (s *particleSystem) reserve(count int) {
+	for len(s.particles)+len(s.free) < count {
+		s.free = append(s.free, &particle{})
+	}
+	if cap(s.particles) < count {
+		particles := make([]*particle, len(s.particles), count)
+		copy(particles, s.particles)
+		s.particles = particles
+	}
+}
+
+// clear recycles every particle
+func (s *particleSystem) clear() {
+	s.free = append(s.free, s.particles...)
+	s.particles = s.particles[:0]
+}
+
+// draw draws every particle, fading and scaling as it ages
+func (s *particleSystem) draw(r Renderer) {
+	for _, p := range s.particles {
+		width, height := p.image.Size()
+		progress := p.progress()
+		scale := p.startScale + (p.endScale-p.startScale)*progress
+
+		op := &Transform{}
+		op.Translate(-float64(width)/2, -float64(height)/2)
+		op.Scale(scale, scale)
+		op.Rotate(p.rotation)
+		op.Translate(p.x, p.y)
+		op.ScaleColor(p.red, p.green, p.blue, 1-progress)
+		r.DrawImage(p.image, op)
+	}
+}
+
+// sprites returns a sprite for each particle, placed where the particle is, so particles can be recorded alongside the world.
+// The sprites are only valid until the next call
+func (s *particleSystem) sprites() []*spriteutils.Sprite {
+	s.snapshot = s.snapshot[:0]
+	for _, p := range s.particles {
+		width, height := p.image.Size()
+		s.snapshot = append(s.snapshot, spriteutils.Sprite{
+			Image:    p.image,
+			X:        int(p.x) - width/2,
+			Y:        int(p.y) - height/2,
+			Rotation: p.rotation,
+		})
+	}
+
+	sprites := make([]*spriteutils.Sprite, len(s.snapshot))
+	for i := range s.snapshot {
+		sprites[i] = &s.snapshot[i]
+	}
+	return sprites
+}
+
+// emitExplosion emits the flash, debris, sparks and smoke of an asteroid explosion centered on a position
+func (g *Game) emitExplosion(x, y float64) {
+	flash := g.particles.emit(g.assets.Image(imageAsteroidExplosion), x, y, 6)
+	flash.rotation = rand.Float64() * math.Pi
+	flash.scrolls = true
+
+	for i := 0; i < 6; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := 1 + rand.Float64()*2
+		debris := g.particles.emit(g.assets.Image(imageSmallAsteroid), x, y, 40)
+		debris.xVelocity = math.Cos(angle) * speed
+		debris.yVelocity = math.Sin(angle) * speed
+		debris.drag = 0.97
+		debris.spin = (rand.Float64() - 0.5) * 0.3
+		debris.startScale = 0.3 + rand.Float64()*0.2
+		debris.endScale = debris.startScale / 2
+		debris.scrolls = true
+	}
+
+	for i := 0; i < 10; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := 3 + rand.Float64()*3
+		spark := g.particles.emit(g.assets.Image(imageBeam), x, y, 25)
+		spark.xVelocity = math.Cos(angle) * speed
+		spark.yVelocity = math.Sin(angle) * speed
+		spark.drag = 0.9
+		spark.startScale = 3
+		spark.red, spark.green, spark.blue = 1, 0.7, 0.2
+		spark.scrolls = true
+	}
+
+	for i := 0; i < 3; i++ {
+		smoke := g.particles.emit(g.assets.Image(imageBeam), x+(rand.Float64()-0.5)*20, y+(rand.Float64()-0.5)*20, 45)
+		smoke.yVelocity = -0.3
+		smoke.startScale = 6
+		smoke.endScale = 18
+		smoke.red, smoke.green, smoke.blue = 0.4, 0.4, 0.4
+		smoke.scrolls = true
+	}
+}
+
+// emitThrustFlame emits a flame particle from the exhaust of every ship, blown back and away from the thrust
+func (g *Game) emitThrustFlame() {
+	direction := g.gravityDirection()
+	for _, ship := range g.ships() {
+		_, height := ship.Image.Size()
+		flame := g.particles.emit(g.assets.Image(imageBeam), float64(ship.X)+10, float64(ship.Y)+float64(height)*(0.5+0.25*direction), thrustFlameFrames)
+		flame.xVelocity = -1 - rand.Float64()
+		flame.yVelocity = (1 + rand.Float64()*1.5) * direction
+		flame.startScale = 5
+		flame.endScale = 1
+		flame.red, flame.green, flame.blue = g.flameColor()
+		flame.scrolls = true
+	}
+}
+
+// emitFizzle emits the fizzle of a destroyed pickup, the pickup image shrinking, spinning and fading away
+func (g *Game) emitFizzle(pickup *spriteutils.Sprite) {
+	x, y := spriteCenter(pickup)
+	fizzle := g.particles.emit(pickup.Image, x, y, fizzleFrames)
+	fizzle.spin = math.Pi / fizzleFrames
+	fizzle.endScale = 0
+	fizzle.scrolls = true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"image/color"
+	"log"
+)
+
+const (
+	// pauseBlurSpread is the distance in pixels between samples when blurring the paused scene
+	pauseBlurSpread = 2
+)
+
+// pauseShade is drawn over the world while the game is paused
+var pauseShade = color.RGBA{0x00, 0x00, 0x00, 0x80}
+
+// isPausePressed returns whether either of the pause keys was just pressed
+func (g *Game) isPausePressed() bool {
+	return g.input.IsKeyJustPressed(ebiten.KeyEscape) || g.input.IsKeyJustPressed(ebiten.KeyP)
+}
+
+// updatePause toggles between playing and paused, returning whether the game is paused.
+// Nothing in the world updates while paused, and all timers are based on frames, so they stop too
+func (g *Game) updatePause() bool {
+	if g.isPausePressed() || g.isGamepadJustPressed(gamepadButtonStart) {
+		if g.mode == ModeGame {
+			g.mode = ModePause
+		} else if g.mode == ModePause {
+			g.mode = ModeGame
+		}
+	}
+	return g.mode == ModePause
+}
+
+// holdAudio suspends the audio while the quick menu or frame stepping holds the run, and resumes it once the run carries on,
+// so the audio clock only counts frames the run is playing, as it does around a pause
+func (g *Game) holdAudio(isHeld bool) {
+	if isHeld == g.isAudioHeld {
+		return
+	}
+	g.isAudioHeld = isHeld
+	if isHeld {
+		g.audio.suspend()
+	} else {
+		g.audio.resume()
+	}
+}
+
+// drawPauseShade darkens the world behind the pause text
+func (g *Game) drawPauseShade(r Renderer) {
+	r.DrawRect(0, 0, float64(screenWidth), float64(screenHeight), pauseShade)
+}
+
+// capturePausedScene draws the frozen scene to an offscreen image once, so it can be shown blurred behind the pause text
+func (g *Game) capturePausedScene() *ebiten.Image {
+	capture, err := ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
+	g.renderer.begin(capture, PaletteNormal)
+	g.drawBackground(&g.renderer)
+	g.drawWorld(&g.renderer)
+	g.renderer.end()
+	return capture
+}
+
+// releasePauseCapture disposes of the captured paused scene once the game is resumed
+func (g *Game) releasePauseCapture() {
+	if g.pauseCapture == nil {
+		return
+	}
+	if err := g.pauseCapture.Dispose(); err != nil {
+		log.Println(err)
+	}
+	g.pauseCapture = nil
+}
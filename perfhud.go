@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"runtime"
+)
+
+const (
+	// perfHUDKey is the key that toggles the performance HUD
+	perfHUDKey = ebiten.KeyF3
+)
+
+// updatePerfHUD toggles the performance HUD when its key is pressed, remembering the choice in the settings, and counts
+// the frame's allocations while it is shown
+func (g *Game) updatePerfHUD() {
+	if g.input.IsKeyJustPressed(perfHUDKey) {
+		g.settings.PerfHUD = !g.settings.PerfHUD
+		if err := g.settings.save(); err != nil {
+			log.Println(err)
+		}
+	}
+	g.countAllocations()
+}
+
+// countAllocations counts the heap allocations made since the last frame.  Reading the memory stats briefly stops the
+// world, so nothing is counted while the performance HUD is hidden
+func (g *Game) countAllocations() {
+	if !g.settings.PerfHUD {
+		g.lastMallocs = 0
+		return
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if g.lastMallocs != 0 {
+		g.allocsPerFrame = stats.Mallocs - g.lastMallocs
+	}
+	g.lastMallocs = stats.Mallocs
+	g.gcCycles = stats.NumGC
+}
+
+// drawPerfHUD draws frame rate, tick rate, entity, draw call, allocation, garbage collection and pre-rotated image counts if
+// the performance HUD is enabled
+func (g *Game) drawPerfHUD(r Renderer) {
+	if !g.settings.PerfHUD {
+		return
+	}
+
+	r.DrawDebugText(fmt.Sprintf(
+		"FPS: %0.2f\nTPS: %0.2f\nENTITIES: %d\nDRAW CALLS: %d\nALLOCS/FRAME: %d\nGC CYCLES: %d\nPRE-ROTATED: %d (%d SKIPPED)",
+		ebiten.CurrentFPS(), ebiten.CurrentTPS(), g.entityCount, r.DrawCalls(), g.allocsPerFrame, g.gcCycles,
+		len(rotations.variants), rotations.skipped,
+	))
+}
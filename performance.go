@@ -0,0 +1,60 @@
+package main
+
+import (
+	"runtime/debug"
+)
+
+const (
+	// performanceGCPercent is the garbage collection target in performance mode.  Letting the heap grow further between
+	// collections means fewer of them, so fewer hitches on long runs
+	performanceGCPercent = 400
+)
+
+// startGCPercent is the garbage collection target the game was started with, from GOGC or else the Go default
+var startGCPercent = readGCPercent()
+
+// readGCPercent returns the current garbage collection target without changing it
+func readGCPercent() int {
+	percent := debug.SetGCPercent(100)
+	debug.SetGCPercent(percent)
+	return percent
+}
+
+// applyPerformanceMode sets the garbage collection target for whether performance mode is on
+func (s *Settings) applyPerformanceMode() {
+	if s.PerformanceMode {
+		debug.SetGCPercent(performanceGCPercent)
+	} else {
+		debug.SetGCPercent(startGCPercent)
+	}
+}
+
+// preallocate sizes the entity list and particle pool for the busiest run on record, in performance mode, so they don't
+// grow and leave garbage behind partway through a run.  The title screen preview is never busy enough to need it
+func (g *Game) preallocate() {
+	if g.isPreview || !g.settings.PerformanceMode {
+		return
+	}
+	g.entities = make([]*entity, 0, g.profile.PeakEntities)
+	g.particles.reserve(g.profile.PeakParticles)
+}
+
+// trackPeaks records the most entities and particles in the world at once during the run
+func (g *Game) trackPeaks() {
+	if len(g.entities) > g.peakEntities {
+		g.peakEntities = len(g.entities)
+	}
+	if len(g.particles.particles) > g.peakParticles {
+		g.peakParticles = len(g.particles.particles)
+	}
+}
+
+// recordPeaks keeps the run's peak entity and particle counts in the profile if they beat the ones on record
+func (g *Game) recordPeaks() {
+	if g.peakEntities > g.profile.PeakEntities {
+		g.profile.PeakEntities = g.peakEntities
+	}
+	if g.peakParticles > g.profile.PeakParticles {
+		g.profile.PeakParticles = g.peakParticles
+	}
+}
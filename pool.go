@@ -0,0 +1,101 @@
+package main
+
+import "github.com/llrowat/spriteutils"
+
+// spritePool recycles the sprites of culled and destroyed entities, so spawning at high speeds doesn't keep the garbage collector busy
+type spritePool struct {
+	// free are the released sprites waiting to be reused
+	free []*spriteutils.Sprite
+	// inPool are the released sprites, so releasing a sprite twice can be caught
+	inPool map[*spriteutils.Sprite]bool
+}
+
+// get returns a zeroed sprite, reusing a released one if there is one
+func (p *spritePool) get() *spriteutils.Sprite {
+	if len(p.free) == 0 {
+		return &spriteutils.Sprite{}
+	}
+	sprite := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+	delete(p.inPool, sprite)
+	return sprite
+}
+
+// isReleased returns whether a sprite has been released and not yet reused
+func (p *spritePool) isReleased(sprite *spriteutils.Sprite) bool {
+	return p.inPool[sprite]
+}
+
+// put releases a sprite to be reused.  Nothing may use the sprite after it is released
+func (p *spritePool) put(sprite *spriteutils.Sprite) {
+	if p.inPool == nil {
+		p.inPool = make(map[*spriteutils.Sprite]bool)
+	}
+	*sprite = spriteutils.Sprite{}
+	p.free = append(p.free, sprite)
+	p.inPool[sprite] = true
+}
+
+// explosionPool recycles expired explosions
+type explosionPool struct {
+	// free are the expired explosions waiting to be reused
+	free []*explosion
+}
+
+// get returns an explosion with a zeroed sprite, reusing an expired one if there is one
+func (p *explosionPool) get() *explosion {
+	if len(p.free) == 0 {
+		sprite := &spriteutils.Sprite{}
+		return &explosion{TransientSprite: &spriteutils.TransientSprite{Sprite: sprite}, sprite: sprite}
+	}
+	explosion := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+	return explosion
+}
+
+// put releases an explosion to be reused.  Nothing may use the explosion after it is released
+func (p *explosionPool) put(explosion *explosion) {
+	*explosion.sprite = spriteutils.Sprite{}
+	*explosion.TransientSprite = spriteutils.TransientSprite{Sprite: explosion.sprite}
+	explosion.radius = 0
+	p.free = append(p.free, explosion)
+}
+
+// generateSprite places a pooled sprite the same way the factory would, with a random image from the factory at a random
+// position within its bounds, drawn from the run's random number generator
+func (g *Game) generateSprite(factory *spriteutils.SpriteFactory) *spriteutils.Sprite {
+	sprite := g.sprites.get()
+	sprite.Image = factory.Images[g.randIntn("spawn image", len(factory.Images))]
+	sprite.X = factory.MinX + g.randIntn("spawn x", factory.MaxX-factory.MinX+1)
+	sprite.Y = factory.MinY + g.randIntn("spawn y", factory.MaxY-factory.MinY+1)
+	return sprite
+}
+
+// releaseSprite releases the sprite of a culled or destroyed spire, pickup or projectile back to the pool
+func (g *Game) releaseSprite(sprite *spriteutils.Sprite) {
+	if g.magnetTarget == sprite {
+		g.magnetTarget = nil
+	}
+	g.sprites.put(sprite)
+}
+
+// releaseAsteroid forgets everything tracked about a culled or destroyed asteroid, then releases its sprite back to the pool.
+// Releasing an asteroid that has already been released does nothing
+func (g *Game) releaseAsteroid(asteroid *spriteutils.Sprite) {
+	if g.sprites.isReleased(asteroid) {
+		return
+	}
+	delete(g.frozenAsteroids, asteroid)
+	delete(g.asteroidPaths, asteroid)
+	delete(g.nearMissAsteroids, asteroid)
+	g.releaseSprite(asteroid)
+}
+
+// releaseDestroyedAsteroids releases the asteroids destroyed this frame.  They are held until collision checks are done,
+// as the collision loops may still be looking at them after removing them from the world
+func (g *Game) releaseDestroyedAsteroids() {
+	for _, asteroid := range g.destroyedAsteroids {
+		g.releaseAsteroid(asteroid)
+	}
+	g.destroyedAsteroids = g.destroyedAsteroids[:0]
+}
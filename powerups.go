@@ -0,0 +1,209 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+)
+
+const (
+	// maxPickups is the most power-up pickups allowed in the world at once
+	maxPickups = 25
+	// boostFrames is how many frames the speed boost and shield from a star last
+	boostFrames = 5 * 60
+)
+
+// PowerUpEffect is what happens when a power-up is collected
+type PowerUpEffect interface {
+	// Apply starts the effect.  It is called every time the power-up is collected, even while it is already active
+	Apply(g *Game)
+	// Expire ends the effect once it has lasted its duration since the power-up was last collected
+	Expire(g *Game)
+}
+
+// PowerUp describes a type of pickup: how it spawns and what it does when collected
+type PowerUp struct {
+	// Name is the name of the power-up
+	Name string
+	// Factory generates the pickup sprites of the power-up
+	Factory *spriteutils.SpriteFactory
+	// FirstSpawn is the distance the first pickup of the power-up spawns at
+	FirstSpawn int
+	// SpawnInterval is the distance between pickups of the power-up
+	SpawnInterval int
+	// Duration is how many frames the effect lasts after the power-up is collected.  Effects with no duration never expire
+	Duration int64
+	// Effect is what happens when the power-up is collected
+	Effect PowerUpEffect
+	// Disguise is the image the pickups of the power-up are drawn with until a ship gets within RevealRange, nil for no disguise
+	Disguise *ebiten.Image
+	// RevealRange is how close a ship has to get to a disguised pickup to see what it really is
+	RevealRange float64
+}
+
+// activePowerUp is a collected power-up whose effect hasn't expired yet
+type activePowerUp struct {
+	// powerUp is the collected power-up
+	powerUp *PowerUp
+	// expiresAt is the frame the effect expires on
+	expiresAt int64
+}
+
+var (
+	// starPowerUp is the star, it is banked and gives a temporary speed boost and shield
+	starPowerUp *PowerUp
+	// freezePowerUp freezes every asteroid in place for a short time, or nil if the rules leave it out
+	freezePowerUp *PowerUp
+	// powerUps are all the power-ups that spawn during a run
+	powerUps []*PowerUp
+)
+
+// initializePowerUps takes every power-up from the rules script, picking out the star and the freeze
+func initializePowerUps(s *script) {
+	powerUps = s.powerUps
+	for _, powerUp := range powerUps {
+		switch powerUp.Effect.(type) {
+		case boostEffect:
+			starPowerUp = powerUp
+		case freezeEffect:
+			if freezePowerUp == nil {
+				freezePowerUp = powerUp
+			}
+		}
+	}
+}
+
+// newPickupFactory creates a factory that generates pickups just off the right of the screen
+func newPickupFactory(image *ebiten.Image) *spriteutils.SpriteFactory {
+	return &spriteutils.SpriteFactory{
+		Images: []*ebiten.Image{image},
+		MaxX:   screenWidth + 100,
+		MinX:   screenWidth + 100,
+		MaxY:   screenHeight - 100,
+		MinY:   100,
+	}
+}
+
+// boostEffect is the effect of a star, a temporary speed boost and shield
+type boostEffect struct{}
+
+// Apply banks the star and starts the boost if it isn't already running
+func (boostEffect) Apply(g *Game) {
+	g.starsCollected++
+	g.publish(&Event{Kind: EventStarCollected})
+	if !g.isBoosting {
+		g.isBoosting = true
+		g.speed += g.boostFactor
+		g.audio.announce(announceBoost)
+	}
+}
+
+// Expire ends the boost
+func (boostEffect) Expire(g *Game) {
+	g.isBoosting = false
+	g.speed -= g.boostFactor
+}
+
+// freezeEffect is the effect of a freeze pickup, freezing every asteroid in place
+type freezeEffect struct{}
+
+// Apply freezes every current asteroid
+func (freezeEffect) Apply(g *Game) {
+	g.freezeAsteroids()
+}
+
+// Expire gives the frozen asteroids back their velocity
+func (freezeEffect) Expire(g *Game) {
+	g.unfreezeAsteroids()
+}
+
+// resetPowerUps removes every pickup and active effect, and resets the spawn thresholds for a new run
+func (g *Game) resetPowerUps() {
+	g.activePowerUps = nil
+	g.powerUpSpawnThresholds = map[*PowerUp]int{}
+	for _, powerUp := range powerUps {
+		g.powerUpSpawnThresholds[powerUp] = powerUp.FirstSpawn
+	}
+}
+
+// spawnPowerUps spawns a pickup of each power-up as the player reaches its spawn threshold
+func (g *Game) spawnPowerUps() {
+	for _, powerUp := range powerUps {
+		if g.distanceTravelled > g.powerUpSpawnThresholds[powerUp] {
+			g.spawnPickup(powerUp, g.generateSprite(powerUp.Factory))
+			g.powerUpSpawnThresholds[powerUp] += powerUp.SpawnInterval
+		}
+	}
+}
+
+// addPickup adds a pickup of a power-up to the world, culling the oldest pickups first to keep within maxPickups
+func (g *Game) addPickup(powerUp *PowerUp, sprite *spriteutils.Sprite) {
+	g.addEntity(entityPickup, sprite).powerUp = powerUp
+}
+
+// updateDisguises shows each disguised pickup as its disguise until a ship gets close.  Pickups far off screen can't be
+// close to a ship, so are left until they come into view
+func (g *Game) updateDisguises() {
+	for _, e := range g.entities {
+		if e.collider == colliderPickup && !isFarOffScreen(e.Sprite) {
+			g.updateDisguise(e)
+		}
+	}
+}
+
+// checkPickupCollisions collects any pickups a ship has hit.  The effects are applied once the pickups are out of the
+// world, as some of them look through the entities
+func (g *Game) checkPickupCollisions() {
+	var collected []*PowerUp
+	g.filterEntities(func(e *entity) bool {
+		if e.collider != colliderPickup || !g.isShipColliding(e.Sprite) {
+			return true
+		}
+		collected = append(collected, e.powerUp)
+		g.releaseEntity(e)
+		return false
+	})
+	for _, powerUp := range collected {
+		g.collectPowerUp(powerUp)
+	}
+}
+
+// collectPowerUp applies the effect of a power-up, restarting its duration if it is already active
+func (g *Game) collectPowerUp(powerUp *PowerUp) {
+	powerUp.Effect.Apply(g)
+	if g.powerUpDuration(powerUp) == 0 {
+		return
+	}
+
+	expiresAt := g.frameCount + g.powerUpDuration(powerUp)
+	for _, active := range g.activePowerUps {
+		if active.powerUp == powerUp {
+			active.expiresAt = expiresAt
+			return
+		}
+	}
+	g.activePowerUps = append(g.activePowerUps, &activePowerUp{powerUp: powerUp, expiresAt: expiresAt})
+}
+
+// updatePowerUps expires the effects of power-ups that have lasted their duration
+func (g *Game) updatePowerUps() {
+	temp := g.activePowerUps[:0]
+	for _, active := range g.activePowerUps {
+		if g.frameCount >= active.expiresAt {
+			active.powerUp.Effect.Expire(g)
+		} else {
+			temp = append(temp, active)
+		}
+	}
+	g.activePowerUps = temp
+}
+
+// pickupSprites returns the sprites of every pickup of a power-up, or of every pickup if powerUp is nil
+func (g *Game) pickupSprites(powerUp *PowerUp) []*spriteutils.Sprite {
+	var sprites []*spriteutils.Sprite
+	for _, e := range g.entities {
+		if e.kind == entityPickup && (powerUp == nil || e.powerUp == powerUp) {
+			sprites = append(sprites, e.Sprite)
+		}
+	}
+	return sprites
+}
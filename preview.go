@@ -0,0 +1,64 @@
+package main
+
+import "image/color"
+
+const (
+	// titlePreviewLength is the distance the title screen preview runs for before starting over
+	titlePreviewLength = 5000
+)
+
+// titlePreviewShade is drawn over the title screen preview so it sits behind the title text
+var titlePreviewShade = color.RGBA{0x00, 0x00, 0x00, 0x60}
+
+// newTitlePreview creates the non-interactive world simulation shown behind the title screen.  It shares the player's
+// profile and settings, so it plays and shows the options selected on the title screen
+func newTitlePreview(profile *Profile, settings *Settings, assets *Assets) *Game {
+	preview := &Game{profile: profile, settings: settings, assets: assets, mode: ModeGame, input: ebitenInput{}, isPreview: true}
+	preview.AddSpawnHook(preview.runSpawnScript)
+	preview.resetGame()
+	return preview
+}
+
+// updateTitlePreview advances the title screen preview using the same spawning as a real run, without a player to collide with
+func (g *Game) updateTitlePreview() {
+	preview := g.titlePreview
+
+	preview.advance()
+	preview.updateStage()
+	preview.updateEntities()
+	preview.updateWater()
+	preview.updateRings()
+	preview.updateTethers()
+	preview.updateGates()
+	preview.updateCurrents()
+	preview.updateOscillatingSpires()
+	preview.updateDisguises()
+	preview.updateAsteroidPaths()
+	preview.spawnHazards()
+	preview.updateExplosions()
+	preview.frameCount++
+
+	if preview.distanceTravelled > titlePreviewLength {
+		preview.resetGame()
+	}
+}
+
+// drawTitlePreview draws the title screen preview, shaded, with the player's ship on top
+func (g *Game) drawTitlePreview(r Renderer) {
+	preview := g.titlePreview
+
+	sprites := preview.worldSprites()
+	for _, sprite := range sprites {
+		// The preview has no player, so its ship is left out
+		if sprite != preview.ship {
+			g.drawSprite(r, sprite)
+		}
+	}
+	preview.drawAsteroidPaths(r)
+	r.DrawRect(0, 0, float64(screenWidth), float64(screenHeight), titlePreviewShade)
+	for _, ship := range g.ships() {
+		r.DrawSprite(ship)
+	}
+
+	g.entityCount = len(sprites)
+}
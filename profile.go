@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// profileDirName is the directory created under the user config directory to hold save data
+	profileDirName = "galactic-asteroid-belt"
+	// profileFileName is the name of the save file
+	profileFileName = "profile.json"
+)
+
+// Profile represents the player data persisted between launches
+type Profile struct {
+	// LastSeenVersion is the game version whose "what's new" screen was last dismissed
+	LastSeenVersion string `json:"lastSeenVersion"`
+	// Difficulty is the name of the selected difficulty
+	Difficulty string `json:"difficulty"`
+	// Biome is the name of the selected biome
+	Biome string `json:"biome"`
+	// Wallet holds the stars and coins banked between runs
+	Wallet Wallet `json:"wallet"`
+	// BestDistance is the furthest distance travelled in a single run
+	BestDistance int `json:"bestDistance"`
+	// RecentDistances are the distances of the most recent runs, oldest first, used to tune how hard runs are
+	RecentDistances []int `json:"recentDistances"`
+	// RunsPlayed is how many runs have been finished, used to unlock game variants
+	RunsPlayed int `json:"runsPlayed"`
+	// LifetimeStars is how many stars have been collected across every run, used to unlock game variants
+	LifetimeStars int `json:"lifetimeStars"`
+	// PeakEntities is the most entities that have been in the world at once in a run, used to size the entity list in
+	// performance mode
+	PeakEntities int `json:"peakEntities"`
+	// PeakParticles is the most particles that have been live at once in a run, used to size the particle pool in
+	// performance mode
+	PeakParticles int `json:"peakParticles"`
+	// Nemesis is the asteroid that ended the last run ended by an asteroid, until it is beaten
+	Nemesis *Nemesis `json:"nemesis"`
+	// Achievements are the achievements the player has earned
+	Achievements []string `json:"achievements"`
+	// Loadouts are the loadouts the player has saved, oldest first
+	Loadouts []Loadout `json:"loadouts"`
+	// Purchases are the names of the items bought in the shop
+	Purchases []string `json:"purchases"`
+	// Flame is the name of the equipped thrust flame bought in the shop, empty for the standard flame
+	Flame string `json:"flame"`
+	// SegmentBests are the fewest frames taken to complete each segment of a run, indexed by segment
+	SegmentBests []int64 `json:"segmentBests"`
+	// Tampered represents whether the save file has ever failed its signature check.  Scores from a tampered profile aren't trusted
+	Tampered bool `json:"tampered"`
+	// Signature is the HMAC of the rest of the profile as it was saved, signed with the per-install key
+	Signature string `json:"signature"`
+}
+
+// profileDir returns the directory holding the save file and any other saved data
+func profileDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, profileDirName), nil
+}
+
+// profilePath returns the location of the save file
+func profilePath() (string, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, profileFileName), nil
+}
+
+// hasSavedProfile returns whether the profile has ever been saved, which it hasn't on the first launch
+func hasSavedProfile() bool {
+	path, err := profilePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// loadProfile reads the save file, returning an empty profile if none exists yet
+func loadProfile() (*Profile, error) {
+	profile := &Profile{}
+
+	path, err := profilePath()
+	if err != nil {
+		return profile, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profile, nil
+	}
+	if err != nil {
+		return profile, err
+	}
+
+	if err := json.Unmarshal(data, profile); err != nil {
+		return &Profile{}, err
+	}
+
+	// Don't trust a save file that has been edited outside the game
+	if err := profile.verify(data); err != nil {
+		return profile, err
+	}
+
+	// Don't trust a wallet that has been edited outside the game
+	if !profile.Wallet.isValid() {
+		profile.Wallet = Wallet{}
+		return profile, errors.New("wallet failed tamper check and has been reset")
+	}
+	return profile, nil
+}
+
+// save writes the profile to the save file, creating its directory if required
+func (p *Profile) save() error {
+	path, err := profilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if err := p.sign(); err != nil {
+		return err
+	}
+
+	// Only whitespace is added, so the saved bytes still match the signature
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
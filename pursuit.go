@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// pursuitWallStartX is where the right edge of the pursuit wall starts, off the left of the screen
+	pursuitWallStartX = -300
+	// pursuitWallMinX is the furthest off the left of the screen the pursuit wall can fall behind, so it is never far away
+	pursuitWallMinX = -400
+	// pursuitWallStartSpeed is how fast the pursuit wall moves through the world at the start of a run
+	pursuitWallStartSpeed = 1.5
+	// pursuitWallAcceleration is how much faster the pursuit wall gets every frame
+	pursuitWallAcceleration = 0.001
+	// pursuitHitSlowdown is how much speed is lost when the shield hits an asteroid in pursuit mode
+	pursuitHitSlowdown = 0.5
+	// pursuitMinSpeed is the slowest hits can slow the ship down to
+	pursuitMinSpeed = 1
+)
+
+// updatePursuitWall moves the pursuit wall toward the ship when the ship is slower than the wall, and away when it is faster.
+// The run ends if the wall reaches a ship
+func (g *Game) updatePursuitWall() {
+	if g.variant != VariantPursuit {
+		return
+	}
+
+	// Losing a boost after being slowed down can't leave the ship slower than the minimum
+	if g.speed < pursuitMinSpeed {
+		g.speed = pursuitMinSpeed
+	}
+
+	wallSpeed := pursuitWallStartSpeed + pursuitWallAcceleration*float64(g.frameCount)
+	g.pursuitWallX += wallSpeed - g.speed
+	if g.pursuitWallX < pursuitWallMinX {
+		g.pursuitWallX = pursuitWallMinX
+	}
+
+	for _, ship := range g.ships() {
+		if g.pursuitWallX >= float64(ship.X) {
+			g.mode = ModeGameOver
+		}
+	}
+}
+
+// slowPursuit slows the ship down after hitting an obstacle in pursuit mode, letting the wall catch up
+func (g *Game) slowPursuit() {
+	if g.variant != VariantPursuit {
+		return
+	}
+
+	g.speed -= pursuitHitSlowdown
+	if g.speed < pursuitMinSpeed {
+		g.speed = pursuitMinSpeed
+	}
+}
+
+// drawPursuitWall draws the pursuit wall from the left of the world up to its right edge
+func (g *Game) drawPursuitWall(r Renderer) {
+	margin := float64(offScreenMarginX())
+	if g.variant != VariantPursuit || g.pursuitWallX <= -margin {
+		return
+	}
+
+	op := &Transform{}
+	op.Scale(g.pursuitWallX+margin, float64(screenHeight))
+	op.Translate(-margin, 0)
+	op.ScaleColor(0.75, 0.125, 0.125, 0.6)
+	r.DrawImage(g.assets.Image(imageBeam), op)
+}
+
+// drawPursuitGap draws how far behind the ship the pursuit wall is
+func (g *Game) drawPursuitGap(r Renderer) {
+	if g.variant != VariantPursuit {
+		return
+	}
+
+	gap := float64(g.ship.X) - g.pursuitWallX
+	theme := g.theme()
+	gapStr := fmt.Sprintf("WALL: %d M", int(gap))
+	r.DrawText(gapStr, g.fonts().Small, g.hudX(len(gapStr)*g.ui(smallFontSize)/2, 0), g.ui(fontSize)+g.ui(smallFontSize)*7, theme.Accent())
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"math"
+)
+
+const (
+	// quickMenuKey is the key held to open the quick settings menu during a run
+	quickMenuKey = ebiten.KeyTab
+	// quickMenuButton is the gamepad button held to open the quick settings menu during a run
+	quickMenuButton = gamepadButtonLB
+	// quickMenuRadius is the distance of each toggle from the center of the quick settings menu
+	quickMenuRadius = 160
+)
+
+// quickToggle represents the toggles on the quick settings menu, clockwise from the top
+type quickToggle int
+
+const (
+	// quickNone represents no toggle being selected
+	quickNone quickToggle = iota
+	// quickMusic represents the background music toggle
+	quickMusic
+	// quickSound represents the sound effects toggle
+	quickSound
+	// quickScreenShake represents the screen shake toggle
+	quickScreenShake
+	// quickHUD represents the HUD toggle
+	quickHUD
+	// quickToggleCount is one more than the number of toggles on the quick settings menu
+	quickToggleCount
+)
+
+// quickToggleNames are the names of each toggle shown on the quick settings menu
+var quickToggleNames = map[quickToggle]string{
+	quickMusic:       "MUSIC",
+	quickSound:       "SOUND",
+	quickScreenShake: "SHAKE",
+	quickHUD:         "HUD",
+}
+
+// quickToggleAngle returns the direction of a toggle from the center of the quick settings menu
+func quickToggleAngle(toggle quickToggle) float64 {
+	return -math.Pi/2 + float64(toggle-quickMusic)*math.Pi/2
+}
+
+// updateQuickMenu opens the quick settings menu while its key or button is held during a run, selecting a toggle with the arrow
+// keys or left stick, and flips the selected toggle when it is let go.  It returns whether the run is held while the menu is open
+func (g *Game) updateQuickMenu() bool {
+	isHeld := g.input.IsKeyPressed(quickMenuKey) || g.isGamepadPressed(quickMenuButton)
+	if !g.isQuickMenuOpen {
+		if isHeld && g.mode == ModeGame {
+			g.isQuickMenuOpen = true
+			g.quickMenuSelected = quickNone
+			g.audio.setThrusting(false)
+		}
+		return g.isQuickMenuOpen
+	}
+
+	if isHeld {
+		if toggle := g.quickMenuDirection(); toggle != quickNone {
+			g.quickMenuSelected = toggle
+		}
+		return true
+	}
+
+	g.isQuickMenuOpen = false
+	switch g.quickMenuSelected {
+	case quickMusic:
+		g.settings.Music = !g.settings.Music
+	case quickSound:
+		g.settings.Sound = !g.settings.Sound
+	case quickScreenShake:
+		g.settings.ScreenShake = !g.settings.ScreenShake
+	case quickHUD:
+		g.settings.HUD = !g.settings.HUD
+	default:
+		return false
+	}
+	g.applySettings()
+	return false
+}
+
+// quickMenuDirection returns the toggle the arrow keys or left stick are pointing at, or quickNone if they aren't pointing anywhere
+func (g *Game) quickMenuDirection() quickToggle {
+	x, y := 0.0, 0.0
+	if g.input.IsKeyPressed(ebiten.KeyUp) {
+		y--
+	}
+	if g.input.IsKeyPressed(ebiten.KeyDown) {
+		y++
+	}
+	if g.input.IsKeyPressed(ebiten.KeyLeft) {
+		x--
+	}
+	if g.input.IsKeyPressed(ebiten.KeyRight) {
+		x++
+	}
+	for _, id := range g.gamepadIDs {
+		if stickX, stickY := g.input.GamepadAxis(id, 0), g.input.GamepadAxis(id, 1); math.Hypot(stickX, stickY) > gamepadStickThreshold {
+			x, y = stickX, stickY
+		}
+	}
+	if x == 0 && y == 0 {
+		return quickNone
+	}
+
+	// Each toggle owns the quarter of the circle centered on its direction
+	angle := math.Atan2(y, x) + math.Pi/2 + math.Pi/4
+	sector := int(math.Floor(angle/(math.Pi/2))+4) % 4
+	return quickMusic + quickToggle(sector)
+}
+
+// quickToggleValue returns whether a toggle on the quick settings menu is on
+func (g *Game) quickToggleValue(toggle quickToggle) bool {
+	switch toggle {
+	case quickMusic:
+		return g.settings.Music
+	case quickSound:
+		return g.settings.Sound
+	case quickScreenShake:
+		return g.settings.ScreenShake
+	default:
+		return g.settings.HUD
+	}
+}
+
+// drawQuickMenu draws the quick settings menu over the held run, with each toggle around the center and the selected one highlighted
+func (g *Game) drawQuickMenu(r Renderer) {
+	if !g.isQuickMenuOpen {
+		return
+	}
+
+	theme := g.theme()
+	g.drawPauseShade(r)
+	hint := "POINT AND RELEASE TO TOGGLE"
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight/2, theme.Text())
+
+	for toggle := quickMusic; toggle < quickToggleCount; toggle++ {
+		label := quickToggleNames[toggle] + ": " + onOff(g.quickToggleValue(toggle))
+		clr := theme.Text()
+		if toggle == g.quickMenuSelected {
+			label = "> " + label + " <"
+			clr = theme.Accent()
+		}
+		angle := quickToggleAngle(toggle)
+		x := float64(screenWidth)/2 + g.uiFloat(quickMenuRadius)*math.Cos(angle) - float64(len(label)*g.ui(fontSize)/2)/2
+		y := float64(screenHeight)/2 + g.uiFloat(quickMenuRadius)*math.Sin(angle)
+		r.DrawText(label, g.fonts().Normal, int(x), int(y), clr)
+	}
+}
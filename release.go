@@ -0,0 +1,10 @@
+//go:build !debug
+// +build !debug
+
+package main
+
+// debugBuild represents whether the game was built with the debug build tag
+const debugBuild = false
+
+// devFlag is always off in release builds, which have no -dev flag
+var devFlag = new(bool)
@@ -0,0 +1,359 @@
+package main
+
+import (
+	_ "embed"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/text"
+	"github.com/llrowat/spriteutils"
+	"golang.org/x/image/font"
+	"image/color"
+	"log"
+	"math"
+)
+
+// blurShaderSource is the Kage source of the shader used to blur images
+//
+//go:embed shaders/blur.kage
+var blurShaderSource []byte
+
+// invertShaderSource is the Kage source of the shader used to invert colors
+//
+//go:embed shaders/invert.kage
+var invertShaderSource []byte
+
+// Palette represents the colors a whole frame is shown in
+type Palette int
+
+const (
+	// PaletteNormal shows frames in their own colors
+	PaletteNormal Palette = iota
+	// PaletteInverted shows frames with every color inverted
+	PaletteInverted
+)
+
+// Camera represents the view of the world, applied to all world space drawing
+type Camera struct {
+	// X is the horizontal offset of the view
+	X float64
+	// Y is the vertical offset of the view
+	Y float64
+	// Zoom is the scale of the view around the center of the screen, 1 being unscaled
+	Zoom float64
+}
+
+// ToScreen returns where a point in world space appears on screen through the camera
+func (c Camera) ToScreen(x, y float64) (float64, float64) {
+	centerX, centerY := float64(screenWidth)/2, float64(screenHeight)/2
+	return (x-centerX)*c.Zoom + centerX + c.X, (y-centerY)*c.Zoom + centerY + c.Y
+}
+
+// defaultCamera is the camera that leaves the world unmoved and unscaled
+var defaultCamera = Camera{Zoom: 1}
+
+// Image is an image a Renderer can draw.  The game logic only needs its size, so it doesn't depend on how a renderer stores it
+type Image interface {
+	// Size returns the width and height of the image in pixels
+	Size() (int, int)
+}
+
+// Transform places and tints an image as it is drawn: an affine transform of its pixels, then a scale and offset of its
+// colors.  The zero value leaves the image unmoved and untinted, and each call applies after the ones before it
+type Transform struct {
+	// a1, b, c, d1, tx and ty are the affine matrix [a b tx; c d ty], with a and d stored less 1 so the zero value is the identity
+	a1, b, c, d1, tx, ty float64
+	// colorScale1 is the scale of the red, green, blue and alpha channels, stored less 1 so the zero value is the identity
+	colorScale1 [4]float64
+	// colorOffset is added to the red, green, blue and alpha channels after scaling them
+	colorOffset [4]float64
+}
+
+// Reset sets the transform back to leaving the image unmoved and untinted
+func (t *Transform) Reset() {
+	*t = Transform{}
+}
+
+// Translate moves the image by x and y
+func (t *Transform) Translate(x, y float64) {
+	t.tx += x
+	t.ty += y
+}
+
+// Scale scales the image by x and y about the origin
+func (t *Transform) Scale(x, y float64) {
+	a, d := t.a1+1, t.d1+1
+	t.a1, t.b, t.tx = a*x-1, t.b*x, t.tx*x
+	t.c, t.d1, t.ty = t.c*y, d*y-1, t.ty*y
+}
+
+// Rotate rotates the image by theta radians clockwise about the origin
+func (t *Transform) Rotate(theta float64) {
+	sin, cos := math.Sincos(theta)
+	a, d := t.a1+1, t.d1+1
+	t.a1, t.b, t.tx, t.c, t.d1, t.ty =
+		cos*a-sin*t.c-1, cos*t.b-sin*d, cos*t.tx-sin*t.ty,
+		sin*a+cos*t.c, sin*t.b+cos*d-1, sin*t.tx+cos*t.ty
+}
+
+// Matrix returns the affine matrix of the transform, mapping (x, y) to (a*x + b*y + tx, c*x + d*y + ty)
+func (t *Transform) Matrix() (a, b, c, d, tx, ty float64) {
+	return t.a1 + 1, t.b, t.c, t.d1 + 1, t.tx, t.ty
+}
+
+// ScaleColor scales the red, green, blue and alpha channels of the image
+func (t *Transform) ScaleColor(red, green, blue, alpha float64) {
+	for i, scale := range [4]float64{red, green, blue, alpha} {
+		t.colorScale1[i] = (t.colorScale1[i]+1)*scale - 1
+		t.colorOffset[i] *= scale
+	}
+}
+
+// TranslateColor adds to the red, green, blue and alpha channels of the image, each from 0 to 1
+func (t *Transform) TranslateColor(red, green, blue, alpha float64) {
+	for i, offset := range [4]float64{red, green, blue, alpha} {
+		t.colorOffset[i] += offset
+	}
+}
+
+// Color returns the scale and then offset of each of the red, green, blue and alpha channels of the transform
+func (t *Transform) Color() (scale, offset [4]float64) {
+	for i := range scale {
+		scale[i] = t.colorScale1[i] + 1
+	}
+	return scale, t.colorOffset
+}
+
+// Renderer draws the game, so the game logic never needs to touch the screen image directly.  Images and transforms are
+// passed in backend neutral types, so a renderer doesn't have to be built on ebiten
+type Renderer interface {
+	// DrawSprite draws a sprite in world space, transformed by the camera
+	DrawSprite(sprite *spriteutils.Sprite)
+	// DrawImage draws an image in world space, placed and tinted by a transform and then transformed by the camera
+	DrawImage(image Image, transform *Transform)
+	// DrawText draws text in screen space, unaffected by the camera
+	DrawText(str string, face font.Face, x, y int, clr color.Color)
+	// DrawRect draws a filled rectangle in screen space, unaffected by the camera
+	DrawRect(x, y, width, height float64, clr color.Color)
+	// DrawDebugText draws debug text at the top left of the screen
+	DrawDebugText(str string)
+	// SetCamera sets the camera used by all following world space drawing
+	SetCamera(camera Camera)
+	// DrawBlurred draws a screen sized image over the whole screen in screen space, blurred by sampling pixels spread apart
+	DrawBlurred(image Image, spread float64)
+	// DrawCalls returns the number of draws issued so far this frame
+	DrawCalls() int
+}
+
+// ebitenRenderer is the Renderer that draws to an ebiten screen image
+type ebitenRenderer struct {
+	// screen is the image everything in the current frame is drawn to, an offscreen frame when the palette changes its colors
+	screen *ebiten.Image
+	// target is the image the current frame ends up on
+	target *ebiten.Image
+	// palette is the palette of the current frame
+	palette Palette
+	// frame is the offscreen image a frame is drawn to before the palette is applied
+	frame *ebiten.Image
+	// camera is the camera used for world space drawing
+	camera Camera
+	// world is the offscreen image world space drawing goes to while the camera moves the view
+	world *ebiten.Image
+	// isWorldDirty represents whether anything has been drawn to world since it was last copied to the screen
+	isWorldDirty bool
+	// drawCalls is the number of draws issued so far this frame
+	drawCalls int
+	// op is reused to draw transformed images without allocating
+	op ebiten.DrawImageOptions
+	// blurShader is the shader used to blur images, compiled the first time it is needed
+	blurShader *ebiten.Shader
+	// isBlurUnavailable represents whether the blur shader failed to compile, in which case images are drawn unblurred
+	isBlurUnavailable bool
+	// invertShader is the shader used to invert colors, compiled the first time it is needed
+	invertShader *ebiten.Shader
+	// isInvertShaderUnavailable represents whether the invert shader failed to compile, in which case colors are inverted with a color matrix
+	isInvertShaderUnavailable bool
+}
+
+// begin starts drawing a new frame to the given screen image, shown in the given palette
+func (r *ebitenRenderer) begin(screen *ebiten.Image, palette Palette) {
+	r.target = screen
+	r.screen = screen
+	r.palette = palette
+	r.camera = defaultCamera
+	r.drawCalls = 0
+
+	if palette != PaletteNormal {
+		r.frame = screenImage(r.frame)
+		r.frame.Clear()
+		r.screen = r.frame
+	}
+}
+
+// end finishes drawing the current frame, applying the palette
+func (r *ebitenRenderer) end() {
+	r.flush()
+	if r.palette == PaletteInverted {
+		r.drawInverted(r.frame)
+	}
+	r.screen = nil
+	r.target = nil
+}
+
+// drawInverted draws an image over the target with every color inverted
+func (r *ebitenRenderer) drawInverted(image *ebiten.Image) {
+	r.drawCalls++
+
+	if r.invertShader == nil && !r.isInvertShaderUnavailable {
+		shader, err := ebiten.NewShader(invertShaderSource)
+		if err != nil {
+			log.Println(err)
+			r.isInvertShaderUnavailable = true
+		}
+		r.invertShader = shader
+	}
+
+	if r.isInvertShaderUnavailable {
+		op := &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(-1, -1, -1, 1)
+		op.ColorM.Translate(1, 1, 1, 0)
+		r.target.DrawImage(image, op)
+		return
+	}
+
+	width, height := image.Size()
+	op := &ebiten.DrawRectShaderOptions{Images: [4]*ebiten.Image{image}}
+	r.target.DrawRectShader(width, height, r.invertShader, op)
+}
+
+// worldTarget returns the image world space drawing should go to.  Drawing goes straight to the screen unless the camera moves the view
+func (r *ebitenRenderer) worldTarget() *ebiten.Image {
+	r.drawCalls++
+	if r.camera == defaultCamera {
+		return r.screen
+	}
+
+	r.world = screenImage(r.world)
+	r.isWorldDirty = true
+	return r.world
+}
+
+// screenImage returns an offscreen image the size of the screen, reusing the given one unless the orientation has changed since it was created
+func screenImage(image *ebiten.Image) *ebiten.Image {
+	if image != nil {
+		if width, height := image.Size(); width == screenWidth && height == screenHeight {
+			return image
+		}
+		image.Dispose()
+	}
+	image, _ = ebiten.NewImage(screenWidth, screenHeight, ebiten.FilterDefault)
+	return image
+}
+
+// flush copies any world space drawing to the screen, transformed by the camera
+func (r *ebitenRenderer) flush() {
+	if !r.isWorldDirty {
+		return
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-float64(screenWidth)/2, -float64(screenHeight)/2)
+	op.GeoM.Scale(r.camera.Zoom, r.camera.Zoom)
+	op.GeoM.Translate(float64(screenWidth)/2+r.camera.X, float64(screenHeight)/2+r.camera.Y)
+	r.screen.DrawImage(r.world, op)
+	r.drawCalls++
+
+	r.world.Clear()
+	r.isWorldDirty = false
+}
+
+// DrawSprite draws a sprite in world space, transformed by the camera
+func (r *ebitenRenderer) DrawSprite(sprite *spriteutils.Sprite) {
+	sprite.Draw(r.worldTarget())
+}
+
+// DrawImage draws an image in world space, placed and tinted by a transform and then transformed by the camera
+func (r *ebitenRenderer) DrawImage(image Image, transform *Transform) {
+	r.setDrawImageOptions(transform)
+	r.worldTarget().DrawImage(image.(*ebiten.Image), &r.op)
+}
+
+// setDrawImageOptions sets the reused ebiten draw options to apply a transform
+func (r *ebitenRenderer) setDrawImageOptions(transform *Transform) {
+	op := &r.op
+	op.ColorM.Reset()
+	a, b, c, d, tx, ty := transform.Matrix()
+	op.GeoM.SetElement(0, 0, a)
+	op.GeoM.SetElement(0, 1, b)
+	op.GeoM.SetElement(0, 2, tx)
+	op.GeoM.SetElement(1, 0, c)
+	op.GeoM.SetElement(1, 1, d)
+	op.GeoM.SetElement(1, 2, ty)
+	// An untinted image is left with the identity color matrix, which ebiten draws without one
+	scale, offset := transform.Color()
+	if scale != [4]float64{1, 1, 1, 1} {
+		op.ColorM.Scale(scale[0], scale[1], scale[2], scale[3])
+	}
+	if offset != [4]float64{} {
+		op.ColorM.Translate(offset[0], offset[1], offset[2], offset[3])
+	}
+}
+
+// DrawText draws text in screen space, unaffected by the camera
+func (r *ebitenRenderer) DrawText(str string, face font.Face, x, y int, clr color.Color) {
+	r.flush()
+	text.Draw(r.screen, str, face, x, y, clr)
+	r.drawCalls++
+}
+
+// DrawRect draws a filled rectangle in screen space, unaffected by the camera
+func (r *ebitenRenderer) DrawRect(x, y, width, height float64, clr color.Color) {
+	r.flush()
+	ebitenutil.DrawRect(r.screen, x, y, width, height, clr)
+	r.drawCalls++
+}
+
+// DrawDebugText draws debug text at the top left of the screen
+func (r *ebitenRenderer) DrawDebugText(str string) {
+	r.flush()
+	ebitenutil.DebugPrint(r.screen, str)
+	r.drawCalls++
+}
+
+// SetCamera sets the camera used by all following world space drawing
+func (r *ebitenRenderer) SetCamera(camera Camera) {
+	r.flush()
+	r.camera = camera
+}
+
+// DrawBlurred draws a screen sized image over the whole screen in screen space, blurred by sampling pixels spread apart
+func (r *ebitenRenderer) DrawBlurred(blurred Image, spread float64) {
+	image := blurred.(*ebiten.Image)
+	r.flush()
+	r.drawCalls++
+
+	if r.blurShader == nil && !r.isBlurUnavailable {
+		shader, err := ebiten.NewShader(blurShaderSource)
+		if err != nil {
+			log.Println(err)
+			r.isBlurUnavailable = true
+		}
+		r.blurShader = shader
+	}
+
+	if r.isBlurUnavailable {
+		r.screen.DrawImage(image, nil)
+		return
+	}
+
+	width, height := image.Size()
+	op := &ebiten.DrawRectShaderOptions{
+		Uniforms: map[string]interface{}{"Spread": float32(spread)},
+		Images:   [4]*ebiten.Image{image},
+	}
+	r.screen.DrawRectShader(width, height, r.blurShader, op)
+}
+
+// DrawCalls returns the number of draws issued so far this frame
+func (r *ebitenRenderer) DrawCalls() int {
+	return r.drawCalls
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"io"
+	"log"
+	"os"
+)
+
+const (
+	// replayFormatLegacy is the format of replays saved before replays had a header, a bare list of frames
+	replayFormatLegacy = 0
+	// replayFormatWhole is the format of replays saved as a header and every frame in full, in a single JSON document
+	replayFormatWhole = 1
+	// replayFormatVersion is the format replays are currently saved in, a gzipped stream of a header then one record per
+	// frame, each a keyframe or the changes since the frame before
+	replayFormatVersion = 2
+	// replayKeyframeInterval is how many frames apart keyframes are saved, every other frame being saved as changes
+	replayKeyframeInterval = 5 * 60
+	// replayMatchLookahead is how many entities ahead in the frame before an entity is looked for, to step over entities
+	// that have been removed since
+	replayMatchLookahead = 4
+	// replayMatchDistance is the furthest an entity can be from where it was expected to be and still be taken for the
+	// same entity as in the frame before
+	replayMatchDistance = 64
+)
+
+// replayFlag is a saved replay to play back instead of showing the title screen
+var replayFlag = flag.String("replay", "", "saved replay file to play back on launch")
+
+// replayFile is the saved form of a replay in the formats from before replays were streamed.  Replays store sprite
+// positions rather than seeds or inputs, so they play back the same no matter how spawn or physics constants change
+// between versions
+type replayFile struct {
+	// Format is the version of the replay format, used to pick how the rest of the file is read
+	Format int `json:"format"`
+	// GameVersion is the version of the game that saved the replay
+	GameVersion string `json:"gameVersion"`
+	// Frames are the recorded frames, oldest first
+	Frames [][]savedEntity `json:"frames"`
+}
+
+// replayHeader is the first record of a streamed replay
+type replayHeader struct {
+	// Format is the version of the replay format, used to pick how the rest of the file is read
+	Format int `json:"format"`
+	// GameVersion is the version of the game that saved the replay
+	GameVersion string `json:"gameVersion"`
+	// KeyframeInterval is how many frames apart keyframes were saved
+	KeyframeInterval int `json:"keyframeInterval"`
+	// Loadout is the options the run was played with, so it is shown as it was played.  It is nil for replays saved before loadouts existed
+	Loadout *Loadout `json:"loadout,omitempty"`
+}
+
+// replayRecord is the saved form of a single frame of a streamed replay
+type replayRecord struct {
+	// Entities are every entity of the frame, for keyframes
+	Entities []savedEntity `json:"entities,omitempty"`
+	// Length is how many entities the frame has, for frames saved as changes
+	Length int `json:"length,omitempty"`
+	// Changes are the entities that aren't where they were expected to be, for frames saved as changes
+	Changes []savedEntityChange `json:"changes,omitempty"`
+}
+
+// savedEntityChange is how an entity differs from where it was expected to be, given where it was in the frame before and
+// how far it moved then.  Entities not listed in a frame's changes kept moving as expected
+type savedEntityChange struct {
+	// Index is where the entity is in the frame
+	Index int `json:"i"`
+	// Skip is how many entities of the frame before were removed just before this one
+	Skip int `json:"s,omitempty"`
+	// Entity is the whole entity, for entities that weren't in the frame before
+	Entity *savedEntity `json:"e,omitempty"`
+	// DX and DY are how far the entity is from where it was expected to be
+	DX int `json:"x,omitempty"`
+	DY int `json:"y,omitempty"`
+	// Rotation is the rotation of the entity, if it changed
+	Rotation *float64 `json:"r,omitempty"`
+}
+
+// entityMotion is how far an entity moved between two frames
+type entityMotion struct {
+	x, y int
+}
+
+// replayDelta holds the last frame written or read, which the next frame is saved as changes against
+type replayDelta struct {
+	// previous is the last frame
+	previous []savedEntity
+	// motions are how far each entity of the last frame moved since the frame before it
+	motions []entityMotion
+}
+
+// keyframe starts the changes over from a frame saved in full
+func (d *replayDelta) keyframe(frame []savedEntity) {
+	d.previous = append(d.previous[:0], frame...)
+	d.motions = d.motions[:0]
+	for range frame {
+		d.motions = append(d.motions, entityMotion{})
+	}
+}
+
+// expected returns where the j-th entity of the last frame is expected to be, if it keeps moving as it was
+func (d *replayDelta) expected(j int) (int, int) {
+	return d.previous[j].X + d.motions[j].x, d.previous[j].Y + d.motions[j].y
+}
+
+// matches returns whether an entity is the j-th entity of the last frame, moved on
+func (d *replayDelta) matches(j int, entity savedEntity) bool {
+	if j < 0 || j >= len(d.previous) || d.previous[j].Type != entity.Type || d.previous[j].Image != entity.Image {
+		return false
+	}
+	x, y := d.expected(j)
+	return abs(entity.X-x) <= replayMatchDistance && abs(entity.Y-y) <= replayMatchDistance
+}
+
+// encode returns the changes from the last frame to a frame, and moves on to it
+func (d *replayDelta) encode(frame []savedEntity) replayRecord {
+	record := replayRecord{Length: len(frame)}
+	motions := make([]entityMotion, len(frame))
+
+	offset := 0
+	for i, entity := range frame {
+		skip := -1
+		for k := 0; k <= replayMatchLookahead; k++ {
+			if d.matches(i+offset+k, entity) {
+				skip = k
+				break
+			}
+		}
+
+		// An entity that wasn't in the frame before is saved whole, and doesn't use up an entity of the frame before
+		if skip < 0 {
+			saved := entity
+			record.Changes = append(record.Changes, savedEntityChange{Index: i, Entity: &saved})
+			offset--
+			continue
+		}
+
+		offset += skip
+		j := i + offset
+		x, y := d.expected(j)
+		change := savedEntityChange{Index: i, Skip: skip, DX: entity.X - x, DY: entity.Y - y}
+		if entity.Rotation != d.previous[j].Rotation {
+			rotation := entity.Rotation
+			change.Rotation = &rotation
+		}
+		if change != (savedEntityChange{Index: i}) {
+			record.Changes = append(record.Changes, change)
+		}
+		motions[i] = entityMotion{entity.X - d.previous[j].X, entity.Y - d.previous[j].Y}
+	}
+
+	d.previous = append(d.previous[:0], frame...)
+	d.motions = motions
+	return record
+}
+
+// decode restores a frame from its changes from the last frame, and moves on to it
+func (d *replayDelta) decode(record replayRecord) ([]savedEntity, error) {
+	frame := make([]savedEntity, record.Length)
+	motions := make([]entityMotion, record.Length)
+
+	offset := 0
+	changes := record.Changes
+	for i := range frame {
+		var change *savedEntityChange
+		if len(changes) > 0 && changes[0].Index == i {
+			change, changes = &changes[0], changes[1:]
+		}
+
+		if change != nil && change.Entity != nil {
+			frame[i] = *change.Entity
+			offset--
+			continue
+		}
+
+		if change != nil {
+			offset += change.Skip
+		}
+		j := i + offset
+		if j < 0 || j >= len(d.previous) {
+			return nil, fmt.Errorf("replay frame entity %d has no entity in the frame before", i)
+		}
+
+		frame[i] = d.previous[j]
+		frame[i].X, frame[i].Y = d.expected(j)
+		if change != nil {
+			frame[i].X += change.DX
+			frame[i].Y += change.DY
+			if change.Rotation != nil {
+				frame[i].Rotation = *change.Rotation
+			}
+		}
+		motions[i] = entityMotion{frame[i].X - d.previous[j].X, frame[i].Y - d.previous[j].Y}
+	}
+	if len(changes) > 0 {
+		return nil, fmt.Errorf("replay frame has a change to entity %d out of %d", changes[0].Index, record.Length)
+	}
+
+	d.previous = append(d.previous[:0], frame...)
+	d.motions = motions
+	return frame, nil
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// replayWriter streams frames to a replay in the current format, so a replay never has to be held in memory whole
+type replayWriter struct {
+	// compressor compresses the replay on its way to the file
+	compressor *gzip.Writer
+	// encoder writes each record as a line of JSON
+	encoder *json.Encoder
+	// count is how many frames have been written
+	count int
+	// delta is the last frame written, which the next is saved as changes against
+	delta replayDelta
+}
+
+// newReplayWriter starts a replay of a run played with a loadout, writing its header
+func newReplayWriter(w io.Writer, loadout Loadout) (*replayWriter, error) {
+	compressor := gzip.NewWriter(w)
+	writer := &replayWriter{compressor: compressor, encoder: json.NewEncoder(compressor)}
+	header := replayHeader{Format: replayFormatVersion, GameVersion: gameVersion, KeyframeInterval: replayKeyframeInterval, Loadout: &loadout}
+	if err := writer.encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// writeFrame writes the next frame, in full every replayKeyframeInterval frames and as changes from the frame before otherwise
+func (w *replayWriter) writeFrame(frame []savedEntity) error {
+	var record replayRecord
+	if w.count%replayKeyframeInterval == 0 {
+		record.Entities = frame
+		w.delta.keyframe(frame)
+	} else {
+		record = w.delta.encode(frame)
+	}
+	w.count++
+	return w.encoder.Encode(record)
+}
+
+// close finishes the replay.  It doesn't close the writer the replay was written to
+func (w *replayWriter) close() error {
+	return w.compressor.Close()
+}
+
+// replayReader reads the frames of a replay saved by any version of the game one at a time.  Replays in the current format
+// are streamed from the file, so only one frame is held in memory at once
+type replayReader struct {
+	// file is the replay file being read
+	file *os.File
+	// decoder reads each record of a streamed replay, nil for replays in older formats
+	decoder *json.Decoder
+	// header is the header of a streamed replay
+	header replayHeader
+	// frames are the frames of a replay in an older format, read whole
+	frames [][]savedEntity
+	// count is how many frames have been read
+	count int
+	// delta is the last frame read, which the next is restored from the changes against
+	delta replayDelta
+}
+
+// openReplay opens a replay file saved by any version of the game
+func openReplay(path string) (*replayReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := &replayReader{file: file}
+	buffered := bufio.NewReader(file)
+
+	// Streamed replays are gzipped, older formats are plain JSON
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		decompressor, err := gzip.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		reader.decoder = json.NewDecoder(decompressor)
+		if err := reader.decoder.Decode(&reader.header); err != nil {
+			file.Close()
+			return nil, err
+		}
+		if reader.header.Format != replayFormatVersion || reader.header.KeyframeInterval <= 0 {
+			file.Close()
+			return nil, fmt.Errorf("replay %s has unsupported format %d", path, reader.header.Format)
+		}
+		return reader, nil
+	}
+
+	data, err := io.ReadAll(buffered)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	replay, err := parseReplayFile(path, data)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader.frames = replay.Frames
+	return reader, nil
+}
+
+// parseReplayFile reads a replay file in one of the formats from before replays were streamed
+func parseReplayFile(path string, data []byte) (*replayFile, error) {
+	// Legacy replays have no header, just the list of frames
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		replay := &replayFile{Format: replayFormatLegacy}
+		if err := json.Unmarshal(data, &replay.Frames); err != nil {
+			return nil, err
+		}
+		replay.Format = replayFormatWhole
+		return replay, nil
+	}
+
+	replay := &replayFile{}
+	if err := json.Unmarshal(data, replay); err != nil {
+		return nil, err
+	}
+
+	switch replay.Format {
+	case replayFormatWhole:
+		return replay, nil
+	default:
+		return nil, fmt.Errorf("replay %s has unsupported format %d", path, replay.Format)
+	}
+}
+
+// next returns the next frame of the replay, or io.EOF once every frame has been read
+func (r *replayReader) next() ([]savedEntity, error) {
+	if r.decoder == nil {
+		if r.count >= len(r.frames) {
+			return nil, io.EOF
+		}
+		r.count++
+		return r.frames[r.count-1], nil
+	}
+
+	var record replayRecord
+	if err := r.decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+
+	keyframe := r.count%r.header.KeyframeInterval == 0
+	r.count++
+	if keyframe {
+		r.delta.keyframe(record.Entities)
+		return record.Entities, nil
+	}
+	return r.delta.decode(record)
+}
+
+// nextSprites returns the sprites of the next frame of the replay, or io.EOF once every frame has been read
+func (r *replayReader) nextSprites() ([]*spriteutils.Sprite, error) {
+	entities, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	sprites := make([]*spriteutils.Sprite, 0, len(entities))
+	for _, entity := range entities {
+		sprite, err := decodeEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+		sprites = append(sprites, sprite)
+	}
+	return sprites, nil
+}
+
+// close closes the replay file
+func (r *replayReader) close() error {
+	return r.file.Close()
+}
+
+// playReplay starts playing back a saved replay from a file, a frame at a time as it is read
+func (g *Game) playReplay(path string) error {
+	reader, err := openReplay(path)
+	if err != nil {
+		return err
+	}
+	g.replay = reader
+	g.replayFrame = nil
+	g.mode = ModeReplay
+	return nil
+}
+
+// stopReplay closes the replay being played back
+func (g *Game) stopReplay() {
+	if g.replay == nil {
+		return
+	}
+	if err := g.replay.close(); err != nil {
+		log.Println(err)
+	}
+	g.replay = nil
+	g.replayFrame = nil
+}
+
+// updateReplay reads the next frame of the replay being played back, going to the title screen when it ends or is skipped
+func (g *Game) updateReplay() {
+	if g.input.IsKeyJustPressed(ebiten.KeySpace) || g.input.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.mode = ModeTitle
+		return
+	}
+
+	frame, err := g.replay.nextSprites()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			log.Println(err)
+		}
+		g.mode = ModeTitle
+		return
+	}
+	g.replayFrame = frame
+}
+
+// drawReplay draws the current frame of the replay being played back, zoomed out like the kill-cam it was recorded from
+func (g *Game) drawReplay(r Renderer) {
+	r.SetCamera(Camera{Zoom: killCamZoom})
+	for _, sprite := range g.replayFrame {
+		g.drawSprite(r, sprite)
+	}
+	r.SetCamera(defaultCamera)
+
+	theme := g.theme()
+	r.DrawText("REPLAY", g.fonts().Normal, g.ui(fontSize), g.ui(fontSize)*2, theme.Text())
+	if g.replay != nil && g.replay.header.Loadout != nil {
+		loadout := g.replay.header.Loadout
+		r.DrawText(loadoutSummary(*loadout), g.fonts().Small, g.ui(fontSize), g.ui(fontSize)*3, theme.Text())
+	}
+	r.DrawText("SPACE: STOP", g.fonts().Small, g.ui(fontSize), screenHeight-g.ui(fontSize), theme.Text())
+
+	g.entityCount = len(g.replayFrame)
+}
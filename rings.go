@@ -0,0 +1,143 @@
+package main
+
+import (
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// maxRings is the most asteroid rings allowed in the world at once
+	maxRings = 2
+	// ringChildCount is the number of small asteroids orbiting the core of a ring
+	ringChildCount = 8
+	// ringRadius is the distance from the center of the core to the center of each orbiting asteroid
+	ringRadius = 120
+	// ringAngularVelocity is how far the ring rotates each frame, in radians
+	ringAngularVelocity = 0.025
+	// ringSweepSpeed is how much faster than the world the ring moves across the screen
+	ringSweepSpeed = 1
+	// ringVerticalSpeed is how fast the ring sweeps up and down the screen
+	ringVerticalSpeed = 1.5
+	// ringMargin is the closest the center of the core gets to the top or bottom of the screen
+	ringMargin = 200
+)
+
+// ringChild is an asteroid orbiting the core of a ring, positioned relative to the core rather than moving on its own
+type ringChild struct {
+	// sprite is the orbiting asteroid
+	sprite *spriteutils.Sprite
+	// angle is the position of the asteroid around the core, relative to the rotation of the ring
+	angle float64
+}
+
+// asteroidRing is a mini-boss hazard, a rotating ring of small asteroids around a central core
+type asteroidRing struct {
+	// core is the asteroid at the center of the ring, it moves and the children follow
+	core *spriteutils.Sprite
+	// children are the asteroids orbiting the core
+	children []*ringChild
+	// rotation is the current rotation of the ring, in radians
+	rotation float64
+	// sweepDirection is the vertical direction the ring is sweeping in, 1 for down and -1 for up
+	sweepDirection float64
+}
+
+// newAsteroidRing creates a ring of asteroids around the given core
+func (g *Game) newAsteroidRing(core *spriteutils.Sprite) *asteroidRing {
+	ring := &asteroidRing{core: core, sweepDirection: 1}
+	if g.randIntn("ring sweep direction", 2) == 0 {
+		ring.sweepDirection = -1
+	}
+	for i := 0; i < ringChildCount; i++ {
+		ring.children = append(ring.children, &ringChild{
+			sprite: &spriteutils.Sprite{Image: g.assets.Image(imageSmallAsteroid)},
+			angle:  2 * math.Pi * float64(i) / ringChildCount,
+		})
+	}
+	ring.updateChildren()
+	return ring
+}
+
+// generateRingCore creates the core of a new ring just off the right of the screen
+func (g *Game) generateRingCore() *spriteutils.Sprite {
+	_, height := g.assets.Image(imageAsteroid2).Size()
+	return &spriteutils.Sprite{
+		Image:     g.assets.Image(imageAsteroid2),
+		X:         screenWidth + ringRadius,
+		Y:         ringMargin + g.randIntn("ring y", screenHeight-ringMargin*2) - height/2,
+		XVelocity: -(g.speed + ringSweepSpeed),
+	}
+}
+
+// updateChildren moves every child of the ring to its place around the core
+func (r *asteroidRing) updateChildren() {
+	coreX, coreY := spriteCenter(r.core)
+	for _, child := range r.children {
+		width, height := child.sprite.Image.Size()
+		angle := r.rotation + child.angle
+		child.sprite.X = int(coreX+math.Cos(angle)*ringRadius) - width/2
+		child.sprite.Y = int(coreY+math.Sin(angle)*ringRadius) - height/2
+		child.sprite.Rotation = angle
+	}
+}
+
+// sprites returns the core and children of the ring
+func (r *asteroidRing) sprites() []*spriteutils.Sprite {
+	sprites := []*spriteutils.Sprite{r.core}
+	for _, child := range r.children {
+		sprites = append(sprites, child.sprite)
+	}
+	return sprites
+}
+
+// updateRings moves and rotates the rings, and destroys rings that have left the screen.
+// Frozen rings stop rotating and sweeping, moving only with the world
+func (g *Game) updateRings() {
+	temp := g.rings[:0]
+	for _, ring := range g.rings {
+		if g.isFreezeActive() {
+			ring.core.XVelocity = -g.speed
+			ring.core.YVelocity = 0
+		} else {
+			ring.core.XVelocity = -(g.speed + ringSweepSpeed)
+			ring.core.YVelocity = ringVerticalSpeed * ring.sweepDirection
+			ring.rotation += ringAngularVelocity
+		}
+		ring.core.Update()
+
+		// Sweep back the other way at the top and bottom of the screen
+		_, coreY := spriteCenter(ring.core)
+		if (coreY < ringMargin && ring.sweepDirection < 0) || (coreY > float64(screenHeight-ringMargin) && ring.sweepDirection > 0) {
+			ring.sweepDirection = -ring.sweepDirection
+		}
+
+		ring.updateChildren()
+
+		if ring.core.X > tuning.OutOfBoundsX-ringRadius {
+			temp = append(temp, ring)
+		}
+	}
+	g.rings = temp
+}
+
+// checkRingCollisions ends the game if a ship hits a ring.  The shield destroys orbiting asteroids, but not the core
+func (g *Game) checkRingCollisions() {
+	for _, ring := range g.rings {
+		if g.isShipColliding(ring.core) {
+			g.mode = ModeGameOver
+		}
+
+		temp := ring.children[:0]
+		for _, child := range ring.children {
+			if g.isShieldColliding(child.sprite) {
+				g.destroyAsteroid(child.sprite, destroyedByShield)
+				continue
+			}
+			if g.isShipColliding(child.sprite) {
+				g.mode = ModeGameOver
+			}
+			temp = append(temp, child)
+		}
+		ring.children = temp
+	}
+}
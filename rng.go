@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// rngLogKey is the key that dumps the RNG audit log in debug builds
+	rngLogKey = ebiten.KeyF4
+	// rngLogDirName is the directory under the profile directory that RNG audit logs are written to
+	rngLogDirName = "rng"
+)
+
+// seedFlag is the seed every run's spawns are drawn from, so runs can be reproduced for testing and speedruns.  0 seeds each run randomly
+var seedFlag = flag.Int64("seed", 0, "seed every run's spawns are drawn from, 0 for a random seed each run")
+
+// rngDraw is a single random number drawn during a run
+type rngDraw struct {
+	// frame is the frame the number was drawn on
+	frame int64
+	// purpose is what the number was drawn for
+	purpose string
+	// value is the number drawn
+	value float64
+}
+
+// resetRNG seeds the run's random number generator, from the game's seed if it has one, and clears the RNG audit log
+func (g *Game) resetRNG() {
+	g.runSeed = g.seed
+	if g.runSeed == 0 {
+		g.runSeed = time.Now().UnixNano()
+	}
+	g.rng = rand.New(rand.NewSource(g.runSeed))
+	g.rngLog = nil
+}
+
+// randIntn returns a random number from 0 up to n, recording it in the RNG audit log in debug builds
+func (g *Game) randIntn(purpose string, n int) int {
+	value := g.rng.Intn(n)
+	g.recordRNGDraw(purpose, float64(value))
+	return value
+}
+
+// randFloat64 returns a random number from 0 up to 1, recording it in the RNG audit log in debug builds
+func (g *Game) randFloat64(purpose string) float64 {
+	value := g.rng.Float64()
+	g.recordRNGDraw(purpose, value)
+	return value
+}
+
+// recordRNGDraw adds a random number to the RNG audit log.  The log is only kept in debug builds
+func (g *Game) recordRNGDraw(purpose string, value float64) {
+	if !debugBuild {
+		return
+	}
+	g.rngLog = append(g.rngLog, rngDraw{frame: g.frameCount, purpose: purpose, value: value})
+}
+
+// updateRNGLog dumps the RNG audit log when its key is pressed in debug builds
+func (g *Game) updateRNGLog() {
+	if !debugBuild || !g.input.IsKeyJustPressed(rngLogKey) {
+		return
+	}
+
+	path, err := g.dumpRNGLog()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Printf("RNG audit log written to %s", path)
+}
+
+// dumpRNGLogOnPanic dumps the RNG audit log if the game is crashing, then carries on crashing.  It must be deferred
+func (g *Game) dumpRNGLogOnPanic() {
+	if err := recover(); err != nil {
+		if path, dumpErr := g.dumpRNGLog(); dumpErr != nil {
+			log.Println(dumpErr)
+		} else {
+			log.Printf("RNG audit log written to %s", path)
+		}
+		panic(err)
+	}
+}
+
+// dumpRNGLog writes the RNG audit log for the current run to a file in the profile directory, returning the path of the file
+func (g *Game) dumpRNGLog() (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "seed\t%d\n", g.runSeed)
+	for _, draw := range g.rngLog {
+		fmt.Fprintf(&b, "%d\t%s\t%v\n", draw.frame, draw.purpose, draw.value)
+	}
+
+	dir, err := profileDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, rngLogDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("rng-%d.log", time.Now().Unix()))
+	return path, os.WriteFile(path, []byte(b.String()), 0644)
+}
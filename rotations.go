@@ -0,0 +1,150 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"image"
+	"log"
+	"math"
+)
+
+const (
+	// rotationSteps is how many rotated variants of each asteroid image are pre-rendered, evenly spaced around a full turn
+	rotationSteps = 16
+	// rotationBuildsPerFrame is how many images have their rotated variants pre-rendered each frame, so building the
+	// cache is spread over the first frames instead of holding up the launch
+	rotationBuildsPerFrame = 4
+	// rotationCacheBudget is the most memory in bytes the pre-rendered variants can take.  Images that would go over it
+	// are left out and rotated as they are drawn
+	rotationCacheBudget = 64 << 20
+)
+
+// rotationCache holds pre-rendered rotated variants of the asteroid images, each image's variants laid out side by side
+// in an atlas.  Drawing the nearest variant is cheaper on weak GPUs than rotating every tumbling asteroid each frame
+type rotationCache struct {
+	// variants are the rotated variants of each image built so far, by the image, the first being unrotated
+	variants map[*ebiten.Image][]*ebiten.Image
+	// pending are the images still to have their variants built, first to be built first
+	pending []*ebiten.Image
+	// queued are the images that have been queued, so none is queued twice
+	queued map[*ebiten.Image]bool
+	// bytes is the memory taken by the atlases built so far
+	bytes int
+	// skipped is how many images were left out for going over the memory budget
+	skipped int
+}
+
+// rotations are the pre-rendered rotated variants of the asteroid images
+var rotations = &rotationCache{variants: map[*ebiten.Image][]*ebiten.Image{}, queued: map[*ebiten.Image]bool{}}
+
+// initializeRotations queues every asteroid image for pre-rendering: the small ring asteroids first, as they always
+// tumble, then every stage's asteroids at every size with the fragments they split into, then the seasonal skins of them
+func initializeRotations(assets *Assets) {
+	rotations.queue(assets.Image(imageSmallAsteroid))
+	for _, stage := range stages {
+		for _, size := range asteroidSizes {
+			for _, key := range asteroidImageKeys {
+				rotations.queueWithFragments(scaledImage(assets.Image(stage.prefix+key), size.scale))
+			}
+		}
+	}
+	for _, season := range seasons {
+		for _, size := range asteroidSizes {
+			for _, key := range asteroidImageKeys {
+				rotations.queueWithFragments(scaledImage(assets.Image(season.prefix+key), size.scale))
+			}
+		}
+	}
+}
+
+// queue adds an image to be pre-rendered, unless it already has been
+func (c *rotationCache) queue(image *ebiten.Image) {
+	if c.queued[image] {
+		return
+	}
+	c.queued[image] = true
+	c.pending = append(c.pending, image)
+}
+
+// queueWithFragments adds an asteroid image and the images of the fragments it splits into to be pre-rendered
+func (c *rotationCache) queueWithFragments(image *ebiten.Image) {
+	c.queue(image)
+	for _, fragment := range fragmentImages(image) {
+		c.queue(fragment)
+	}
+}
+
+// update builds the variants of the next few queued images
+func (c *rotationCache) update() {
+	for i := 0; i < rotationBuildsPerFrame && len(c.pending) > 0; i++ {
+		image := c.pending[0]
+		c.pending = c.pending[1:]
+		if err := c.build(image); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// build pre-renders the rotated variants of an image into an atlas, one square cell per variant big enough for the
+// image at any angle.  Images whose atlas would go over the memory budget are skipped
+func (c *rotationCache) build(img *ebiten.Image) error {
+	width, height := img.Size()
+	cell := int(math.Ceil(math.Hypot(float64(width), float64(height))))
+	size := cell * cell * rotationSteps * 4
+	if c.bytes+size > rotationCacheBudget {
+		c.skipped++
+		return nil
+	}
+
+	atlas, err := ebiten.NewImage(cell*rotationSteps, cell, ebiten.FilterDefault)
+	if err != nil {
+		return err
+	}
+	variants := make([]*ebiten.Image, rotationSteps)
+	for i := range variants {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-float64(width)/2, -float64(height)/2)
+		op.GeoM.Rotate(2 * math.Pi * float64(i) / rotationSteps)
+		op.GeoM.Translate(float64(cell*i)+float64(cell)/2, float64(cell)/2)
+		op.Filter = ebiten.FilterLinear
+		atlas.DrawImage(img, op)
+		variants[i] = atlas.SubImage(image.Rect(cell*i, 0, cell*(i+1), cell)).(*ebiten.Image)
+	}
+	c.variants[img] = variants
+	c.bytes += size
+	return nil
+}
+
+// variant returns the pre-rendered variant of an image nearest a rotation, or false if the image has none
+func (c *rotationCache) variant(image *ebiten.Image, rotation float64) (*ebiten.Image, bool) {
+	variants, ok := c.variants[image]
+	if !ok {
+		return nil, false
+	}
+	step := int(math.Round(rotation/(2*math.Pi)*rotationSteps)) % rotationSteps
+	if step < 0 {
+		step += rotationSteps
+	}
+	return variants[step], true
+}
+
+// drawSprite draws a world sprite with its seasonal skin, using the nearest pre-rendered variant of its image if it is
+// rotated and has them, and rotating it as it is drawn otherwise
+func (g *Game) drawSprite(r Renderer, sprite *spriteutils.Sprite) {
+	sprite = g.skinned(sprite)
+	if sprite.Rotation == 0 {
+		r.DrawSprite(sprite)
+		return
+	}
+	variant, ok := rotations.variant(sprite.Image, sprite.Rotation)
+	if !ok {
+		r.DrawSprite(sprite)
+		return
+	}
+
+	width, height := sprite.Image.Size()
+	cell, _ := variant.Size()
+	g.rotationOp.Reset()
+	g.rotationOp.Translate(float64(sprite.X)+float64(width-cell)/2, float64(sprite.Y)+float64(height-cell)/2)
+	r.DrawImage(variant, &g.rotationOp)
+}
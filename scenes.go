@@ -0,0 +1,429 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Scene is the behavior of the game in a single mode.  Adding a mode only needs a new scene, not changes to Update and Draw
+type Scene interface {
+	// Enter is called when the game switches to the scene's mode
+	Enter(g *Game)
+	// Exit is called when the game switches away from the scene's mode
+	Exit(g *Game)
+	// Update updates the game for a frame
+	Update(g *Game)
+	// Draw draws the game for a frame
+	Draw(g *Game, r Renderer)
+}
+
+// scenes are the scenes of every mode
+var scenes = map[Mode]Scene{
+	ModeTitle:       TitleScene{},
+	ModeGame:        PlayScene{},
+	ModePause:       PauseScene{},
+	ModeGameOver:    GameOverScene{},
+	ModeKillCam:     KillCamScene{},
+	ModeSettings:    SettingsScene{},
+	ModeLeaderboard: LeaderboardScene{},
+	ModeWhatsNew:    WhatsNewScene{},
+	ModeReplay:      ReplayScene{},
+	ModeShop:        ShopScene{},
+}
+
+// scene returns the scene of the current mode
+func (g *Game) scene() Scene {
+	return scenes[g.mode]
+}
+
+// switchScene exits the scene of a mode and enters the scene of the current mode, if the mode has changed from it
+func (g *Game) switchScene(previous Mode) {
+	if g.mode == previous {
+		return
+	}
+	scenes[previous].Exit(g)
+	g.scene().Enter(g)
+}
+
+// drawScreenTexts draws the centered title and text lines of a screen
+func (g *Game) drawScreenTexts(r Renderer, titleTexts, texts []string) {
+	theme := g.theme()
+	for i, l := range titleTexts {
+		x := (screenWidth - len(l)/2*g.ui(titleFontSize)) / 2
+		r.DrawText(l, g.fonts().Title, x, screenHeight/4+(i+4)*g.ui(titleFontSize), theme.Text())
+	}
+	for i, l := range texts {
+		x := (screenWidth - len(l)/2*g.ui(fontSize)) / 2
+		r.DrawText(l, g.fonts().Normal, x, screenHeight/4+(i+4)*g.ui(fontSize), theme.Text())
+	}
+}
+
+// TitleScene is the title screen, with the title preview running behind it
+type TitleScene struct{}
+
+// Enter does nothing, the run is reset when the last one ends
+func (TitleScene) Enter(g *Game) {}
+
+// Exit does nothing
+func (TitleScene) Exit(g *Game) {}
+
+// Update runs the title preview and handles the title screen options and launch
+func (TitleScene) Update(g *Game) {
+	g.updateTitlePreview()
+	if g.updateLoadoutNaming() {
+		return
+	}
+	g.updateLoadout()
+	g.updateTheme()
+	g.updateVariant()
+	g.updateDifficulty()
+	g.updateBiome()
+	g.updatePathAssistOption()
+	g.updateSettingsShortcut()
+	g.updateLeaderboardShortcut()
+	g.updateShopShortcut()
+	g.updateLaunch()
+}
+
+// Draw draws the title, the player's progress, the launch meter and the hints for each option
+func (TitleScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawTitlePreview(r)
+	g.drawLaunchMeter(r)
+	g.drawThemeHint(r)
+	g.drawVariantHint(r)
+	g.drawDifficultyHint(r)
+	g.drawBiomeHint(r)
+	g.drawPathAssistHint(r)
+	g.drawSettingsHint(r)
+	g.drawLeaderboardHint(r)
+	g.drawLoadoutHint(r)
+	g.drawShopHint(r)
+	g.drawScreenTexts(r, []string{"GALACTIC ASTEROID BELT"}, []string{
+		"", "", "", "",
+		fmt.Sprintf("STAR BANK: %d", g.profile.Wallet.Stars),
+		fmt.Sprintf("BEST: %d M", g.profile.BestDistance),
+		"",
+		fmt.Sprintf("HOLD %s TO CHARGE LAUNCH", controlSchemeKeyNames[g.settings.ControlScheme]),
+	})
+}
+
+// PlayScene is a run being played
+type PlayScene struct{}
+
+// Enter does nothing, the run is set up when it launches
+func (PlayScene) Enter(g *Game) {}
+
+// Exit stops the thrust sound, which only plays during a run, and lets go of the audio if the run was being held
+func (PlayScene) Exit(g *Game) {
+	g.audio.setThrusting(false)
+	g.holdAudio(false)
+}
+
+// Update moves the world on by a frame, unless the quick menu, pausing or frame stepping holds the run.  The audio is held
+// along with the run, the pause scene holding it itself
+func (PlayScene) Update(g *Game) {
+	if g.updateQuickMenu() || g.updatePause() || g.updateCheats() {
+		g.holdAudio(g.mode == ModeGame)
+		return
+	}
+	// Stepping a frame doesn't let the audio go, it would only play in blips
+	g.holdAudio(g.cheats.frameStepping)
+
+	g.advance()
+	g.updateSegments()
+	g.updateStage()
+
+	g.updatePowerUps()
+	g.updateMagnet()
+	g.shipMovement()
+	g.recordGhost()
+	g.checkShieldOn()
+
+	g.updateEntities()
+	g.updateWater()
+	g.updateRings()
+	g.updateDisguises()
+	g.updateStarShower()
+	g.updateBoss()
+	g.updateUFOs()
+	g.updateHomingAsteroids()
+	g.updateOscillatingSpires()
+	g.updateNemesis()
+	g.updateGates()
+	g.updateCurrents()
+	g.updateChaos()
+	g.updateAnnouncer()
+	g.updateLaser()
+	g.updateAsteroidPaths()
+	g.updateCamera()
+	g.updateShake()
+	g.updatePursuitWall()
+
+	g.checkCollisions()
+	g.updateTethers()
+	g.checkTetherPasses()
+	g.applyInvulnerability()
+	g.updateDirector()
+
+	g.spawnHazards()
+	g.trackPeaks()
+	g.updateExplosions()
+	g.updateGraveyard()
+	g.updateFloatingTexts()
+
+	g.recordKillCamFrame()
+
+	g.frameCount++
+
+	if g.mode == ModeGameOver {
+		x, y := spriteCenter(g.ship)
+		g.publish(&Event{Kind: EventShipDied, X: x, Y: y})
+		g.endRun()
+	}
+}
+
+// Draw draws the world and the HUD
+func (PlayScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawWorld(r)
+	g.drawFloatingTexts(r)
+	if g.settings.HUD {
+		g.drawScore(r)
+		g.drawSplitMarker(r)
+		g.drawMagnetMeter(r)
+		g.drawHeatMeter(r)
+		g.drawPursuitGap(r)
+		g.drawBossMeter(r)
+	}
+	g.drawStarShowerBanner(r)
+	g.drawChaosBanner(r)
+	g.drawStageBanner(r)
+	g.drawAchievementBanner(r)
+	g.drawChaosTelegraphs(r)
+	g.drawCheats(r)
+	g.drawResumeCountdown(r)
+	g.drawQuickMenu(r)
+}
+
+// PauseScene is a run on hold until it is resumed
+type PauseScene struct{}
+
+// Enter holds the music and sounds where they are.  The paused scene is captured the first time it is drawn
+func (PauseScene) Enter(g *Game) {
+	g.audio.suspend()
+}
+
+// Exit carries on the music and sounds in time with the run, and disposes of the captured paused scene
+func (PauseScene) Exit(g *Game) {
+	g.audio.resume()
+	g.releasePauseCapture()
+}
+
+// Update resumes the run when asked
+func (PauseScene) Update(g *Game) {
+	g.updatePause()
+}
+
+// Draw draws the frozen world blurred behind the pause text.  The world is captured once, as it doesn't change while paused
+func (PauseScene) Draw(g *Game, r Renderer) {
+	if g.pauseCapture != nil {
+		r.DrawBlurred(g.pauseCapture, pauseBlurSpread)
+	} else {
+		g.drawBackground(r)
+		g.drawWorld(r)
+	}
+	if g.settings.HUD {
+		g.drawScore(r)
+	}
+	g.drawPauseShade(r)
+	g.drawScreenTexts(r, []string{"PAUSED"}, []string{"", "", "PRESS 'P' OR ESCAPE KEY TO RESUME"})
+}
+
+// GameOverScene is the game over screen, counting up the summary of the run that just ended
+type GameOverScene struct{}
+
+// Enter does nothing, the run is summarized when it ends
+func (GameOverScene) Enter(g *Game) {}
+
+// Exit does nothing
+func (GameOverScene) Exit(g *Game) {}
+
+// Update counts up the summary and handles restarting, the replay and the share card
+func (GameOverScene) Update(g *Game) {
+	if g.summary.update(g.input) {
+		g.audio.play(soundTick)
+	}
+	g.updateFireworks()
+	g.updateShake()
+	g.updateShareCard()
+	if g.input.IsKeyJustPressed(ebiten.KeyR) || g.isGamepadJustPressed(gamepadButtonStart) || (g.summary.isFinished() && g.isGamepadJustPressed(gamepadButtonA)) {
+		g.resetGame()
+		g.mode = ModeTitle
+	} else if g.input.IsKeyJustPressed(ebiten.KeyC) {
+		g.mode = ModeKillCam
+	}
+}
+
+// Draw draws the world where the run ended, the fireworks and the summary
+func (GameOverScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawWorld(r)
+	g.drawFireworks(r)
+	texts := append([]string{"", "", "", "", "", ""}, g.summary.texts()...)
+	if g.summary.isFinished() {
+		texts = append(texts, g.assistedLabel(), "PRESS 'R' KEY TO RESTART", "PRESS 'C' KEY TO WATCH REPLAY", g.shareCardPrompt())
+	}
+	g.drawScreenTexts(r, []string{"GAME OVER!"}, texts)
+}
+
+// KillCamScene replays the final seconds of the run that just ended
+type KillCamScene struct{}
+
+// Enter starts the replay from the beginning
+func (KillCamScene) Enter(g *Game) {
+	g.killCam.startPlayback()
+}
+
+// Exit does nothing
+func (KillCamScene) Exit(g *Game) {}
+
+// Update advances the replay
+func (KillCamScene) Update(g *Game) {
+	g.updateKillCam()
+}
+
+// Draw draws the replay and its controls
+func (KillCamScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawKillCam(r)
+	g.drawKillCamText(r)
+}
+
+// ReplayScene plays back a saved replay, reading it from the file as it goes
+type ReplayScene struct{}
+
+// Enter does nothing, the replay is opened before switching to the scene
+func (ReplayScene) Enter(g *Game) {}
+
+// Exit closes the replay
+func (ReplayScene) Exit(g *Game) {
+	g.stopReplay()
+}
+
+// Update reads the next frame of the replay
+func (ReplayScene) Update(g *Game) {
+	g.updateReplay()
+}
+
+// Draw draws the current frame of the replay
+func (ReplayScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawReplay(r)
+}
+
+// SettingsScene is the settings screen, with the title preview running behind it
+type SettingsScene struct{}
+
+// Enter opens the settings on the first tab with the first option selected
+func (SettingsScene) Enter(g *Game) {
+	g.settingCategory = categoryAudio
+	g.settingsSearch = ""
+	g.settingSelected = settingVolume
+	g.isEditingSetting = false
+}
+
+// Exit does nothing, each setting is saved as it changes
+func (SettingsScene) Exit(g *Game) {}
+
+// Update runs the title preview and changes the settings
+func (SettingsScene) Update(g *Game) {
+	g.updateTitlePreview()
+	g.updateSettings()
+}
+
+// Draw draws the settings of the current tab or search
+func (SettingsScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawTitlePreview(r)
+	g.drawScreenTexts(r, []string{"SETTINGS"}, g.settingsTexts())
+}
+
+// LeaderboardScene is the leaderboard screen, with the title preview running behind it
+type LeaderboardScene struct{}
+
+// Enter starts fetching the top entries
+func (LeaderboardScene) Enter(g *Game) {
+	g.leaderboard.fetch()
+}
+
+// Exit does nothing
+func (LeaderboardScene) Exit(g *Game) {}
+
+// Update runs the title preview and picks up the fetched entries
+func (LeaderboardScene) Update(g *Game) {
+	g.updateTitlePreview()
+	g.updateLeaderboard()
+}
+
+// Draw draws the top entries
+func (LeaderboardScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawTitlePreview(r)
+	g.drawScreenTexts(r, []string{"LEADERBOARD"}, g.leaderboardTexts())
+}
+
+// ShopScene is the shop, with the title preview running behind it
+type ShopScene struct{}
+
+// Enter opens the shop with the first item selected
+func (ShopScene) Enter(g *Game) {
+	g.shopSelected = 0
+	g.shopNotice = ""
+}
+
+// Exit does nothing, the profile is saved as each item is bought or equipped
+func (ShopScene) Exit(g *Game) {}
+
+// Update runs the title preview and buys or equips items
+func (ShopScene) Update(g *Game) {
+	g.updateTitlePreview()
+	g.updateShop()
+}
+
+// Draw draws the items for sale and the coins there are to spend
+func (ShopScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawTitlePreview(r)
+	g.drawScreenTexts(r, []string{"SHOP"}, g.shopTexts())
+}
+
+// WhatsNewScene lists the changes in this version, shown once after an update
+type WhatsNewScene struct{}
+
+// Enter does nothing
+func (WhatsNewScene) Enter(g *Game) {}
+
+// Exit does nothing, the version is remembered as seen when the screen is dismissed
+func (WhatsNewScene) Exit(g *Game) {}
+
+// Update dismisses the screen when asked
+func (WhatsNewScene) Update(g *Game) {
+	if g.input.IsKeyJustPressed(ebiten.KeySpace) || g.isGamepadJustPressed(gamepadButtonA) {
+		g.dismissWhatsNew()
+	}
+}
+
+// Draw draws the changes in this version
+func (WhatsNewScene) Draw(g *Game, r Renderer) {
+	g.drawBackground(r)
+	g.drawWorld(r)
+	texts := []string{"", "", ""}
+	if entry, ok := currentChangelogEntry(); ok {
+		for _, change := range entry.Changes {
+			texts = append(texts, "- "+change)
+		}
+	}
+	texts = append(texts, "", "", "PRESS SPACE KEY TO CONTINUE")
+	g.drawScreenTexts(r, []string{fmt.Sprintf("WHAT'S NEW IN V%s", gameVersion)}, texts)
+}
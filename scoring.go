@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"github.com/llrowat/spriteutils"
+	"log"
+	"math"
+)
+
+const (
+	// floatingTextFrames is how many frames floating score text is shown for
+	floatingTextFrames = 60
+	// floatingTextRise is how far floating score text rises every frame
+	floatingTextRise = 0.75
+)
+
+// destructionCause represents what destroyed an asteroid
+type destructionCause int
+
+const (
+	// destroyedByTerrain represents an asteroid destroyed by hitting the ground or a spire
+	destroyedByTerrain destructionCause = iota
+	// destroyedByShield represents an asteroid destroyed by the ship's shield
+	destroyedByShield
+	// destroyedByLaser represents an asteroid destroyed by a laser projectile
+	destroyedByLaser
+)
+
+// destructionEvent describes an asteroid being destroyed, carrying what is needed to score it
+type destructionEvent struct {
+	// asteroid is the destroyed asteroid
+	asteroid *spriteutils.Sprite
+	// cause is what destroyed the asteroid
+	cause destructionCause
+	// sizeTier is how big the asteroid was, 1 being the smallest
+	sizeTier int
+	// relativeSpeed is how fast the asteroid was moving relative to the ship
+	relativeSpeed float64
+}
+
+// floatingText is a short piece of text that rises and fades from where something happened, such as the score for a kill
+type floatingText struct {
+	// text is the text shown
+	text string
+	// x is the horizontal world position of the text
+	x float64
+	// y is the vertical world position of the text
+	y float64
+	// frames is the number of frames the text has been shown for
+	frames int
+}
+
+// asteroidSizeTier returns how big an asteroid is, 1 for the small ring asteroids and fragments as small as them, 2
+// for the big ones and bigger fragments, and 3 for rocks bigger than the big asteroids
+func (g *Game) asteroidSizeTier(asteroid *spriteutils.Sprite) int {
+	width, _ := asteroid.Image.Size()
+	if smallWidth, _ := g.assets.Image(imageSmallAsteroid).Size(); width <= smallWidth {
+		return 1
+	}
+	for _, key := range asteroidImageKeys {
+		if bigWidth, _ := g.stageImage(key).Size(); width <= bigWidth {
+			return 2
+		}
+	}
+	return 3
+}
+
+// destroyAsteroid replaces an asteroid with an explosion and publishes the destruction for the sound, effects and scoring
+func (g *Game) destroyAsteroid(asteroid *spriteutils.Sprite, cause destructionCause) {
+	g.asteroidExplosions = append(g.asteroidExplosions, g.createAsteroidExplosion(asteroid))
+
+	// Asteroids move with the world, so their speed relative to the ship is their own velocity less the ship's vertical movement
+	x, y := spriteCenter(asteroid)
+	g.publish(&Event{Kind: EventAsteroidDestroyed, X: x, Y: y, Destruction: destructionEvent{
+		asteroid:      asteroid,
+		cause:         cause,
+		sizeTier:      g.asteroidSizeTier(asteroid),
+		relativeSpeed: math.Hypot(asteroid.XVelocity, asteroid.YVelocity-g.ship.YVelocity),
+	}})
+	g.destroyedAsteroids = append(g.destroyedAsteroids, asteroid)
+}
+
+// onAsteroidDestroyed scores a destroyed asteroid with the points and bonus distance the rules give for it.  Shield kills
+// also slow the ship down in pursuit mode
+func (g *Game) onAsteroidDestroyed(event destructionEvent) {
+	if event.cause == destroyedByShield {
+		g.slowPursuit()
+	}
+
+	points, distance, err := rules.killScore(event)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	x, y := spriteCenter(event.asteroid)
+	if points != 0 {
+		g.bonusScore += points
+		g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d", points), x: x, y: y})
+	}
+	if distance != 0 {
+		g.distanceTravelled += distance
+		g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d M", distance), x: x, y: y})
+	}
+}
+
+// updateFloatingTexts moves floating text up and along with the world, destroying text that has been shown long enough
+func (g *Game) updateFloatingTexts() {
+	temp := g.floatingTexts[:0]
+	for _, text := range g.floatingTexts {
+		text.x -= g.speed
+		text.y -= floatingTextRise
+		text.frames++
+
+		if text.frames < floatingTextFrames {
+			temp = append(temp, text)
+		}
+	}
+	g.floatingTexts = temp
+}
+
+// drawFloatingTexts draws floating text where it is in the world, fading as it rises
+func (g *Game) drawFloatingTexts(r Renderer) {
+	theme := g.theme()
+	camera := g.camera()
+	for _, text := range g.floatingTexts {
+		x, y := camera.ToScreen(text.x, text.y)
+		width := len(text.text) * g.ui(smallFontSize) / 2
+		r.DrawText(text.text, g.fonts().Small, int(x)-width/2, int(y), withOpacity(theme.AccentColor, theme.Opacity*(1-float64(text.frames)/floatingTextFrames)))
+	}
+}
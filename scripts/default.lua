@@ -0,0 +1,47 @@
+-- The default rules of Galactic Asteroid Belt.  Copy this file to mod.lua next to your profile to change them.
+-- Runs played with a mod aren't submitted to the leaderboard.  Replays and ghosts rely on runs playing out the same
+-- way every time, so keep the rules free of math.random.
+
+-- power_ups are the pickups that spawn during a run, in the order they are checked.
+--   name           shown to the player
+--   image          the key of the image the pickup is drawn with: star, coin, freezePickup or growPickup
+--   effect         what collecting it does: boost (banks the star, speeds up and shields the ship), coin (banks a coin
+--                  to spend in the shop), freeze (freezes every asteroid), grow (enlarges the ship) or score (adds
+--                  points to the score)
+--   points         the points a score effect adds
+--   first_spawn    the distance the first pickup spawns at
+--   spawn_interval the distance between pickups
+--   duration       how many frames the effect lasts, 0 for effects that never wear off.  A boost lasts as long as the
+--                  difficulty allows
+--   disguise       the key of the image the pickup is drawn with until the ship gets within reveal_range
+-- Exactly one power-up must boost, as stars are what the star bank, magnet and star showers are built on.
+power_ups = {
+  {name = "STAR", image = "star", effect = "boost", first_spawn = 50, spawn_interval = 2000, duration = 300},
+  {name = "COIN", image = "coin", effect = "coin", first_spawn = 400, spawn_interval = 800, duration = 0},
+  {name = "FREEZE", image = "freezePickup", effect = "freeze", first_spawn = 3000, spawn_interval = 3000, duration = 240},
+  {
+    name = "GROW", image = "growPickup", effect = "grow", first_spawn = 1500, spawn_interval = 2500, duration = 300,
+    disguise = "star", reveal_range = 180,
+  },
+}
+
+-- spawn is called for every spire, asteroid, pickup and ring the game decides to spawn, before it enters the world.
+-- s.kind is "spire", "asteroid", "homing", "nemesis", "pickup", "ring", "twins", "gate" or "ufo", and s.power_up is the
+-- name of a pickup's power-up.  Change s.x, s.y, s.x_velocity or s.y_velocity to move it, and return false to cancel it.
+-- To spawn more alongside it, set s.extra to a list of spawns with the same fields, plus an optional image key for
+-- spires, asteroids, homing asteroids, the nemesis, rings, twins, the top spire of gates and UFOs.
+function spawn(s)
+  return true
+end
+
+-- kill_score returns the points and the bonus distance for destroying an asteroid.  cause is "terrain", "shield" or
+-- "laser", size is 1 for the small ring asteroids, 2 for the big ones and 3 for the large rocks, and speed is how fast
+-- the asteroid was moving relative to the ship.
+function kill_score(cause, size, speed)
+  if cause == "shield" then
+    return math.floor(5 * size * speed), 0
+  elseif cause == "laser" then
+    return 0, 50
+  end
+  return 0, 0
+end
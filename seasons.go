@@ -0,0 +1,203 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"time"
+)
+
+const (
+	// seasonAuto is the season setting that follows the calendar
+	seasonAuto = ""
+	// seasonOff is the season setting that leaves seasonal content out all year
+	seasonOff = "OFF"
+)
+
+// Season represents themed content shown during a window of the calendar: skins for the asteroids and stars, and its
+// own take on the background music
+type Season struct {
+	// Name is the name of the season, shown to the player and saved in the settings when forced on
+	Name string
+	// start and end are the first and last days of the season, the year ignored.  Seasons ending before they start run over the new year
+	start, end time.Time
+	// prefix is put in front of the keys of the asteroid and star images to get the season's skins of them
+	prefix string
+	// asteroidTint scales the red, green and blue of the asteroid skins
+	asteroidTint [3]float64
+	// starTint scales the red, green and blue of the star skin
+	starTint [3]float64
+	// musicRoots are the root notes of each bar of the season's background music, in Hz
+	musicRoots [musicBars]float64
+	// skins are the season's skins of the images sprites are drawn with, by the image they replace
+	skins map[*ebiten.Image]*ebiten.Image
+	// musicBaseLayer and musicIntenseLayer are the season's background music, generated the first time the season is used
+	musicBaseLayer, musicIntenseLayer []byte
+}
+
+// seasons are every season, in the order the setting cycles through them
+var seasons = []*Season{
+	{
+		Name:         "HALLOWEEN",
+		start:        seasonDay(time.October, 15),
+		end:          seasonDay(time.November, 1),
+		prefix:       "halloween-",
+		asteroidTint: [3]float64{1, 0.55, 0.1},
+		starTint:     [3]float64{0.5, 1, 0.5},
+		musicRoots:   [musicBars]float64{110, 116.54, 103.83, 98},
+	},
+	{
+		Name:         "HOLIDAYS",
+		start:        seasonDay(time.December, 15),
+		end:          seasonDay(time.January, 2),
+		prefix:       "holidays-",
+		asteroidTint: [3]float64{1.6, 1.7, 1.9},
+		starTint:     [3]float64{1, 0.3, 0.3},
+		musicRoots:   [musicBars]float64{130.81, 174.61, 196, 130.81},
+	},
+}
+
+// seasonDay returns a day of the year, for comparing against dates regardless of their year
+func seasonDay(month time.Month, day int) time.Time {
+	return time.Date(0, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// isActive returns whether a date falls within the season
+func (s *Season) isActive(date time.Time) bool {
+	day := seasonDay(date.Month(), date.Day())
+	if s.end.Before(s.start) {
+		return !day.Before(s.start) || !day.After(s.end)
+	}
+	return !day.Before(s.start) && !day.After(s.end)
+}
+
+// loadSeasonImages creates each season's skins of the asteroid and star images
+func (a *Assets) loadSeasonImages() error {
+	for _, season := range seasons {
+		for _, key := range asteroidImageKeys {
+			op := &ebiten.DrawImageOptions{}
+			op.ColorM.Scale(season.asteroidTint[0], season.asteroidTint[1], season.asteroidTint[2], 1)
+			if err := a.deriveImage(season.prefix+key, key, 1, op); err != nil {
+				return err
+			}
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.ColorM.Scale(season.starTint[0], season.starTint[1], season.starTint[2], 1)
+		if err := a.deriveImage(season.prefix+imageStar, imageStar, 1, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeSeasons matches each season's skins to the images they replace.  An asteroid skin replaces every stage's
+// copy of the asteroid at every size, and the fragments it splits into
+func initializeSeasons(assets *Assets) {
+	for _, season := range seasons {
+		season.skins = map[*ebiten.Image]*ebiten.Image{
+			assets.Image(imageStar): assets.Image(season.prefix + imageStar),
+		}
+		for _, key := range asteroidImageKeys {
+			for _, size := range asteroidSizes {
+				skin := scaledImage(assets.Image(season.prefix+key), size.scale)
+				skinFragments := fragmentImages(skin)
+				for _, stage := range stages {
+					image := scaledImage(assets.Image(stage.prefix+key), size.scale)
+					season.skins[image] = skin
+					for i, fragment := range fragmentImages(image) {
+						if i < len(skinFragments) {
+							season.skins[fragment] = skinFragments[i]
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// music returns the season's background music, generating it the first time
+func (s *Season) music() ([]byte, []byte) {
+	if s.musicBaseLayer == nil {
+		s.musicBaseLayer, s.musicIntenseLayer = synthesizeMusic(s.musicRoots)
+	}
+	return s.musicBaseLayer, s.musicIntenseLayer
+}
+
+// activeSeason returns the season whose content is shown, from the setting or else the calendar, or nil if there isn't one
+func (g *Game) activeSeason() *Season {
+	for _, season := range seasons {
+		if g.settings.Season == season.Name {
+			return season
+		}
+	}
+	if g.settings.Season == seasonOff {
+		return nil
+	}
+
+	now := time.Now()
+	for _, season := range seasons {
+		if season.isActive(now) {
+			return season
+		}
+	}
+	return nil
+}
+
+// applySeason switches the skins and background music to those of the active season, or back to the usual ones.
+// Nothing changes if the season is already in use
+func (g *Game) applySeason() {
+	season := g.activeSeason()
+	if season == g.season {
+		return
+	}
+	g.season = season
+
+	if season == nil {
+		g.audio.setMusic(musicBaseLayer, musicIntenseLayer)
+	} else {
+		g.audio.setMusic(season.music())
+	}
+}
+
+// stepSeason cycles the season setting through following the calendar, off and forcing on each season
+func (g *Game) stepSeason(step int) {
+	options := []string{seasonAuto, seasonOff}
+	for _, season := range seasons {
+		options = append(options, season.Name)
+	}
+
+	current := 0
+	for i, option := range options {
+		if option == g.settings.Season {
+			current = i
+		}
+	}
+	g.settings.Season = options[(current+step+len(options))%len(options)]
+	g.applySeason()
+}
+
+// seasonSettingText returns how the season setting is shown to the player, naming the season the calendar picks when following it
+func (g *Game) seasonSettingText() string {
+	if g.settings.Season != seasonAuto {
+		return g.settings.Season
+	}
+	if season := g.activeSeason(); season != nil {
+		return "AUTO (" + season.Name + ")"
+	}
+	return "AUTO"
+}
+
+// skinned returns a sprite drawn with the active season's skin of its image, or the sprite itself if there is no skin
+// for it.  The skinned sprite is only valid until the next call
+func (g *Game) skinned(sprite *spriteutils.Sprite) *spriteutils.Sprite {
+	if g.season == nil {
+		return sprite
+	}
+	skin, ok := g.season.skins[sprite.Image]
+	if !ok {
+		return sprite
+	}
+	g.skinnedSprite = *sprite
+	g.skinnedSprite.Image = skin
+	return &g.skinnedSprite
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// segmentLength is the distance covered by each segment of a run
+	segmentLength = 1000
+	// splitMarkerFrames is how many frames the split marker is shown for after beating a segment best
+	splitMarkerFrames = 120
+	// splitMarkerSlideFrames is how many frames the split marker takes to slide in or out
+	splitMarkerSlideFrames = 15
+)
+
+// updateSegments checks whether the current segment has been completed, recording a new segment best if it was beaten
+func (g *Game) updateSegments() {
+	if g.splitMarker != nil {
+		g.splitMarker.Update()
+		if g.splitMarker.IsFinished() {
+			g.splitMarker = nil
+		}
+	}
+
+	if g.distanceTravelled < (g.segment+1)*segmentLength {
+		return
+	}
+
+	frames := g.frameCount - g.segmentStartFrame
+	for len(g.profile.SegmentBests) <= g.segment {
+		g.profile.SegmentBests = append(g.profile.SegmentBests, 0)
+	}
+
+	best := g.profile.SegmentBests[g.segment]
+	if !g.isCheated && (best == 0 || frames < best) {
+		g.profile.SegmentBests[g.segment] = frames
+
+		// Only celebrate beating an existing best, not the first time a segment is reached
+		if best != 0 {
+			g.splitMarker = newSplitMarkerSlide()
+			g.splitMarkerSegment = g.segment
+			g.splitMarkerGain = time.Duration(best-frames) * time.Second / 60
+		}
+	}
+
+	g.segment++
+	g.segmentStartFrame = g.frameCount
+}
+
+// newSplitMarkerSlide creates the slide of the split marker banner, in from the right edge of the screen, held, then back out.
+// The value is how far off the right edge of the screen the banner is, from 0 to 1
+func newSplitMarkerSlide() *TweenSequence {
+	return NewTweenSequence(
+		NewTween(1, 0, splitMarkerSlideFrames, EaseOutQuad),
+		NewWait(0, splitMarkerFrames-splitMarkerSlideFrames*2),
+		NewTween(0, 1, splitMarkerSlideFrames, EaseInQuad),
+	)
+}
+
+// drawSplitMarker draws the golden split marker below the score after a segment best is beaten
+func (g *Game) drawSplitMarker(r Renderer) {
+	if g.splitMarker == nil {
+		return
+	}
+
+	splitStr := fmt.Sprintf("%d M SEGMENT BEST -%.2fs", (g.splitMarkerSegment+1)*segmentLength, g.splitMarkerGain.Seconds())
+	width := len(splitStr) * g.ui(smallFontSize) / 2
+	// The marker slides in from whichever edge the HUD is against
+	slide := int(g.splitMarker.Value() * float64(width))
+	if g.isHUDMirrored() {
+		slide = -slide
+	}
+	x := g.hudX(width, 0) + slide
+	theme := g.theme()
+	r.DrawText(splitStr, g.fonts().Small, x, g.ui(fontSize)+g.ui(smallFontSize)*4, theme.Accent())
+}
@@ -0,0 +1,665 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	// settingsFileName is the name of the settings file, kept apart from the signed save file
+	settingsFileName = "settings.json"
+	// settingsKey is the key that opens the settings screen from the title screen
+	settingsKey = ebiten.KeyS
+	// gamepadButtonB is the B button on a standard controller, it goes back
+	gamepadButtonB = ebiten.GamepadButton1
+	// gamepadButtonBack is the Back button on a standard controller, it opens the settings screen
+	gamepadButtonBack = ebiten.GamepadButton6
+	// gamepadStickThreshold is how far a stick has to be pushed to move through the settings
+	gamepadStickThreshold = 0.5
+	// gamepadButtonLB is the left shoulder button on a standard controller, it moves to the previous settings tab
+	gamepadButtonLB = ebiten.GamepadButton4
+	// gamepadButtonRB is the right shoulder button on a standard controller, it moves to the next settings tab
+	gamepadButtonRB = ebiten.GamepadButton5
+	// volumeStep is how much the volume changes with each press
+	volumeStep = 0.1
+	// maxSettingsSearchLength is the longest the settings search can be
+	maxSettingsSearchLength = 20
+	// maxShownSettingTextLength is the most characters of a typed setting shown on the settings screen, the end being shown
+	// when it is longer
+	maxShownSettingTextLength = 32
+)
+
+// ControlScheme represents the keys used to thrust
+type ControlScheme int
+
+const (
+	// ControlSpace represents thrusting with Space or the left mouse button
+	ControlSpace ControlScheme = iota
+	// ControlArrows represents thrusting with the up arrow
+	ControlArrows
+	// ControlW represents thrusting with W
+	ControlW
+)
+
+// controlSchemeNames are the names of each control scheme shown to the player
+var controlSchemeNames = map[ControlScheme]string{
+	ControlSpace:  "SPACE / MOUSE",
+	ControlArrows: "UP ARROW",
+	ControlW:      "W KEY",
+}
+
+// controlSchemeKeyNames are the names of the key that thrusts in each control scheme, used in prompts
+var controlSchemeKeyNames = map[ControlScheme]string{
+	ControlSpace:  "SPACE KEY",
+	ControlArrows: "UP ARROW",
+	ControlW:      "W KEY",
+}
+
+// Settings represents the options chosen on the settings screen, persisted between launches
+type Settings struct {
+	// Volume is the volume of all sound, from 0 to 1
+	Volume float64 `json:"volume"`
+	// Fullscreen represents whether the game is played fullscreen
+	Fullscreen bool `json:"fullscreen"`
+	// ControlScheme is the keys used to thrust
+	ControlScheme ControlScheme `json:"controlScheme"`
+	// LeaderboardURL is the endpoint runs are submitted to and the leaderboard fetched from, empty for no leaderboard
+	LeaderboardURL string `json:"leaderboardURL,omitempty"`
+	// PlayerName is the name runs are submitted to the leaderboard under
+	PlayerName string `json:"playerName,omitempty"`
+	// Music represents whether the background music plays
+	Music bool `json:"music"`
+	// Sound represents whether the sound effects play
+	Sound bool `json:"sound"`
+	// ScreenShake represents whether the camera shakes for explosions and deaths
+	ScreenShake bool `json:"screenShake"`
+	// HUD represents whether the distance, score and meters are shown during a run
+	HUD bool `json:"hud"`
+	// Announcer represents whether the announcer calls out boosts, records and warnings
+	Announcer bool `json:"announcer"`
+	// AnnouncerVolume is the volume of the announcer from 0 to 1, on top of the overall volume
+	AnnouncerVolume float64 `json:"announcerVolume"`
+	// UIScale is the size of text and the HUD as a percentage, one of uiScales
+	UIScale int `json:"uiScale"`
+	// PerformanceMode represents whether the garbage collector is tuned and memory set aside up front to cut hitches on long runs
+	PerformanceMode bool `json:"performanceMode"`
+	// Theme is the name of the selected HUD theme, empty for the default
+	Theme string `json:"theme,omitempty"`
+	// PathAssist represents whether the predicted paths of newly spawned asteroids are shown
+	PathAssist bool `json:"pathAssist,omitempty"`
+	// PerfHUD represents whether the performance HUD is drawn
+	PerfHUD bool `json:"perfHUD,omitempty"`
+	// Season is the season whose content is shown: empty to follow the calendar, "OFF" for none, or a season's name to force it on
+	Season string `json:"season,omitempty"`
+	// Orientation is which way round the screen is laid out.  It is left out when landscape, so settings saved before it existed read back the same
+	Orientation Orientation `json:"orientation,omitempty"`
+	// MirroredHUD represents whether the HUD is laid out against the left of the screen, with warnings along the opposite edges
+	MirroredHUD bool `json:"mirroredHUD,omitempty"`
+}
+
+// settingOption represents the options on the settings screen, in the order they are listed
+type settingOption int
+
+const (
+	// settingVolume represents the volume option
+	settingVolume settingOption = iota
+	// settingMusic represents the background music toggle
+	settingMusic
+	// settingSound represents the sound effects toggle
+	settingSound
+	// settingAnnouncer represents the announcer toggle
+	settingAnnouncer
+	// settingAnnouncerVolume represents the announcer volume option
+	settingAnnouncerVolume
+	// settingFullscreen represents the fullscreen option
+	settingFullscreen
+	// settingScreenShake represents the screen shake toggle
+	settingScreenShake
+	// settingHUD represents the HUD toggle
+	settingHUD
+	// settingUIScale represents the UI scale option
+	settingUIScale
+	// settingPerformance represents the performance mode toggle
+	settingPerformance
+	// settingPerfHUD represents the performance HUD toggle
+	settingPerfHUD
+	// settingTheme represents the HUD theme option
+	settingTheme
+	// settingSeason represents the seasonal events option
+	settingSeason
+	// settingOrientation represents the screen orientation option
+	settingOrientation
+	// settingHUDSide represents the side of the screen the HUD is laid out against
+	settingHUDSide
+	// settingPathAssist represents the asteroid path assist toggle
+	settingPathAssist
+	// settingPlayerName represents the name runs are submitted to the leaderboard under
+	settingPlayerName
+	// settingLeaderboardURL represents the leaderboard endpoint
+	settingLeaderboardURL
+	// settingControls represents the control scheme option
+	settingControls
+	// settingBack represents going back to the title screen
+	settingBack
+	// settingOptionCount is the number of options on the settings screen
+	settingOptionCount
+)
+
+// settingCategory represents the tabs the options on the settings screen are grouped into
+type settingCategory int
+
+const (
+	// categoryAudio represents the tab of sound options
+	categoryAudio settingCategory = iota
+	// categoryVideo represents the tab of display options
+	categoryVideo
+	// categoryAccessibility represents the tab of options that make the game easier to see and follow
+	categoryAccessibility
+	// categoryGameplay represents the tab of options that change how runs play
+	categoryGameplay
+	// categoryControls represents the tab of input options
+	categoryControls
+	// settingCategoryCount is the number of tabs on the settings screen
+	settingCategoryCount
+)
+
+// settingCategoryNames are the names of each tab shown to the player
+var settingCategoryNames = map[settingCategory]string{
+	categoryAudio:         "AUDIO",
+	categoryVideo:         "VIDEO",
+	categoryAccessibility: "ACCESSIBILITY",
+	categoryGameplay:      "GAMEPLAY",
+	categoryControls:      "CONTROLS",
+}
+
+// settingOptionCategories are the tabs each option is listed on.  Going back is listed on every tab
+var settingOptionCategories = map[settingOption]settingCategory{
+	settingVolume:          categoryAudio,
+	settingMusic:           categoryAudio,
+	settingSound:           categoryAudio,
+	settingAnnouncer:       categoryAudio,
+	settingAnnouncerVolume: categoryAudio,
+	settingFullscreen:      categoryVideo,
+	settingScreenShake:     categoryAccessibility,
+	settingHUD:             categoryVideo,
+	settingUIScale:         categoryAccessibility,
+	settingPerformance:     categoryVideo,
+	settingPerfHUD:         categoryVideo,
+	settingTheme:           categoryAccessibility,
+	settingSeason:          categoryGameplay,
+	settingOrientation:     categoryVideo,
+	settingHUDSide:         categoryAccessibility,
+	settingPathAssist:      categoryGameplay,
+	settingPlayerName:      categoryGameplay,
+	settingLeaderboardURL:  categoryGameplay,
+	settingControls:        categoryControls,
+}
+
+// settingOptionNames are the names of each option shown to the player and matched by the search
+var settingOptionNames = map[settingOption]string{
+	settingVolume:          "VOLUME",
+	settingMusic:           "MUSIC",
+	settingSound:           "SOUND EFFECTS",
+	settingAnnouncer:       "ANNOUNCER",
+	settingAnnouncerVolume: "ANNOUNCER VOLUME",
+	settingFullscreen:      "FULLSCREEN",
+	settingScreenShake:     "SCREEN SHAKE",
+	settingHUD:             "HUD",
+	settingUIScale:         "UI SCALE",
+	settingPerformance:     "PERFORMANCE MODE",
+	settingPerfHUD:         "PERFORMANCE HUD",
+	settingTheme:           "HUD THEME",
+	settingSeason:          "SEASONAL EVENTS",
+	settingOrientation:     "ORIENTATION",
+	settingHUDSide:         "HUD SIDE",
+	settingPathAssist:      "ASTEROID PATH ASSIST",
+	settingPlayerName:      "PLAYER NAME",
+	settingLeaderboardURL:  "LEADERBOARD URL",
+	settingControls:        "CONTROLS",
+	settingBack:            "BACK",
+}
+
+// defaultSettings returns the settings used before any have been saved.  Debug builds show the performance HUD to begin with
+func defaultSettings() *Settings {
+	return &Settings{Volume: 1, Music: true, Sound: true, ScreenShake: true, HUD: true, Announcer: true, AnnouncerVolume: 1, UIScale: defaultUIScale, PerfHUD: debugBuild}
+}
+
+// settingsPath returns the location of the settings file
+func settingsPath() (string, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, settingsFileName), nil
+}
+
+// loadSettings reads the settings file, returning the default settings if none exists yet
+func loadSettings() (*Settings, error) {
+	settings := defaultSettings()
+
+	path, err := settingsPath()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, settings); err != nil {
+		return defaultSettings(), err
+	}
+	return settings, nil
+}
+
+// save writes the settings to the settings file, creating its directory if required
+func (s *Settings) save() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// apply applies the settings that belong to the window rather than the game
+func (s *Settings) apply() {
+	ebiten.SetFullscreen(s.Fullscreen)
+	s.applyPerformanceMode()
+}
+
+// isThrustPressed returns whether the thrust input of the chosen control scheme, or A on any gamepad, is held
+func (g *Game) isThrustPressed() bool {
+	if g.isGamepadPressed(gamepadButtonA) {
+		return true
+	}
+
+	switch g.settings.ControlScheme {
+	case ControlArrows:
+		return g.input.IsKeyPressed(ebiten.KeyUp)
+	case ControlW:
+		return g.input.IsKeyPressed(ebiten.KeyW)
+	default:
+		return g.input.IsKeyPressed(ebiten.KeySpace) || g.input.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	}
+}
+
+// isThrustJustPressed returns whether the thrust key of the chosen control scheme, or A on any gamepad, was just pressed
+func (g *Game) isThrustJustPressed() bool {
+	if g.isGamepadJustPressed(gamepadButtonA) {
+		return true
+	}
+
+	switch g.settings.ControlScheme {
+	case ControlArrows:
+		return g.input.IsKeyJustPressed(ebiten.KeyUp)
+	case ControlW:
+		return g.input.IsKeyJustPressed(ebiten.KeyW)
+	default:
+		return g.input.IsKeyJustPressed(ebiten.KeySpace)
+	}
+}
+
+// updateSettingsShortcut opens the settings screen when its key or the gamepad Back button is pressed on the title screen
+func (g *Game) updateSettingsShortcut() {
+	if g.input.IsKeyJustPressed(settingsKey) || g.isGamepadJustPressed(gamepadButtonBack) {
+		g.mode = ModeSettings
+	}
+}
+
+// gamepadStickJustPushed returns the direction the left stick of any gamepad was just pushed in, -1, 0 or 1 along each axis
+func (g *Game) gamepadStickJustPushed() (int, int) {
+	x, y := 0, 0
+	for _, id := range g.gamepadIDs {
+		if axis := g.input.GamepadAxis(id, 0); axis < -gamepadStickThreshold {
+			x = -1
+		} else if axis > gamepadStickThreshold {
+			x = 1
+		}
+		if axis := g.input.GamepadAxis(id, 1); axis < -gamepadStickThreshold {
+			y = -1
+		} else if axis > gamepadStickThreshold {
+			y = 1
+		}
+	}
+
+	// Only a change of direction counts, so holding the stick doesn't race through the options
+	pushedX, pushedY := 0, 0
+	if x != g.lastStickX {
+		pushedX = x
+	}
+	if y != g.lastStickY {
+		pushedY = y
+	}
+	g.lastStickX, g.lastStickY = x, y
+	return pushedX, pushedY
+}
+
+// visibleSettings returns the options listed on the settings screen, those matching the search or else those on the current tab
+func (g *Game) visibleSettings() []settingOption {
+	var options []settingOption
+	for option := settingOption(0); option < settingBack; option++ {
+		if g.settingsSearch != "" {
+			if strings.Contains(settingOptionNames[option], g.settingsSearch) {
+				options = append(options, option)
+			}
+		} else if settingOptionCategories[option] == g.settingCategory {
+			options = append(options, option)
+		}
+	}
+	return append(options, settingBack)
+}
+
+// updateSettingsSearch adds typed letters to the settings search and removes them with backspace, returning whether it changed
+func (g *Game) updateSettingsSearch() bool {
+	search := g.settingsSearch
+	for _, char := range g.input.InputChars() {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || char == ' ' {
+			search += strings.ToUpper(string(char))
+		}
+	}
+	if g.input.IsKeyJustPressed(ebiten.KeyBackspace) && search != "" {
+		search = search[:len(search)-1]
+	}
+	if len(search) > maxSettingsSearchLength {
+		search = search[:maxSettingsSearchLength]
+	}
+
+	changed := search != g.settingsSearch
+	g.settingsSearch = search
+	return changed
+}
+
+// isTypedSetting returns whether a setting is changed by typing rather than stepping through values
+func isTypedSetting(option settingOption) bool {
+	return option == settingPlayerName || option == settingLeaderboardURL
+}
+
+// typedSettingValue returns the saved value of a typed setting
+func (g *Game) typedSettingValue(option settingOption) string {
+	if option == settingPlayerName {
+		return g.settings.PlayerName
+	}
+	return g.settings.LeaderboardURL
+}
+
+// updateSettingInput takes the typed value of the selected setting until it is saved with Enter or given up on with Escape.
+// It returns whether a value is being typed, so the other settings keys and the search are left alone while it is
+func (g *Game) updateSettingInput() bool {
+	if !g.isEditingSetting {
+		return false
+	}
+
+	for _, char := range g.input.InputChars() {
+		switch {
+		case g.settingSelected == settingPlayerName && (unicode.IsLetter(char) || unicode.IsDigit(char) || char == ' '):
+			g.settingInput += strings.ToUpper(string(char))
+		case g.settingSelected == settingLeaderboardURL && unicode.IsPrint(char) && !unicode.IsSpace(char):
+			g.settingInput += string(char)
+		}
+	}
+	if g.input.IsKeyJustPressed(ebiten.KeyBackspace) && g.settingInput != "" {
+		g.settingInput = g.settingInput[:len(g.settingInput)-1]
+	}
+	maxLength := maxLeaderboardURLLength
+	if g.settingSelected == settingPlayerName {
+		maxLength = maxPlayerNameLength
+	}
+	if len(g.settingInput) > maxLength {
+		g.settingInput = g.settingInput[:maxLength]
+	}
+
+	switch {
+	case g.input.IsKeyJustPressed(ebiten.KeyEscape):
+		g.isEditingSetting = false
+	case g.input.IsKeyJustPressed(ebiten.KeyEnter):
+		g.isEditingSetting = false
+		value := strings.TrimSpace(g.settingInput)
+		if g.settingSelected == settingPlayerName {
+			g.settings.PlayerName = value
+		} else {
+			g.settings.LeaderboardURL = value
+			g.leaderboard = newLeaderboard(value)
+		}
+		g.applySettings()
+	}
+	return true
+}
+
+// updateSettings moves through the settings with the arrow keys or left stick and changes them, saving and applying each change.
+// Tab or the shoulder buttons move between tabs, and typing searches every tab, or fills in a typed setting once Enter
+// starts editing it
+func (g *Game) updateSettings() {
+	if g.updateSettingInput() {
+		return
+	}
+
+	stickX, stickY := g.gamepadStickJustPushed()
+	up := g.input.IsKeyJustPressed(ebiten.KeyUp) || stickY < 0
+	down := g.input.IsKeyJustPressed(ebiten.KeyDown) || stickY > 0
+	left := g.input.IsKeyJustPressed(ebiten.KeyLeft) || stickX < 0
+	right := g.input.IsKeyJustPressed(ebiten.KeyRight) || stickX > 0
+	confirm := g.input.IsKeyJustPressed(ebiten.KeyEnter) || g.isGamepadJustPressed(gamepadButtonA)
+	shift := g.input.IsKeyPressed(ebiten.KeyShift)
+	previousTab := (g.input.IsKeyJustPressed(ebiten.KeyTab) && shift) || g.isGamepadJustPressed(gamepadButtonLB)
+	nextTab := (g.input.IsKeyJustPressed(ebiten.KeyTab) && !shift) || g.isGamepadJustPressed(gamepadButtonRB)
+
+	// Escape clears the search before it leaves the settings
+	if g.input.IsKeyJustPressed(ebiten.KeyEscape) && g.settingsSearch != "" {
+		g.settingsSearch = ""
+	} else if g.input.IsKeyJustPressed(ebiten.KeyEscape) || g.isGamepadJustPressed(gamepadButtonB) || (confirm && g.settingSelected == settingBack) {
+		g.mode = ModeTitle
+		return
+	}
+
+	searchChanged := g.updateSettingsSearch()
+	if previousTab || nextTab {
+		step := settingCategory(1)
+		if previousTab {
+			step = settingCategoryCount - 1
+		}
+		g.settingCategory = (g.settingCategory + step) % settingCategoryCount
+		g.settingsSearch = ""
+	}
+
+	// The selection moves to the first listed option whenever the list changes under it
+	options := g.visibleSettings()
+	selected := 0
+	for i, option := range options {
+		if option == g.settingSelected {
+			selected = i
+		}
+	}
+	if previousTab || nextTab || searchChanged {
+		selected = 0
+	}
+	if up {
+		selected = (selected + len(options) - 1) % len(options)
+	} else if down {
+		selected = (selected + 1) % len(options)
+	}
+	g.settingSelected = options[selected]
+
+	step := 0
+	if left {
+		step = -1
+	} else if right || confirm {
+		step = 1
+	}
+	if step == 0 {
+		return
+	}
+
+	switch g.settingSelected {
+	case settingVolume:
+		g.settings.Volume = stepVolume(g.settings.Volume, step)
+	case settingMusic:
+		g.settings.Music = !g.settings.Music
+	case settingSound:
+		g.settings.Sound = !g.settings.Sound
+	case settingAnnouncer:
+		g.settings.Announcer = !g.settings.Announcer
+	case settingAnnouncerVolume:
+		g.settings.AnnouncerVolume = stepVolume(g.settings.AnnouncerVolume, step)
+	case settingFullscreen:
+		g.settings.Fullscreen = !g.settings.Fullscreen
+	case settingScreenShake:
+		g.settings.ScreenShake = !g.settings.ScreenShake
+	case settingHUD:
+		g.settings.HUD = !g.settings.HUD
+	case settingUIScale:
+		g.settings.UIScale = stepUIScale(g.uiScale(), step)
+	case settingPerformance:
+		g.settings.PerformanceMode = !g.settings.PerformanceMode
+	case settingPerfHUD:
+		g.settings.PerfHUD = !g.settings.PerfHUD
+	case settingTheme:
+		g.stepTheme(step)
+	case settingSeason:
+		g.stepSeason(step)
+	case settingOrientation:
+		g.stepOrientation(step)
+	case settingHUDSide:
+		g.settings.MirroredHUD = !g.settings.MirroredHUD
+	case settingPathAssist:
+		g.settings.PathAssist = !g.settings.PathAssist
+	case settingPlayerName, settingLeaderboardURL:
+		if confirm {
+			g.isEditingSetting = true
+			g.settingInput = g.typedSettingValue(g.settingSelected)
+		}
+		return
+	case settingControls:
+		count := ControlScheme(len(controlSchemeNames))
+		g.settings.ControlScheme = (g.settings.ControlScheme + ControlScheme(step) + count) % count
+	default:
+		return
+	}
+	g.applySettings()
+}
+
+// applySettings applies a change to the settings to the window and the audio, and saves them
+func (g *Game) applySettings() {
+	g.settings.apply()
+	g.audio.setVolume(g.settings.Volume)
+	g.audio.setMuted(!g.settings.Music, !g.settings.Sound)
+	g.audio.setAnnouncer(g.settings.Announcer, g.settings.AnnouncerVolume)
+	if err := g.settings.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// onOff returns how a setting that is on or off is shown to the player
+func onOff(isOn bool) string {
+	if isOn {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// stepVolume returns a volume moved up or down by volumeStep, kept from 0 to 1
+func stepVolume(volume float64, step int) float64 {
+	volume = math.Round((volume+float64(step)*volumeStep)*10) / 10
+	return math.Max(0, math.Min(1, volume))
+}
+
+// settingsTexts returns the lines of the settings screen, marking the current tab and the selected option
+func (g *Game) settingsTexts() []string {
+	values := map[settingOption]string{
+		settingVolume:          fmt.Sprintf("%d%%", int(math.Round(g.settings.Volume*100))),
+		settingMusic:           onOff(g.settings.Music),
+		settingSound:           onOff(g.settings.Sound),
+		settingAnnouncer:       onOff(g.settings.Announcer),
+		settingAnnouncerVolume: fmt.Sprintf("%d%%", int(math.Round(g.settings.AnnouncerVolume*100))),
+		settingFullscreen:      onOff(g.settings.Fullscreen),
+		settingScreenShake:     onOff(g.settings.ScreenShake),
+		settingHUD:             onOff(g.settings.HUD),
+		settingUIScale:         fmt.Sprintf("%d%%", g.uiScale()),
+		settingPerformance:     onOff(g.settings.PerformanceMode),
+		settingPerfHUD:         onOff(g.settings.PerfHUD),
+		settingTheme:           g.theme().Name,
+		settingSeason:          g.seasonSettingText(),
+		settingOrientation:     orientationNames[g.settings.Orientation],
+		settingHUDSide:         hudSideText(g.settings.MirroredHUD),
+		settingPathAssist:      onOff(g.settings.PathAssist),
+		settingPlayerName:      g.settings.PlayerName,
+		settingLeaderboardURL:  g.settings.LeaderboardURL,
+		settingControls:        controlSchemeNames[g.settings.ControlScheme],
+	}
+	if values[settingPlayerName] == "" {
+		values[settingPlayerName] = defaultPlayerName
+	}
+	if values[settingLeaderboardURL] == "" {
+		values[settingLeaderboardURL] = "OFF"
+	}
+	if g.isEditingSetting {
+		values[g.settingSelected] = g.settingInput + "_"
+	}
+	for option, value := range values {
+		if isTypedSetting(option) && len(value) > maxShownSettingTextLength {
+			values[option] = "..." + value[len(value)-maxShownSettingTextLength:]
+		}
+	}
+
+	// The tabs are replaced by the search while there is one, as it lists options from every tab
+	header := "SEARCH: " + g.settingsSearch
+	if g.settingsSearch == "" {
+		var tabs []string
+		for category := settingCategory(0); category < settingCategoryCount; category++ {
+			tab := settingCategoryNames[category]
+			if category == g.settingCategory {
+				tab = "[" + tab + "]"
+			}
+			tabs = append(tabs, tab)
+		}
+		header = strings.Join(tabs, "  ")
+	}
+
+	texts := []string{"", "", header, ""}
+	options := g.visibleSettings()
+	if len(options) == 1 {
+		texts = append(texts, "NO MATCHING SETTINGS")
+	}
+	for _, option := range options {
+		text := settingOptionNames[option]
+		if value, ok := values[option]; ok {
+			text += ": " + value
+		}
+		if option == g.settingSelected {
+			text = "> " + text + " <"
+		}
+		texts = append(texts, text)
+	}
+	if g.isEditingSetting {
+		return append(texts, "", "", "TYPE, THEN ENTER TO SAVE OR ESCAPE TO CANCEL")
+	}
+	return append(texts, "", "", "UP/DOWN TO CHOOSE, LEFT/RIGHT TO CHANGE, ENTER TO TYPE, ESCAPE TO GO BACK", "TAB OR LB/RB TO CHANGE TAB, TYPE TO SEARCH")
+}
+
+// drawSettingsHint draws how to open the settings screen at the bottom of the title screen
+func (g *Game) drawSettingsHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'S' KEY FOR SETTINGS"
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*5, theme.Text())
+}
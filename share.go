@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// shareCardKey is the key that saves a share card of the run on the game over screen
+	shareCardKey = ebiten.KeyI
+	// shareCardDirName is the directory under the profile directory that share cards are written to
+	shareCardDirName = "cards"
+	// shareCardWidth is the width of a share card
+	shareCardWidth = 480
+	// shareCardHeight is the height of a share card
+	shareCardHeight = 270
+	// shareCardMargin is the space around the edge of a share card
+	shareCardMargin = 20
+	// shareCardMapHeight is the height of the run map along the bottom of a share card
+	shareCardMapHeight = 60
+	// shareCardMapPoints is how many points the run map is drawn with
+	shareCardMapPoints = 110
+)
+
+// updateShareCard saves a share card of the run when its key is pressed on the game over screen
+func (g *Game) updateShareCard() {
+	if !g.input.IsKeyJustPressed(shareCardKey) || g.isShareCardSaved {
+		return
+	}
+
+	path, err := g.saveShareCard()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Printf("share card written to %s", path)
+	g.isShareCardSaved = true
+}
+
+// saveShareCard draws a share card of the run offscreen and writes it to a PNG in the profile directory, returning the path of the file
+func (g *Game) saveShareCard() (string, error) {
+	card, err := ebiten.NewImage(shareCardWidth, shareCardHeight, ebiten.FilterDefault)
+	if err != nil {
+		return "", err
+	}
+	defer card.Dispose()
+
+	var r ebitenRenderer
+	r.begin(card, PaletteNormal)
+	g.drawShareCard(&r)
+	r.end()
+
+	dir, err := profileDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, shareCardDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("card-%d.png", time.Now().Unix()))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return path, png.Encode(file, card)
+}
+
+// drawShareCard draws the distance, stars, date and ship of the run, with a map of the ship's path along the bottom
+func (g *Game) drawShareCard(r Renderer) {
+	theme := g.theme()
+	// The card is laid out at a fixed size, so it ignores the UI scale
+	fonts := g.assets.Fonts(theme.Font, defaultUIScale)
+
+	op := &Transform{}
+	width, height := g.assets.Image(imageBackground).Size()
+	op.Scale(shareCardWidth/float64(width), shareCardHeight/float64(height))
+	r.DrawImage(g.assets.Image(imageBackground), op)
+
+	op = &Transform{}
+	shipWidth, _ := g.assets.Image(imageShip).Size()
+	op.Translate(float64(shareCardWidth-shareCardMargin-shipWidth), shareCardMargin)
+	r.DrawImage(g.assets.Image(imageShip), op)
+
+	title := "GALACTIC ASTEROID BELT"
+	if g.isNewBest {
+		title += " - NEW BEST!"
+	}
+	r.DrawText(title, fonts.Small, shareCardMargin, shareCardMargin+smallFontSize, theme.Accent())
+	r.DrawText(fmt.Sprintf("%d M", g.distanceTravelled), fonts.Title, shareCardMargin, shareCardMargin+smallFontSize*2+titleFontSize, theme.Text())
+	r.DrawText(fmt.Sprintf("STARS: %d", g.starsCollected), fonts.Normal, shareCardMargin, shareCardMargin+smallFontSize*2+titleFontSize+fontSize*2, theme.Text())
+	r.DrawText(time.Now().Format("2006-01-02"), fonts.Small, shareCardMargin, shareCardMargin+smallFontSize*3+titleFontSize+fontSize*3, theme.Text())
+
+	// The map squashes the whole run into the width of the card, and the height of the screen into the height of the map
+	mapWidth := float64(shareCardWidth - shareCardMargin*2)
+	mapY := float64(shareCardHeight - shareCardMargin - shareCardMapHeight)
+	r.DrawRect(shareCardMargin, mapY, mapWidth, shareCardMapHeight, theme.Panel())
+	if len(g.ghostRecording) == 0 {
+		return
+	}
+	for i := 0; i < shareCardMapPoints; i++ {
+		y := g.ghostRecording[i*(len(g.ghostRecording)-1)/(shareCardMapPoints-1)]
+		x := shareCardMargin + mapWidth*float64(i)/(shareCardMapPoints-1)
+		r.DrawRect(x, mapY+float64(y)*shareCardMapHeight/float64(screenHeight), 2, 2, theme.Accent())
+	}
+}
+
+// shareCardPrompt returns the line shown on the game over screen explaining how to save a share card
+func (g *Game) shareCardPrompt() string {
+	if g.isShareCardSaved {
+		return "SHARE IMAGE SAVED"
+	}
+	return "PRESS 'I' KEY TO SAVE SHARE IMAGE"
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"log"
+	"math/rand"
+)
+
+const (
+	// shopKey is the key that opens the shop from the title screen
+	shopKey = ebiten.KeyH
+	// shopMagnetCapacitor is the name of the upgrade that recharges the magnet faster
+	shopMagnetCapacitor = "MAGNET CAPACITOR"
+	// shopHeatSink is the name of the upgrade that cools the engine faster
+	shopHeatSink = "HEAT SINK"
+	// shopShieldBattery is the name of the upgrade that makes star boosts last longer
+	shopShieldBattery = "SHIELD BATTERY"
+)
+
+// shopItemKind represents the types of item sold in the shop
+type shopItemKind int
+
+const (
+	// shopUpgrade is an upgrade, always in effect once bought
+	shopUpgrade shopItemKind = iota
+	// shopFlame is a cosmetic thrust flame color, only one of which is equipped at a time
+	shopFlame
+)
+
+// shopItem is an item that can be bought with coins in the shop
+type shopItem struct {
+	// name is the name of the item, shown in the shop and saved in the profile once bought
+	name string
+	// description is what the item does, shown in the shop
+	description string
+	// price is how many coins the item costs
+	price int64
+	// kind is the type of the item
+	kind shopItemKind
+	// red, green and blue are the color of the thrust flame of a flame cosmetic
+	red, green, blue float64
+}
+
+// shopItems are the items sold in the shop, in the order they are listed
+var shopItems = []shopItem{
+	{name: shopMagnetCapacitor, description: "MAGNET RECHARGES 2X FASTER", price: 150, kind: shopUpgrade},
+	{name: shopHeatSink, description: "ENGINE COOLS 1.5X FASTER", price: 200, kind: shopUpgrade},
+	{name: shopShieldBattery, description: "BOOSTS LAST 25% LONGER", price: 300, kind: shopUpgrade},
+	{name: "PLASMA FLAME", description: "BLUE THRUST FLAME", price: 100, kind: shopFlame, red: 0.3, green: 0.6, blue: 1},
+	{name: "TOXIC FLAME", description: "GREEN THRUST FLAME", price: 100, kind: shopFlame, red: 0.4, green: 1, blue: 0.3},
+	{name: "ROYAL FLAME", description: "PURPLE THRUST FLAME", price: 250, kind: shopFlame, red: 0.8, green: 0.3, blue: 1},
+}
+
+// hasPurchased returns whether the player has bought an item from the shop
+func (g *Game) hasPurchased(name string) bool {
+	for _, purchase := range g.profile.Purchases {
+		if purchase == name {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeFactor returns how much an upgrade scales what it improves: the factor once it has been bought, or 1 before
+func (g *Game) upgradeFactor(name string, factor float64) float64 {
+	if g.hasPurchased(name) {
+		return factor
+	}
+	return 1
+}
+
+// flameColor returns the color of a thrust flame particle: the equipped flame cosmetic, or a flickering orange without one
+func (g *Game) flameColor() (float64, float64, float64) {
+	for _, item := range shopItems {
+		if item.kind == shopFlame && item.name == g.profile.Flame && g.hasPurchased(item.name) {
+			return item.red, item.green, item.blue
+		}
+	}
+	return 1, 0.5 + rand.Float64()*0.3, 0.1
+}
+
+// updateShopShortcut opens the shop when its key is pressed on the title screen
+func (g *Game) updateShopShortcut() {
+	if g.input.IsKeyJustPressed(shopKey) {
+		g.mode = ModeShop
+	}
+}
+
+// updateShop moves the selection through the items, buys or equips the selected item and goes back to the title screen when asked
+func (g *Game) updateShop() {
+	_, stickY := g.gamepadStickJustPushed()
+	if g.input.IsKeyJustPressed(shopKey) || g.input.IsKeyJustPressed(ebiten.KeyEscape) || g.isGamepadJustPressed(gamepadButtonB) {
+		g.mode = ModeTitle
+		return
+	}
+
+	if g.input.IsKeyJustPressed(ebiten.KeyUp) || stickY < 0 {
+		g.shopSelected = (g.shopSelected + len(shopItems) - 1) % len(shopItems)
+		g.shopNotice = ""
+	} else if g.input.IsKeyJustPressed(ebiten.KeyDown) || stickY > 0 {
+		g.shopSelected = (g.shopSelected + 1) % len(shopItems)
+		g.shopNotice = ""
+	}
+
+	if g.input.IsKeyJustPressed(ebiten.KeyEnter) || g.isGamepadJustPressed(gamepadButtonA) {
+		g.chooseShopItem(shopItems[g.shopSelected])
+	}
+}
+
+// chooseShopItem buys an item if it hasn't been bought yet and there are enough coins, and equips or unequips a flame
+// that has been bought.  The profile is saved after every change
+func (g *Game) chooseShopItem(item shopItem) {
+	switch {
+	case !g.hasPurchased(item.name):
+		if !g.profile.Wallet.spendCoins(item.price) {
+			g.shopNotice = "NOT ENOUGH COINS"
+			return
+		}
+		g.profile.Purchases = append(g.profile.Purchases, item.name)
+		if item.kind == shopFlame {
+			g.profile.Flame = item.name
+		}
+		g.shopNotice = "BOUGHT " + item.name
+		g.audio.play(soundStar)
+	case item.kind == shopFlame && g.profile.Flame == item.name:
+		g.profile.Flame = ""
+	case item.kind == shopFlame:
+		g.profile.Flame = item.name
+	default:
+		return
+	}
+
+	if err := g.profile.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// shopItemStatus returns how an item's price or ownership is shown in the shop
+func (g *Game) shopItemStatus(item shopItem) string {
+	switch {
+	case !g.hasPurchased(item.name):
+		return fmt.Sprintf("%d COINS", item.price)
+	case item.kind == shopFlame && g.profile.Flame == item.name:
+		return "EQUIPPED"
+	default:
+		return "OWNED"
+	}
+}
+
+// shopTexts returns the lines of the shop, marking the selected item and describing it below the list
+func (g *Game) shopTexts() []string {
+	texts := []string{"", "", fmt.Sprintf("COINS: %d", g.profile.Wallet.Coins), ""}
+	for i, item := range shopItems {
+		text := item.name + ": " + g.shopItemStatus(item)
+		if i == g.shopSelected {
+			text = "> " + text + " <"
+		}
+		texts = append(texts, text)
+	}
+	texts = append(texts, "", shopItems[g.shopSelected].description, g.shopNotice, "")
+	return append(texts, "UP/DOWN TO CHOOSE, ENTER TO BUY OR EQUIP", "ESCAPE TO GO BACK")
+}
+
+// drawShopHint draws how many coins there are to spend and how to open the shop at the bottom of the title screen
+func (g *Game) drawShopHint(r Renderer) {
+	theme := g.theme()
+	hint := fmt.Sprintf("PRESS 'H' KEY FOR SHOP: %d COINS", g.profile.Wallet.Coins)
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*9, theme.Text())
+}
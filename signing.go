@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// installKeyFileName is the name of the file holding the per-install key save files are signed with
+	installKeyFileName = "install.key"
+	// installKeySize is the size in bytes of the per-install key
+	installKeySize = 32
+)
+
+// errProfileTampered is returned when the save file signature doesn't match its contents
+var errProfileTampered = errors.New("save file failed signature check, it has been marked as tampered")
+
+// loadInstallKey reads the per-install signing key, creating a new random key if this is the first run
+func loadInstallKey() ([]byte, error) {
+	dir, err := profileDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, installKeyFileName)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(data)))
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key := make([]byte, installKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// signature calculates the HMAC of the bytes of a profile as saved, with the signature itself empty
+func signature(key, data []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedContents returns the bytes of a save file its signature was calculated over: the saved profile without its
+// whitespace and with the signature emptied.  The bytes that were saved are checked, rather than the profile marshaled
+// again, so fields added to the profile since it was saved don't break its signature
+func signedContents(data []byte, signature string) ([]byte, bool) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, false
+	}
+
+	// The signature is the last field saved, and is hex so is never escaped
+	contents := compact.Bytes()
+	field := []byte(`"signature":"` + signature + `"`)
+	i := bytes.LastIndex(contents, field)
+	if i < 0 {
+		return nil, false
+	}
+	unsigned := append([]byte{}, contents[:i]...)
+	unsigned = append(unsigned, `"signature":""`...)
+	return append(unsigned, contents[i+len(field):]...), true
+}
+
+// sign sets the profile signature to the HMAC of the profile as it is about to be saved
+func (p *Profile) sign() error {
+	key, err := loadInstallKey()
+	if err != nil {
+		return err
+	}
+
+	p.Signature = ""
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	p.Signature = signature(key, data)
+	return nil
+}
+
+// verify checks the profile signature matches the save file it was loaded from.  A profile that fails is kept, so settings
+// aren't lost, but is marked as tampered for good and has its wallet emptied
+func (p *Profile) verify(data []byte) error {
+	key, err := loadInstallKey()
+	if err != nil {
+		return err
+	}
+	contents, ok := signedContents(data, p.Signature)
+	if ok && hmac.Equal([]byte(signature(key, contents)), []byte(p.Signature)) {
+		return nil
+	}
+
+	p.Tampered = true
+	p.Wallet = Wallet{}
+	return errProfileTampered
+}
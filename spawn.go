@@ -0,0 +1,127 @@
+package main
+
+import "github.com/llrowat/spriteutils"
+
+// SpawnKind represents the types of entity that can be spawned into the world
+type SpawnKind int
+
+const (
+	// SpawnSpire represents a spire spawn
+	SpawnSpire SpawnKind = iota
+	// SpawnAsteroid represents an asteroid spawn
+	SpawnAsteroid
+	// SpawnPickup represents a power-up pickup spawn
+	SpawnPickup
+	// SpawnRing represents an asteroid ring spawn, the sprite being the core of the ring
+	SpawnRing
+	// SpawnTwins represents a twin asteroid spawn, the sprite being the upper twin
+	SpawnTwins
+	// SpawnUFO represents a UFO enemy spawn
+	SpawnUFO
+	// SpawnHoming represents a homing asteroid spawn
+	SpawnHoming
+	// SpawnNemesis represents the nemesis asteroid coming back from the last run
+	SpawnNemesis
+	// SpawnGate represents a laser gate spawn, the sprite being the top spire
+	SpawnGate
+)
+
+const (
+	// maxSpires is the most spires allowed in the world at once
+	maxSpires = 20
+	// maxAsteroids is the most asteroids allowed in the world at once
+	maxAsteroids = 100
+)
+
+// Spawn represents a single entity being spawned into the world
+type Spawn struct {
+	// Kind is the type of entity being spawned
+	Kind SpawnKind
+	// Sprite is the sprite being spawned, its position, velocity and image can be changed before it enters the world
+	Sprite *spriteutils.Sprite
+	// PowerUp is the power-up given by a pickup spawn, nil for every other kind
+	PowerUp *PowerUp
+}
+
+// SpawnEvent is passed to every spawn hook when the game decides to spawn an entity
+type SpawnEvent struct {
+	Spawn
+	// Vetoed represents whether a hook has cancelled the spawn
+	Vetoed bool
+	// Added are extra spawns hooks want to happen alongside this one.  They are not passed to hooks themselves
+	Added []Spawn
+}
+
+// SpawnHook is a function that can veto, modify or add to spawns decided by the game
+type SpawnHook func(event *SpawnEvent)
+
+// AddSpawnHook registers a hook that is called for every spawn the game decides on, so mods can change spawning without changing the game
+func (g *Game) AddSpawnHook(hook SpawnHook) {
+	g.spawnHooks = append(g.spawnHooks, hook)
+}
+
+// spawn passes a spawn through all spawn hooks, then adds it and any spawns added by hooks to the world
+func (g *Game) spawn(kind SpawnKind, sprite *spriteutils.Sprite) {
+	g.spawnEvent(&SpawnEvent{Spawn: Spawn{Kind: kind, Sprite: sprite}})
+}
+
+// spawnPickup spawns a pickup for a power-up, passing it through all spawn hooks like any other spawn
+func (g *Game) spawnPickup(powerUp *PowerUp, sprite *spriteutils.Sprite) {
+	g.spawnEvent(&SpawnEvent{Spawn: Spawn{Kind: SpawnPickup, Sprite: sprite, PowerUp: powerUp}})
+}
+
+// spawnEvent passes a spawn event through all spawn hooks, then adds its spawn and any spawns added by hooks to the world.
+// The sprite of a vetoed spawn never enters the world, so it goes straight back to the pool
+func (g *Game) spawnEvent(event *SpawnEvent) {
+	for _, hook := range g.spawnHooks {
+		hook(event)
+	}
+
+	if event.Vetoed {
+		if event.Sprite != nil {
+			g.releaseSprite(event.Sprite)
+		}
+	} else {
+		g.addSpawn(event.Spawn)
+	}
+	for _, added := range event.Added {
+		g.addSpawn(added)
+	}
+}
+
+// addSpawn adds a spawned entity to the world.  addEntity culls the oldest entities of its type if there are too many, and
+// only the newest maxRings rings are kept
+func (g *Game) addSpawn(spawn Spawn) {
+	if spawn.Sprite == nil {
+		return
+	}
+
+	switch spawn.Kind {
+	case SpawnSpire:
+		g.oscillateSpire(g.addEntity(entitySpire, spawn.Sprite))
+	case SpawnAsteroid:
+		g.addEntity(entityAsteroid, spawn.Sprite)
+		g.trackAsteroidPath(spawn.Sprite)
+	case SpawnPickup:
+		if spawn.PowerUp != nil {
+			g.addPickup(spawn.PowerUp, spawn.Sprite)
+		} else {
+			g.releaseSprite(spawn.Sprite)
+		}
+	case SpawnTwins:
+		g.addTwins(spawn.Sprite)
+	case SpawnUFO:
+		g.addUFO(spawn.Sprite)
+	case SpawnHoming:
+		g.addHomingAsteroid(spawn.Sprite)
+	case SpawnNemesis:
+		g.addNemesis(spawn.Sprite)
+	case SpawnGate:
+		g.addGate(spawn.Sprite)
+	case SpawnRing:
+		g.rings = append(g.rings, g.newAsteroidRing(spawn.Sprite))
+		if len(g.rings) > maxRings {
+			g.rings = g.rings[len(g.rings)-maxRings:]
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	// stageLength is the distance covered by each stage before the next one starts
+	stageLength = 10000
+	// stageFadeFrames is how many frames the background takes to fade into the colors of a new stage
+	stageFadeFrames = 120
+	// stageBannerFrames is how many frames the name of a new stage is shown for
+	stageBannerFrames = 180
+)
+
+// Stage represents a themed stretch of a run, with its own colors, obstacles and spawn rates.  Runs move on to the next
+// stage every stageLength, starting over from the first after the last
+type Stage struct {
+	// Name is the name of the stage, shown to the player when it starts
+	Name string
+	// prefix is put in front of the keys of the spire and asteroid images to get the stage's own copies, empty for the originals
+	prefix string
+	// red, green and blue scale the colors of the background and of the stage's copies of the obstacles
+	red, green, blue float64
+	// spireSpawnFactor scales the distance between spires, lower spawning them more often
+	spireSpawnFactor float64
+	// asteroidSpawnFactor scales the distance between asteroids, lower spawning them more often
+	asteroidSpawnFactor float64
+}
+
+// stages are every stage, in the order a run goes through them
+var stages = []Stage{
+	{Name: "ASTEROID BELT", red: 1, green: 1, blue: 1, spireSpawnFactor: 1, asteroidSpawnFactor: 1},
+	{Name: "ICE FIELD", prefix: "ice-", red: 0.55, green: 0.8, blue: 1, spireSpawnFactor: 1.5, asteroidSpawnFactor: 0.75},
+	{Name: "VOLCANIC CANYON", prefix: "lava-", red: 1, green: 0.45, blue: 0.3, spireSpawnFactor: 0.6, asteroidSpawnFactor: 1.4},
+}
+
+// stageObstacleImages are the keys of the images each stage has its own copy of
+var stageObstacleImages = []string{imageTopSpire, imageBottomSpire, imageTopLedgeSpire, imageBottomLedgeSpire, imageAsteroid1, imageAsteroid2, imageAsteroid3, imageAsteroid4}
+
+// loadStageImages creates each stage's copies of the obstacle images, tinted in the stage's colors
+func (a *Assets) loadStageImages() error {
+	for _, stage := range stages {
+		if stage.prefix == "" {
+			continue
+		}
+		for _, key := range stageObstacleImages {
+			op := &ebiten.DrawImageOptions{}
+			op.ColorM.Scale(stage.red, stage.green, stage.blue, 1)
+			if err := a.deriveImage(stage.prefix+key, key, 1, op); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stage returns the stage the run is currently in
+func (g *Game) stage() *Stage {
+	return &stages[g.stageIndex]
+}
+
+// stageImage returns the current stage's copy of an obstacle image
+func (g *Game) stageImage(key string) *ebiten.Image {
+	return g.assets.Image(g.stage().prefix + key)
+}
+
+// updateStage moves the run on to the next stage once it has covered the current one, switching the obstacles spawned
+// from then on to the new stage's
+func (g *Game) updateStage() {
+	index := g.distanceTravelled / stageLength % len(stages)
+	if index == g.stageIndex {
+		return
+	}
+
+	g.previousStageIndex = g.stageIndex
+	g.stageIndex = index
+	g.stageStartFrame = g.frameCount
+	g.initializeSpireFactories()
+	g.initializeAsteroidFactories()
+}
+
+// stageTint returns the colors the background is scaled by, fading from the previous stage's into the current one's
+func (g *Game) stageTint() (float64, float64, float64) {
+	current, previous := g.stage(), &stages[g.previousStageIndex]
+	fade := float64(g.frameCount-g.stageStartFrame) / stageFadeFrames
+	if fade >= 1 {
+		return current.red, current.green, current.blue
+	}
+	return previous.red + (current.red-previous.red)*fade,
+		previous.green + (current.green-previous.green)*fade,
+		previous.blue + (current.blue-previous.blue)*fade
+}
+
+// drawStageBanner announces a new stage for a while after it starts
+func (g *Game) drawStageBanner(r Renderer) {
+	if g.stageStartFrame == 0 || g.frameCount-g.stageStartFrame > stageBannerFrames {
+		return
+	}
+	theme := g.theme()
+	banner := "ENTERING " + g.stage().Name
+	r.DrawText(banner, g.fonts().Normal, (screenWidth-len(banner)*g.ui(fontSize)/2)/2, g.ui(fontSize)*5, theme.Accent())
+}
@@ -0,0 +1,55 @@
+package main
+
+const (
+	// starShowerCleanFrames is how many frames of clean play it takes to trigger a star shower
+	starShowerCleanFrames = 30 * 60
+	// starShowerFrames is how many frames a star shower lasts
+	starShowerFrames = 5 * 60
+	// starShowerSpawnFrames is how many frames apart stars fall during a star shower
+	starShowerSpawnFrames = 12
+	// starShowerFallSpeed is how far shower stars fall each frame
+	starShowerFallSpeed = 3
+)
+
+// isStarShowerActive returns whether a star shower is currently raining stars
+func (g *Game) isStarShowerActive() bool {
+	return g.frameCount < g.starShowerEndFrame
+}
+
+// updateStarShower starts a star shower once the player has played cleanly for long enough, and rains stars while it lasts.
+// Any hit ends the run, so the clean streak runs from the start of the run and restarts after each shower
+func (g *Game) updateStarShower() {
+	if g.isStarShowerActive() {
+		if (g.starShowerEndFrame-g.frameCount)%starShowerSpawnFrames == 0 {
+			g.spawnShowerStar()
+		}
+		return
+	}
+
+	if g.frameCount-g.cleanPlayStartFrame >= starShowerCleanFrames {
+		g.starShowerEndFrame = g.frameCount + starShowerFrames
+		g.cleanPlayStartFrame = g.starShowerEndFrame
+		g.audio.play(soundJingle)
+	}
+}
+
+// spawnShowerStar drops a star from a random point along the top of the screen
+func (g *Game) spawnShowerStar() {
+	width, height := g.assets.Image(imageStar).Size()
+	star := g.generateSprite(starPowerUp.Factory)
+	star.X = screenWidth/4 + g.randIntn("star shower x", screenWidth*3/4-width)
+	star.Y = -height
+	star.YVelocity = starShowerFallSpeed
+	g.addPickup(starPowerUp, star)
+}
+
+// drawStarShowerBanner announces a star shower while it lasts
+func (g *Game) drawStarShowerBanner(r Renderer) {
+	if !g.isStarShowerActive() {
+		return
+	}
+
+	theme := g.theme()
+	banner := "STAR SHOWER!"
+	r.DrawText(banner, g.fonts().Normal, (screenWidth-len(banner)*g.ui(fontSize)/2)/2, g.ui(fontSize)*3, theme.Accent())
+}
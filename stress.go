@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	// stressTestAsteroids is how many asteroids the stress test spawns at once
+	stressTestAsteroids = 300
+	// stressTestFrames is how many frames the stress test times after spawning
+	stressTestFrames = 300
+)
+
+// stressTest times updates and draws after hundreds of asteroids are spawned at once, as a repeatable performance scenario
+type stressTest struct {
+	// framesLeft is how many more frames are timed before the results are logged
+	framesLeft int
+	// updateTime is the total time spent updating while timed
+	updateTime time.Duration
+	// maxUpdateTime is the slowest single update while timed
+	maxUpdateTime time.Duration
+	// drawTime is the total time spent drawing while timed
+	drawTime time.Duration
+	// maxDrawTime is the slowest single draw while timed
+	maxDrawTime time.Duration
+	// maxAsteroids is the most asteroids alive at once while timed, after the asteroid budget culled the rest
+	maxAsteroids int
+	// maxEntities is the most sprites drawn at once while timed
+	maxEntities int
+}
+
+// startStressTest spawns stressTestAsteroids asteroids scattered over the two screens ahead of the ship and starts timing.
+// Positions come from the run's random number generator, so the scenario repeats exactly in runs with the same -seed
+func (g *Game) startStressTest() {
+	freeSprites := len(g.sprites.free)
+	for i := 0; i < stressTestAsteroids; i++ {
+		asteroid := g.generateSprite(g.asteroidFactory)
+		asteroid.X = screenWidth + g.randIntn("stress test x", screenWidth*2)
+		asteroid.Y = g.randIntn("stress test y", screenHeight)
+		g.spawn(SpawnAsteroid, asteroid)
+	}
+
+	log.Printf("stress test: spawned %d asteroids, %d reused from the pool, %d kept by the asteroid budget",
+		stressTestAsteroids, freeSprites-len(g.sprites.free), len(g.entitiesOf(entityAsteroid)))
+	g.stressTest = &stressTest{framesLeft: stressTestFrames}
+}
+
+// timeStressTestUpdate adds the time since an update started to the stress test, logging the results once every frame has been timed
+func (g *Game) timeStressTestUpdate(start time.Time) {
+	s := g.stressTest
+	if s == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	s.updateTime += elapsed
+	if elapsed > s.maxUpdateTime {
+		s.maxUpdateTime = elapsed
+	}
+	if asteroids := len(g.entitiesOf(entityAsteroid)); asteroids > s.maxAsteroids {
+		s.maxAsteroids = asteroids
+	}
+
+	s.framesLeft--
+	if s.framesLeft > 0 {
+		return
+	}
+	log.Printf("stress test: %d frames, update avg %v max %v, draw avg %v max %v, up to %d asteroids and %d entities",
+		stressTestFrames, s.updateTime/stressTestFrames, s.maxUpdateTime, s.drawTime/stressTestFrames, s.maxDrawTime, s.maxAsteroids, s.maxEntities)
+	g.stressTest = nil
+}
+
+// timeStressTestDraw adds the time since a draw started to the stress test.  Drawing is only timed on the CPU, the GPU
+// finishes the work later
+func (g *Game) timeStressTestDraw(start time.Time) {
+	s := g.stressTest
+	if s == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	s.drawTime += elapsed
+	if elapsed > s.maxDrawTime {
+		s.maxDrawTime = elapsed
+	}
+	if g.entityCount > s.maxEntities {
+		s.maxEntities = g.entityCount
+	}
+}
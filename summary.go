@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+)
+
+const (
+	// summaryTickFrames is how many frames apart the tick sound plays while the score counts up
+	summaryTickFrames = 4
+	// summaryRevealFrames is how many frames apart each line of the run summary is revealed
+	summaryRevealFrames = 30
+	// summaryCountUpFrames is how many frames the final score takes to count up
+	summaryCountUpFrames = 90
+)
+
+// summaryLine is a single line of the run summary shown on the game over screen
+type summaryLine struct {
+	// label is the name of the line
+	label string
+	// value is the value shown after the label
+	value string
+}
+
+// runSummary is the breakdown of a finished run, revealed line by line before the score counts up
+type runSummary struct {
+	// lines are the lines revealed before the score, in order
+	lines []summaryLine
+	// revealed is the number of lines revealed so far
+	revealed int
+	// isCountingUp represents whether every line has been revealed and the score has started counting up
+	isCountingUp bool
+	// countUp is the score count-up
+	countUp *Tween
+	// sequence reveals each line in turn, then counts up the score
+	sequence *TweenSequence
+}
+
+// newRunSummary creates the summary of the run that just ended
+func (g *Game) newRunSummary() *runSummary {
+	best := fmt.Sprintf("%d M", g.profile.BestDistance)
+	if g.isNewBest {
+		best += " NEW!"
+	}
+
+	s := &runSummary{
+		lines: []summaryLine{
+			{label: "DISTANCE TRAVELLED", value: fmt.Sprintf("%d M", g.distanceTravelled)},
+			{label: "BEST", value: best},
+			{label: "STARS BANKED", value: fmt.Sprintf("%d", g.starsCollected)},
+			{label: "COINS BANKED", value: fmt.Sprintf("%d", g.coinsBanked())},
+			{label: "SCORE MULTIPLIER", value: fmt.Sprintf("X%.2f", g.scoreMultiplier)},
+			{label: "SHIELD KILLS", value: fmt.Sprintf("+%d", g.bonusScore)},
+		},
+		countUp: NewTween(0, float64(g.score()), summaryCountUpFrames, EaseInQuad),
+	}
+
+	// Seeded runs show their seed so they can be played again
+	if g.seed != 0 {
+		s.lines = append(s.lines, summaryLine{label: "SEED", value: fmt.Sprintf("%d", g.seed)})
+	}
+
+	// The first line is shown straight away, the rest are revealed in turn before the score counts up
+	s.revealed = 1
+	var tweens []*Tween
+	for range s.lines[1:] {
+		tweens = append(tweens, NewWait(0, summaryRevealFrames).Then(func() {
+			s.revealed++
+		}))
+	}
+	tweens = append(tweens, NewWait(0, summaryRevealFrames).Then(func() {
+		s.isCountingUp = true
+	}))
+	s.sequence = NewTweenSequence(append(tweens, s.countUp)...)
+	return s
+}
+
+// isFinished returns whether every line has been revealed and the score has finished counting up
+func (s *runSummary) isFinished() bool {
+	return s.sequence.IsFinished()
+}
+
+// update advances the summary animation, skipping to the end if space is pressed.  It returns whether the count-up should tick this frame
+func (s *runSummary) update(input Input) bool {
+	if input.IsKeyJustPressed(ebiten.KeySpace) {
+		s.sequence.Finish()
+	}
+	if s.isFinished() {
+		return false
+	}
+
+	s.sequence.Update()
+	return s.isCountingUp && int(s.countUp.Progress()*summaryCountUpFrames)%summaryTickFrames == 0
+}
+
+// texts returns the lines of text for the summary at this point in the animation
+func (s *runSummary) texts() []string {
+	var texts []string
+	for _, line := range s.lines[:s.revealed] {
+		texts = append(texts, fmt.Sprintf("%s: %s", line.label, line.value))
+	}
+	if s.isCountingUp {
+		texts = append(texts, fmt.Sprintf("SCORE: %d", int(s.countUp.Value())))
+	}
+	return texts
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"time"
+)
+
+const (
+	// suspendedTPS is the tick rate used while the window is minimized, so almost no CPU or GPU time is used
+	suspendedTPS = 1
+	// suspendGap is the longest gap between updates before the game is considered to have been suspended
+	suspendGap = time.Millisecond * 250
+	// resumeCountdownFrames is how many frames the run waits before continuing after being suspended
+	resumeCountdownFrames = 90
+)
+
+// updateSuspend detects the window being minimized or losing focus, returning whether the simulation should be skipped this frame.
+// Ebiten stops calling Update while the window is unfocused, so a long gap since the last update means the game was suspended
+func (g *Game) updateSuspend() bool {
+	now := time.Now()
+	wasSuspended := !g.lastUpdateTime.IsZero() && now.Sub(g.lastUpdateTime) > suspendGap
+	g.lastUpdateTime = now
+
+	if ebiten.IsWindowMinimized() {
+		if !g.isMinimized {
+			ebiten.SetMaxTPS(suspendedTPS)
+			g.audio.suspend()
+			g.isMinimized = true
+		}
+		return true
+	}
+
+	if g.isMinimized {
+		ebiten.SetMaxTPS(ebiten.DefaultTPS)
+		g.audio.resume()
+		g.isMinimized = false
+		wasSuspended = true
+	}
+
+	// Give the player a moment to get their bearings before the run continues
+	if wasSuspended && g.mode == ModeGame {
+		g.resumeCountdown = resumeCountdownFrames
+	}
+
+	if g.resumeCountdown > 0 {
+		g.resumeCountdown--
+		return g.mode == ModeGame
+	}
+	return false
+}
+
+// drawResumeCountdown draws the countdown shown before a suspended run continues
+func (g *Game) drawResumeCountdown(r Renderer) {
+	if g.resumeCountdown == 0 {
+		return
+	}
+
+	theme := g.theme()
+	countdown := fmt.Sprintf("GET READY %d", g.resumeCountdown/ebiten.DefaultTPS+1)
+	r.DrawText(countdown, g.fonts().Title, (screenWidth-len(countdown)/2*g.ui(titleFontSize))/2, screenHeight/2, theme.Text())
+}
@@ -0,0 +1,34 @@
+package main
+
+// telegraphWindow is how long a hazard is warned about, measured in meters travelled rather than frames so the warning keeps
+// pace with the world
+type telegraphWindow struct {
+	// startDistance is the distance travelled when the warning started
+	startDistance int
+	// endDistance is the distance travelled when the warning ends and the hazard arrives
+	endDistance int
+}
+
+// telegraphDistance returns how far the player travels during a warning of the given meters at the starting speed.  Warnings
+// grow with the current speed, so they always leave the same reaction time however fast the world is moving
+func (g *Game) telegraphDistance(meters int) int {
+	return meters * int(g.speed)
+}
+
+// newTelegraphWindow starts a warning of the given meters at the starting speed, scaled to the current speed
+func (g *Game) newTelegraphWindow(meters int) telegraphWindow {
+	return telegraphWindow{startDistance: g.distanceTravelled, endDistance: g.distanceTravelled + g.telegraphDistance(meters)}
+}
+
+// isOver returns whether a warning has ended at the given distance travelled
+func (w telegraphWindow) isOver(distance int) bool {
+	return distance >= w.endDistance
+}
+
+// remaining returns the fraction of a warning left at the given distance travelled, from 1 when it starts to 0 when it ends
+func (w telegraphWindow) remaining(distance int) float64 {
+	if w.endDistance <= w.startDistance {
+		return 0
+	}
+	return 1 - float64(distance-w.startDistance)/float64(w.endDistance-w.startDistance)
+}
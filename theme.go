@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"golang.org/x/image/font"
+	"image/color"
+	"log"
+)
+
+const (
+	// fontRegular is the key of the Go Regular font set
+	fontRegular = "regular"
+	// fontMono is the key of the Go Mono font set
+	fontMono = "mono"
+	// fontBold is the key of the Go Bold font set
+	fontBold = "bold"
+	// themeKey is the key that cycles through the HUD themes on the title screen
+	themeKey = ebiten.KeyT
+)
+
+// FontSet represents the faces of a single font at each of the text sizes used by the game
+type FontSet struct {
+	// Title is the face used for titles
+	Title font.Face
+	// Normal is the face used for most text
+	Normal font.Face
+	// Small is the face used for secondary text
+	Small font.Face
+}
+
+// Theme represents the look of all HUD and text rendering
+type Theme struct {
+	// Name is the name of the theme shown to the player, and stored in the settings
+	Name string
+	// Font is the key of the font set used for text
+	Font string
+	// TextColor is the color of all text
+	TextColor color.RGBA
+	// AccentColor is the color used to highlight HUD elements, such as meters and split markers
+	AccentColor color.RGBA
+	// Opacity is the opacity of all HUD elements, from 0 to 1
+	Opacity float64
+}
+
+// themes are all the selectable HUD themes, the first being the default
+var themes = []Theme{
+	{
+		Name:        "CLASSIC",
+		Font:        fontRegular,
+		TextColor:   color.RGBA{0xff, 0xff, 0xff, 0xff},
+		AccentColor: color.RGBA{0xff, 0xd7, 0x00, 0xff},
+		Opacity:     1,
+	},
+	{
+		Name:        "TERMINAL",
+		Font:        fontMono,
+		TextColor:   color.RGBA{0x33, 0xff, 0x66, 0xff},
+		AccentColor: color.RGBA{0xcc, 0xff, 0xcc, 0xff},
+		Opacity:     0.9,
+	},
+	{
+		Name:        "NEON",
+		Font:        fontBold,
+		TextColor:   color.RGBA{0xff, 0x66, 0xcc, 0xff},
+		AccentColor: color.RGBA{0x33, 0xcc, 0xff, 0xff},
+		Opacity:     1,
+	},
+	{
+		Name:        "GHOST",
+		Font:        fontRegular,
+		TextColor:   color.RGBA{0xff, 0xff, 0xff, 0xff},
+		AccentColor: color.RGBA{0xaa, 0xcc, 0xff, 0xff},
+		Opacity:     0.5,
+	},
+}
+
+// fonts returns the font set used by the selected HUD theme, at the UI scale
+func (g *Game) fonts() *FontSet {
+	return g.assets.Fonts(g.theme().Font, g.uiScale())
+}
+
+// Text returns the text color with the theme opacity applied
+func (t *Theme) Text() color.Color {
+	return withOpacity(t.TextColor, t.Opacity)
+}
+
+// Accent returns the accent color with the theme opacity applied
+func (t *Theme) Accent() color.Color {
+	return withOpacity(t.AccentColor, t.Opacity)
+}
+
+// Panel returns a faint version of the text color, used behind meters
+func (t *Theme) Panel() color.Color {
+	return withOpacity(t.TextColor, t.Opacity/4)
+}
+
+// withOpacity scales a color by an opacity from 0 to 1
+func withOpacity(c color.RGBA, opacity float64) color.Color {
+	return color.RGBA{
+		R: uint8(float64(c.R) * opacity),
+		G: uint8(float64(c.G) * opacity),
+		B: uint8(float64(c.B) * opacity),
+		A: uint8(float64(c.A) * opacity),
+	}
+}
+
+// theme returns the HUD theme selected in the settings, or the default theme if none is selected
+func (g *Game) theme() *Theme {
+	for i := range themes {
+		if themes[i].Name == g.settings.Theme {
+			return &themes[i]
+		}
+	}
+	return &themes[0]
+}
+
+// updateTheme cycles to the next HUD theme when its key is pressed, remembering the choice in the settings
+func (g *Game) updateTheme() {
+	if !g.input.IsKeyJustPressed(themeKey) {
+		return
+	}
+	g.stepTheme(1)
+	if err := g.settings.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// stepTheme cycles the HUD theme forwards or backwards through the themes
+func (g *Game) stepTheme(step int) {
+	current := g.theme()
+	for i := range themes {
+		if &themes[i] == current {
+			g.settings.Theme = themes[(i+step+len(themes))%len(themes)].Name
+			return
+		}
+	}
+}
+
+// drawThemeHint draws the selected theme and how to change it at the bottom of the title screen
+func (g *Game) drawThemeHint(r Renderer) {
+	theme := g.theme()
+	hint := "PRESS 'T' KEY TO CHANGE THEME: " + theme.Name
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*2, theme.Text())
+}
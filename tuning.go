@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// tuningFileName is the name of the file next to the profile that overrides the compiled in tuning
+	tuningFileName = "tuning.json"
+)
+
+// Tuning is the numbers that balance the game, compiled in but overridable from a file so the game can be balanced without recompiling
+type Tuning struct {
+	// ShipGravity is how much downward velocity a ship gains each frame
+	ShipGravity float64 `json:"shipGravity"`
+	// ShipThrust is how much upward velocity a ship gains each frame while thrusting
+	ShipThrust float64 `json:"shipThrust"`
+	// BoostFactor is the amount speed increases when the player hits a star
+	BoostFactor float64 `json:"boostFactor"`
+	// OutOfBoundsX is the location when sprites are considered out of bounds and will be destroyed.  Sprites heading away
+	// from any other edge are out of bounds the same distance past it
+	OutOfBoundsX int `json:"outOfBoundsX"`
+	// RingSpawnInterval is the distance between asteroid ring spawns
+	RingSpawnInterval int `json:"ringSpawnInterval"`
+	// ChaosEventInterval is the distance between chaos events
+	ChaosEventInterval int `json:"chaosEventInterval"`
+	// Difficulties are the available difficulties with their spawn intervals, easiest first, in the order they are cycled through
+	Difficulties []Difficulty `json:"difficulties"`
+	// DefaultDifficulty is the name of the difficulty played until another is chosen.  The first difficulty is the default if
+	// none has the name
+	DefaultDifficulty string `json:"defaultDifficulty"`
+}
+
+// tuning is the tuning the game is played with, the compiled in defaults unless a tuning file overrides them
+var tuning = defaultTuning()
+
+// isTuned represents whether a tuning file has overridden any of the compiled in tuning.  Runs played with it aren't submitted
+// to the leaderboard
+var isTuned bool
+
+// defaultTuning returns the compiled in tuning
+func defaultTuning() Tuning {
+	return Tuning{
+		ShipGravity:        0.25,
+		ShipThrust:         0.5,
+		BoostFactor:        2,
+		OutOfBoundsX:       -200,
+		RingSpawnInterval:  7000,
+		ChaosEventInterval: 5000,
+		Difficulties: []Difficulty{
+			{
+				Name:                  "EASY",
+				StartSpeed:            1,
+				SpeedIncreaseDistance: 600,
+				SpeedIncreaseGrowth:   2.5,
+				SpireSpawnInterval:    800,
+				AsteroidSpawnInterval: 300,
+				BoostFrames:           boostFrames * 3 / 2,
+			},
+			{
+				Name:                   "NORMAL",
+				StartSpeed:             1,
+				SpeedIncreaseDistance:  500,
+				SpeedIncreaseGrowth:    2,
+				SpireSpawnInterval:     600,
+				AsteroidSpawnInterval:  200,
+				BoostFrames:            boostFrames,
+				OscillatingSpireChance: 1,
+			},
+			{
+				Name:                   "HARD",
+				StartSpeed:             2,
+				SpeedIncreaseDistance:  400,
+				SpeedIncreaseGrowth:    1.75,
+				SpireSpawnInterval:     500,
+				AsteroidSpawnInterval:  150,
+				BoostFrames:            boostFrames * 2 / 3,
+				HeatPerFrame:           1.0 / 150,
+				CoolingPerFrame:        1.0 / 200,
+				OscillatingSpireChance: 3,
+			},
+		},
+		DefaultDifficulty: "NORMAL",
+	}
+}
+
+// loadTuning reads the tuning file next to the profile over the compiled in tuning, if there is one.  Anything the file leaves
+// out keeps its compiled in value
+func loadTuning() error {
+	dir, err := profileDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, tuningFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	loaded := defaultTuning()
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("tuning: %w", err)
+	}
+	if err := loaded.validate(); err != nil {
+		return fmt.Errorf("tuning: %w", err)
+	}
+	tuning = loaded
+	isTuned = true
+	return nil
+}
+
+// defaultDifficulty returns the difficulty played until another is chosen
+func (t *Tuning) defaultDifficulty() *Difficulty {
+	for i := range t.Difficulties {
+		if t.Difficulties[i].Name == t.DefaultDifficulty {
+			return &t.Difficulties[i]
+		}
+	}
+	return &t.Difficulties[0]
+}
+
+// validate returns an error for tuning the game can't be played with
+func (t *Tuning) validate() error {
+	if len(t.Difficulties) == 0 {
+		return errors.New("there must be at least one difficulty")
+	}
+	if t.OutOfBoundsX >= 0 {
+		return errors.New("outOfBoundsX must be left of the screen")
+	}
+	if t.RingSpawnInterval <= 0 || t.ChaosEventInterval <= 0 {
+		return errors.New("spawn intervals must be positive")
+	}
+	for _, difficulty := range t.Difficulties {
+		if difficulty.SpireSpawnInterval <= 0 || difficulty.AsteroidSpawnInterval <= 0 || difficulty.SpeedIncreaseDistance <= 0 {
+			return fmt.Errorf("difficulty %q: spawn intervals must be positive", difficulty.Name)
+		}
+		if difficulty.StartSpeed <= 0 {
+			return fmt.Errorf("difficulty %q: startSpeed must be positive", difficulty.Name)
+		}
+		if difficulty.SpeedIncreaseGrowth <= 1 {
+			return fmt.Errorf("difficulty %q: speedIncreaseGrowth must be more than 1", difficulty.Name)
+		}
+		if difficulty.HeatPerFrame < 0 || (difficulty.HeatPerFrame > 0 && difficulty.CoolingPerFrame <= 0) {
+			return fmt.Errorf("difficulty %q: heat must build and cool at positive rates", difficulty.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+// EaseFunc maps the linear progress of a tween, from 0 to 1, to eased progress
+type EaseFunc func(t float64) float64
+
+// EaseLinear moves at a constant rate
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slow and accelerates
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts fast and decelerates
+func EaseOutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates through the first half and decelerates through the second
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// Tween moves a value from one number to another over a number of frames, following an easing curve
+type Tween struct {
+	// From is the value at the start of the tween
+	From float64
+	// To is the value at the end of the tween
+	To float64
+	// Frames is how many frames the tween lasts
+	Frames int
+	// Ease is the easing curve of the tween, linear if nil
+	Ease EaseFunc
+	// OnComplete is called once when the tween finishes, if set
+	OnComplete func()
+
+	// frame is the number of frames the tween has run for
+	frame int
+	// isCompleted represents whether OnComplete has been called
+	isCompleted bool
+}
+
+// NewTween creates a tween from one value to another over a number of frames
+func NewTween(from, to float64, frames int, ease EaseFunc) *Tween {
+	return &Tween{From: from, To: to, Frames: frames, Ease: ease}
+}
+
+// NewWait creates a tween that holds a value for a number of frames, used to add pauses to sequences
+func NewWait(value float64, frames int) *Tween {
+	return NewTween(value, value, frames, nil)
+}
+
+// Then sets the function called when the tween finishes, returning the tween so it can be chained
+func (t *Tween) Then(onComplete func()) *Tween {
+	t.OnComplete = onComplete
+	return t
+}
+
+// Update advances the tween by a frame
+func (t *Tween) Update() {
+	if t.frame < t.Frames {
+		t.frame++
+	}
+	t.complete()
+}
+
+// Finish skips to the end of the tween
+func (t *Tween) Finish() {
+	t.frame = t.Frames
+	t.complete()
+}
+
+// complete calls OnComplete the first time the tween is found finished
+func (t *Tween) complete() {
+	if t.IsFinished() && !t.isCompleted {
+		t.isCompleted = true
+		if t.OnComplete != nil {
+			t.OnComplete()
+		}
+	}
+}
+
+// IsFinished returns whether the tween has reached its end value
+func (t *Tween) IsFinished() bool {
+	return t.frame >= t.Frames
+}
+
+// Progress returns how far through the tween is, from 0 to 1, before easing
+func (t *Tween) Progress() float64 {
+	if t.Frames <= 0 {
+		return 1
+	}
+	return float64(t.frame) / float64(t.Frames)
+}
+
+// Value returns the current value of the tween
+func (t *Tween) Value() float64 {
+	progress := t.Progress()
+	if t.Ease != nil {
+		progress = t.Ease(progress)
+	}
+	return t.From + (t.To-t.From)*progress
+}
+
+// TweenSequence runs tweens one after another, its value being the value of the tween currently running
+type TweenSequence struct {
+	// tweens are the tweens in the sequence, in order
+	tweens []*Tween
+	// current is the index of the tween currently running
+	current int
+}
+
+// NewTweenSequence creates a sequence of tweens
+func NewTweenSequence(tweens ...*Tween) *TweenSequence {
+	return &TweenSequence{tweens: tweens}
+}
+
+// Update advances the running tween by a frame, moving on to the next tween once it finishes
+func (s *TweenSequence) Update() {
+	if s.IsFinished() {
+		return
+	}
+
+	s.tweens[s.current].Update()
+	if s.tweens[s.current].IsFinished() {
+		s.current++
+	}
+}
+
+// Finish skips to the end of the sequence, finishing every remaining tween in order
+func (s *TweenSequence) Finish() {
+	for ; s.current < len(s.tweens); s.current++ {
+		s.tweens[s.current].Finish()
+	}
+}
+
+// IsFinished returns whether every tween in the sequence has finished
+func (s *TweenSequence) IsFinished() bool {
+	return s.current >= len(s.tweens)
+}
+
+// Current returns the tween currently running, or the last tween once the sequence has finished
+func (s *TweenSequence) Current() *Tween {
+	if len(s.tweens) == 0 {
+		return nil
+	}
+	if s.IsFinished() {
+		return s.tweens[len(s.tweens)-1]
+	}
+	return s.tweens[s.current]
+}
+
+// Value returns the value of the tween currently running
+func (s *TweenSequence) Value() float64 {
+	if current := s.Current(); current != nil {
+		return current.Value()
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// twinSpawnMinInterval is the shortest distance between twin asteroid spawns
+	twinSpawnMinInterval = 2500
+	// twinSpawnMaxInterval is the longest distance between twin asteroid spawns
+	twinSpawnMaxInterval = 5000
+	// twinSeparation is the distance between the centers of twin asteroids
+	twinSeparation = 300
+	// twinMargin is the closest the center of a twin gets to the top or bottom of the screen
+	twinMargin = 100
+	// twinSweepSpeed is how much faster than the world twin asteroids move across the screen
+	twinSweepSpeed = 0.5
+	// tetherGap is the fraction of the tether, around its middle, left open for the ship to fly through
+	tetherGap = 0.45
+	// tetherWidth is the width of a tether
+	tetherWidth = 3
+	// tetherStep is how far apart the points along a tether are checked for touching a ship
+	tetherStep = 6
+	// tetherBonusPoints is the score for flying through the gap in a tether
+	tetherBonusPoints = 250
+)
+
+// tether links a pair of twin asteroids.  Flying through the gap in its middle scores a bonus, and touching the rest of it
+// is harmless but cancels the bonus
+type tether struct {
+	// first and second are the twin asteroids at either end of the tether
+	first, second *entity
+	// isBroken represents whether a ship has touched the tether, cancelling the bonus
+	isBroken bool
+	// aheadShips are the ships the tether is still ahead of
+	aheadShips map[*spriteutils.Sprite]bool
+}
+
+// ends returns the centers of the twin asteroids at either end of the tether
+func (t *tether) ends() (float64, float64, float64, float64) {
+	x1, y1 := spriteCenter(t.first.Sprite)
+	x2, y2 := spriteCenter(t.second.Sprite)
+	return x1, y1, x2, y2
+}
+
+// point returns the point a fraction of the way along the tether, from 0 at the first twin to 1 at the second
+func (t *tether) point(fraction float64) (float64, float64) {
+	x1, y1, x2, y2 := t.ends()
+	return x1 + (x2-x1)*fraction, y1 + (y2-y1)*fraction
+}
+
+// isInGap returns whether a fraction of the way along the tether is in the gap in its middle
+func isInGap(fraction float64) bool {
+	return math.Abs(fraction-0.5) <= tetherGap/2
+}
+
+// generateTwin creates the upper asteroid of a new pair of twins just off the right of the screen
+func (g *Game) generateTwin() *spriteutils.Sprite {
+	twin := g.generateSprite(g.asteroidFactory)
+	_, height := twin.Image.Size()
+	twin.Y = twinMargin + g.randIntn("twin y", screenHeight-twinMargin*2-twinSeparation) - height/2
+	twin.XVelocity = -(g.speed + twinSweepSpeed)
+	return twin
+}
+
+// addTwins adds an asteroid and its twin below it to the world, linked by a tether
+func (g *Game) addTwins(first *spriteutils.Sprite) {
+	second := g.sprites.get()
+	*second = *first
+	second.Y += twinSeparation
+
+	t := &tether{aheadShips: map[*spriteutils.Sprite]bool{}}
+	t.first = g.addEntity(entityAsteroid, first)
+	t.second = g.addEntity(entityAsteroid, second)
+	t.first.tether, t.second.tether = t, t
+	for _, ship := range g.ships() {
+		t.aheadShips[ship] = true
+	}
+	g.tethers = append(g.tethers, t)
+}
+
+// updateTethers drops the tethers whose twins aren't both in the world anymore
+func (g *Game) updateTethers() {
+	if len(g.tethers) == 0 {
+		return
+	}
+
+	linked := map[*entity]bool{}
+	for _, e := range g.entities {
+		if e.tether != nil {
+			linked[e] = true
+		}
+	}
+
+	temp := g.tethers[:0]
+	for _, t := range g.tethers {
+		if linked[t.first] && linked[t.second] && !t.isBroken {
+			temp = append(temp, t)
+		}
+	}
+	for i := len(temp); i < len(g.tethers); i++ {
+		g.tethers[i] = nil
+	}
+	g.tethers = temp
+}
+
+// checkTetherPasses breaks any tether a ship touches, and scores a bonus for a ship flying through the gap in a tether
+func (g *Game) checkTetherPasses() {
+	for _, t := range g.tethers {
+		for _, ship := range g.ships() {
+			if g.isTouchingTether(ship, t) {
+				t.isBroken = true
+				continue
+			}
+
+			// The gap is passed once the middle of the tether is behind the middle of the ship
+			shipX, shipY := spriteCenter(ship)
+			gapX, _ := t.point(0.5)
+			if !t.aheadShips[ship] || gapX > shipX {
+				continue
+			}
+			delete(t.aheadShips, ship)
+
+			_, y1, _, y2 := t.ends()
+			if isInGap((shipY - y1) / (y2 - y1)) {
+				g.bonusScore += tetherBonusPoints
+				g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d THREADED", tetherBonusPoints), x: shipX, y: shipY})
+				g.audio.play(soundStar)
+			}
+		}
+	}
+}
+
+// isTouchingTether returns whether any point along a tether outside its gap is inside a ship
+func (g *Game) isTouchingTether(ship *spriteutils.Sprite, t *tether) bool {
+	x1, y1, x2, y2 := t.ends()
+	steps := int(math.Hypot(x2-x1, y2-y1) / tetherStep)
+	width, height := ship.Image.Size()
+	for i := 0; i <= steps; i++ {
+		fraction := float64(i) / float64(steps)
+		if isInGap(fraction) {
+			continue
+		}
+		x, y := t.point(fraction)
+		if x >= float64(ship.X) && x <= float64(ship.X+width) && y >= float64(ship.Y) && y <= float64(ship.Y+height) {
+			return true
+		}
+	}
+	return false
+}
+
+// drawTethers draws the tether between each pair of twins, with the gap in its middle left open
+func (g *Game) drawTethers(r Renderer) {
+	// The tethers hum with energy, pulsing brighter and dimmer
+	alpha := 0.55 + 0.25*math.Sin(float64(g.frameCount)*0.2)
+	for _, t := range g.tethers {
+		g.drawTetherSegment(r, t, 0, 0.5-tetherGap/2, alpha)
+		g.drawTetherSegment(r, t, 0.5+tetherGap/2, 1, alpha)
+	}
+}
+
+// drawTetherSegment draws the part of a tether between two fractions of the way along it
+func (g *Game) drawTetherSegment(r Renderer, t *tether, from, to, alpha float64) {
+	x1, y1 := t.point(from)
+	x2, y2 := t.point(to)
+
+	op := &Transform{}
+	op.Translate(0, -0.5)
+	op.Scale(math.Hypot(x2-x1, y2-y1), tetherWidth)
+	op.Rotate(math.Atan2(y2-y1, x2-x1))
+	op.Translate(x1, y1)
+	op.ScaleColor(0.7, 0.4, 1, alpha)
+	r.DrawImage(g.assets.Image(imageBeam), op)
+}
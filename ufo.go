@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/llrowat/spriteutils"
+	"math"
+)
+
+const (
+	// imageUFO is the key of the UFO enemy
+	imageUFO = "ufo"
+	// ufoWidth is the width of a UFO
+	ufoWidth = 70
+	// ufoHeight is the height of a UFO
+	ufoHeight = 34
+	// ufoFirstDistance is the distance of the first UFO
+	ufoFirstDistance = 1500
+	// ufoSpawnInterval is the distance between UFOs
+	ufoSpawnInterval = 3500
+	// ufoSpeed is how much slower than the world a UFO moves across the screen, so it lingers in front of the ship
+	ufoSpeed = 1.5
+	// ufoMinSpeed is the slowest a UFO moves across the screen, so it still arrives while the world is slow
+	ufoMinSpeed = 2
+	// ufoTrackSpeed is the fastest a UFO moves up or down to follow the ship
+	ufoTrackSpeed = 1.5
+	// ufoMinFireFrames is the fewest frames between two shots from a UFO
+	ufoMinFireFrames = 90
+	// ufoMaxFireFrames is the most frames between two shots from a UFO
+	ufoMaxFireFrames = 180
+	// ufoPoints is the score for destroying a UFO
+	ufoPoints = 150
+	// maxUFOs is the most UFOs allowed in the world at once
+	maxUFOs = 3
+)
+
+// loadUFOImage creates the UFO, a flattened green copy of the shield for its saucer with a smaller one on top for its dome
+func (a *Assets) loadUFOImage() error {
+	img, err := ebiten.NewImage(ufoWidth, ufoHeight, ebiten.FilterDefault)
+	if err != nil {
+		return err
+	}
+	width, height := a.images[imageShield].Size()
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(ufoWidth/float64(width), ufoHeight/2/float64(height))
+	op.GeoM.Translate(0, ufoHeight/2)
+	op.ColorM.Scale(0.4, 1, 0.5, 1)
+	img.DrawImage(a.images[imageShield], op)
+
+	op = &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(ufoWidth/2/float64(width), ufoHeight*0.6/float64(height))
+	op.GeoM.Translate(ufoWidth/4, 0)
+	op.ColorM.Scale(0.6, 1, 1, 1)
+	img.DrawImage(a.images[imageShield], op)
+
+	a.images[imageUFO] = img
+	return nil
+}
+
+// initializeUFOFactory sets the options of the UFO sprite factory
+func (g *Game) initializeUFOFactory() {
+	g.ufoFactory = &spriteutils.SpriteFactory{
+		Images: []*ebiten.Image{g.assets.Image(imageUFO)},
+		MaxX:   screenWidth + 50,
+		MinX:   screenWidth + 50,
+		MaxY:   screenHeight - 150,
+		MinY:   150,
+	}
+}
+
+// addUFO adds a UFO to the world, its first shot a random while away
+func (g *Game) addUFO(sprite *spriteutils.Sprite) {
+	ufo := g.addEntity(entityUFO, sprite)
+	ufo.fireFrame = g.frameCount + g.ufoFireFrames()
+}
+
+// ufoFireFrames returns a random number of frames until a UFO's next shot
+func (g *Game) ufoFireFrames() int64 {
+	return int64(ufoMinFireFrames + g.randIntn("ufo fire frames", ufoMaxFireFrames-ufoMinFireFrames))
+}
+
+// updateUFOs moves every UFO across the screen toward the nearest ship's height, firing a bolt at it now and then
+func (g *Game) updateUFOs() {
+	for _, e := range g.entities {
+		if e.kind != entityUFO {
+			continue
+		}
+
+		x, y := spriteCenter(e.Sprite)
+		target := g.nearestShip(x, y)
+		shipX, shipY := spriteCenter(target)
+		e.XVelocity = -math.Max(g.speed-ufoSpeed, ufoMinSpeed)
+		e.YVelocity = math.Max(-ufoTrackSpeed, math.Min(ufoTrackSpeed, shipY-y))
+
+		// UFOs only fire while the ship is ahead of them, not once they have flown past it
+		if g.frameCount >= e.fireFrame {
+			if shipX < x {
+				g.fireBolt(x, y, math.Atan2(shipY-y, shipX-x))
+			}
+			e.fireFrame = g.frameCount + g.ufoFireFrames()
+		}
+	}
+}
+
+// nearestShip returns the ship closest to a position
+func (g *Game) nearestShip(x, y float64) *spriteutils.Sprite {
+	nearest := g.ship
+	nearestDistance := math.Inf(1)
+	for _, ship := range g.ships() {
+		shipX, shipY := spriteCenter(ship)
+		if distance := math.Hypot(shipX-x, shipY-y); distance < nearestDistance {
+			nearest, nearestDistance = ship, distance
+		}
+	}
+	return nearest
+}
+
+// checkUFOCollisions is the UFO collision system.  Shields and laser projectiles destroy any UFO they hit, along with
+// the projectile, and a ship hitting a UFO ends the run
+func (g *Game) checkUFOCollisions() {
+	projectiles := g.entitiesOf(entityProjectile)
+	spent := map[*entity]bool{}
+	var destroyed []*entity
+	g.filterEntities(func(e *entity) bool {
+		if e.collider != colliderUFO {
+			return true
+		}
+
+		isHit := g.isShieldColliding(e.Sprite)
+		for _, projectile := range projectiles {
+			if !spent[projectile] && projectile.IsColliding(e.Sprite) {
+				spent[projectile] = true
+				isHit = true
+			}
+		}
+		if isHit {
+			destroyed = append(destroyed, e)
+			return false
+		}
+
+		if g.isShipColliding(e.Sprite) {
+			g.mode = ModeGameOver
+		}
+		return true
+	})
+
+	g.filterEntities(func(e *entity) bool {
+		if !spent[e] {
+			return true
+		}
+		g.releaseEntity(e)
+		return false
+	})
+	for _, e := range destroyed {
+		x, y := spriteCenter(e.Sprite)
+		g.publish(&Event{Kind: EventUFODestroyed, X: x, Y: y})
+		g.releaseEntity(e)
+	}
+}
+
+// onUFODestroyed scores a destroyed UFO
+func (g *Game) onUFODestroyed(x, y float64) {
+	g.bonusScore += ufoPoints
+	g.floatingTexts = append(g.floatingTexts, &floatingText{text: fmt.Sprintf("+%d", ufoPoints), x: x, y: y})
+}
@@ -0,0 +1,47 @@
+package main
+
+// defaultUIScale is the UI scale used before one is chosen, as a percentage
+const defaultUIScale = 100
+
+// uiScales are the selectable sizes of text and the HUD as percentages, smallest first
+var uiScales = []int{75, 100, 125, 150}
+
+// uiScale returns the UI scale chosen in the settings as a percentage, or the default if the settings hold one that isn't selectable
+func (g *Game) uiScale() int {
+	if g.settings == nil {
+		return defaultUIScale
+	}
+	for _, scale := range uiScales {
+		if scale == g.settings.UIScale {
+			return scale
+		}
+	}
+	return defaultUIScale
+}
+
+// ui scales a length on the HUD by the UI scale.  The game world is drawn at the same size whatever the UI scale
+func (g *Game) ui(length int) int {
+	return length * g.uiScale() / 100
+}
+
+// uiFloat scales a length on the HUD by the UI scale, for drawing shapes
+func (g *Game) uiFloat(length float64) float64 {
+	return length * float64(g.uiScale()) / 100
+}
+
+// stepUIScale returns the UI scale a step up or down from a selectable scale, kept within the selectable scales
+func stepUIScale(scale, step int) int {
+	index := 0
+	for i, selectable := range uiScales {
+		if selectable == scale {
+			index = i
+		}
+	}
+	index += step
+	if index < 0 {
+		index = 0
+	} else if index >= len(uiScales) {
+		index = len(uiScales) - 1
+	}
+	return uiScales[index]
+}
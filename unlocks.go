@@ -0,0 +1,69 @@
+package main
+
+import "fmt"
+
+// milestone represents a measure of the player's progress, kept in the profile, that unlocks game variants
+type milestone int
+
+const (
+	// milestoneBestDistance is the furthest distance travelled in a single run
+	milestoneBestDistance milestone = iota
+	// milestoneRunsPlayed is how many runs have been finished
+	milestoneRunsPlayed
+	// milestoneLifetimeStars is how many stars have been collected across every run
+	milestoneLifetimeStars
+)
+
+// milestoneConditions are how each milestone is described to the player, formatted with the target
+var milestoneConditions = map[milestone]string{
+	milestoneBestDistance:  "REACH %d M IN ONE RUN",
+	milestoneRunsPlayed:    "PLAY %d RUNS",
+	milestoneLifetimeStars: "COLLECT %d STARS",
+}
+
+// variantUnlock is the milestone a variant is locked behind
+type variantUnlock struct {
+	// milestone is the measure of progress that unlocks the variant
+	milestone milestone
+	// target is the value the milestone must reach
+	target int
+}
+
+// variantUnlocks are the milestones each locked variant needs.  Variants left out are always unlocked
+var variantUnlocks = map[Variant]variantUnlock{
+	VariantDual:    {milestone: milestoneRunsPlayed, target: 10},
+	VariantPursuit: {milestone: milestoneLifetimeStars, target: 100},
+	VariantMirror:  {milestone: milestoneBestDistance, target: 10000},
+}
+
+// milestoneProgress returns how far the player has got toward a milestone
+func (g *Game) milestoneProgress(m milestone) int {
+	switch m {
+	case milestoneBestDistance:
+		return g.profile.BestDistance
+	case milestoneRunsPlayed:
+		return g.profile.RunsPlayed
+	case milestoneLifetimeStars:
+		return g.profile.LifetimeStars
+	}
+	return 0
+}
+
+// isVariantUnlocked returns whether a variant can be played
+func (g *Game) isVariantUnlocked(variant Variant) bool {
+	unlock, ok := variantUnlocks[variant]
+	return !ok || g.milestoneProgress(unlock.milestone) >= unlock.target
+}
+
+// unlockCondition returns what the player still has to do to unlock a variant, with their progress so far
+func (g *Game) unlockCondition(variant Variant) string {
+	unlock := variantUnlocks[variant]
+	condition := fmt.Sprintf(milestoneConditions[unlock.milestone], unlock.target)
+	return fmt.Sprintf("%s (%d/%d)", condition, g.milestoneProgress(unlock.milestone), unlock.target)
+}
+
+// recordProgress adds a finished run to the milestones kept in the profile
+func (g *Game) recordProgress() {
+	g.profile.RunsPlayed++
+	g.profile.LifetimeStars += g.starsCollected
+}
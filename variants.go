@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Variant represents the different ways a run can be played
+type Variant int
+
+const (
+	// VariantClassic represents the normal single ship game
+	VariantClassic Variant = iota
+	// VariantDual represents the game where two ships are controlled at once
+	VariantDual
+	// VariantPursuit represents the game where a wall chases the ship from the left and falling behind ends the run
+	VariantPursuit
+	// VariantMirror represents the mirror universe, with inverted colors and reversed gravity
+	VariantMirror
+)
+
+const (
+	// variantKey is the key that cycles through the game variants on the title screen
+	variantKey = ebiten.KeyV
+)
+
+// variantNames are the names of each variant shown to the player
+var variantNames = map[Variant]string{
+	VariantClassic: "CLASSIC",
+	VariantDual:    "DUAL SHIP",
+	VariantPursuit: "PURSUIT",
+	VariantMirror:  "MIRROR UNIVERSE",
+}
+
+// updateVariant cycles to the next game variant when its key is pressed on the title screen.  Locked variants can be
+// selected to see what unlocks them, but not launched
+func (g *Game) updateVariant() {
+	if !g.input.IsKeyJustPressed(variantKey) {
+		return
+	}
+
+	g.variant = (g.variant + 1) % Variant(len(variantNames))
+	g.resetGame()
+}
+
+// drawVariantHint draws the selected variant and how to change it at the bottom of the title screen.  A locked variant is
+// greyed out, with what unlocks it
+func (g *Game) drawVariantHint(r Renderer) {
+	theme := g.theme()
+	hint, clr := "PRESS 'V' KEY TO CHANGE MODE: "+variantNames[g.variant], theme.Text()
+	if !g.isVariantUnlocked(g.variant) {
+		hint, clr = hint+" - LOCKED: "+g.unlockCondition(g.variant), theme.Panel()
+	}
+	r.DrawText(hint, g.fonts().Small, (screenWidth-len(hint)*g.ui(smallFontSize)/2)/2, screenHeight-g.ui(fontSize)*3, clr)
+}
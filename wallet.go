@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// maxWalletStars is the most stars the wallet can hold, deposits beyond it are lost
+	maxWalletStars = 1000000
+	// walletInterestRate is the fraction of the balance added as interest for each day played
+	walletInterestRate = 0.02
+	// walletChecksumSalt is mixed into the wallet checksum so it can't be recalculated by hand
+	walletChecksumSalt = "galactic-asteroid-belt-wallet"
+	// walletVersion is the version of what the wallet checksum covers.  Version 0 wallets were saved before coins existed
+	walletVersion = 1
+	// dayFormat is the format days are stored in
+	dayFormat = "2006-01-02"
+)
+
+// Wallet represents the stars and coins the player has banked between runs
+type Wallet struct {
+	// Stars is the number of unspent stars
+	Stars int64 `json:"stars"`
+	// Coins is the number of unspent coins, spent in the shop
+	Coins int64 `json:"coins"`
+	// LastInterestDay is the last day interest was paid, in dayFormat
+	LastInterestDay string `json:"lastInterestDay"`
+	// Version is the version of what the checksum covers, see walletVersion
+	Version int `json:"version"`
+	// Checksum is used to detect the wallet being edited outside the game
+	Checksum string `json:"checksum"`
+}
+
+// checksum calculates the checksum of the wallet contents covered by the wallet's version
+func (w *Wallet) checksum() string {
+	var contents string
+	switch w.Version {
+	case 0:
+		contents = fmt.Sprintf("%d|%s|%s", w.Stars, w.LastInterestDay, walletChecksumSalt)
+	default:
+		contents = fmt.Sprintf("%d|%d|%d|%s|%s", w.Version, w.Stars, w.Coins, w.LastInterestDay, walletChecksumSalt)
+	}
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
+// updateChecksum moves the wallet to the current version and recalculates its checksum after its contents change
+func (w *Wallet) updateChecksum() {
+	w.Version = walletVersion
+	w.Checksum = w.checksum()
+}
+
+// isValid returns whether the wallet contents are in range and match the checksum
+func (w *Wallet) isValid() bool {
+	if w.Stars == 0 && w.Coins == 0 && w.LastInterestDay == "" && w.Checksum == "" {
+		// A wallet that has never been used
+		return true
+	}
+	if w.Version == 0 && w.Coins != 0 {
+		// Coins aren't covered by the checksum of a wallet saved before they existed
+		return false
+	}
+	return w.Stars >= 0 && w.Stars <= maxWalletStars && w.Coins >= 0 && w.Coins <= maxWalletCoins && w.Checksum == w.checksum()
+}
+
+// deposit adds stars to the wallet, capped at maxWalletStars
+func (w *Wallet) deposit(stars int64) {
+	if stars <= 0 {
+		return
+	}
+	if stars > maxWalletStars-w.Stars {
+		w.Stars = maxWalletStars
+	} else {
+		w.Stars += stars
+	}
+	w.updateChecksum()
+}
+
+// applyDailyInterest pays interest on the balance the first time the game is played on a new day, returning the interest paid
+func (w *Wallet) applyDailyInterest(now time.Time) int64 {
+	today := now.Format(dayFormat)
+	if w.LastInterestDay == today {
+		return 0
+	}
+
+	var interest int64
+	// No interest is paid on the first day the wallet is used
+	if w.LastInterestDay != "" && w.Stars > 0 {
+		interest = int64(float64(w.Stars) * walletInterestRate)
+		if interest < 1 {
+			interest = 1
+		}
+	}
+
+	w.LastInterestDay = today
+	w.deposit(interest)
+	w.updateChecksum()
+	return interest
+}
@@ -0,0 +1,229 @@
+package main
+
+import "github.com/llrowat/spriteutils"
+
+// collider represents how an entity takes part in collisions
+type collider int
+
+const (
+	// colliderNone represents an entity nothing collides with
+	colliderNone collider = iota
+	// colliderTerrain represents an entity that ends the run when a ship hits it and destroys any asteroid that hits it
+	colliderTerrain
+	// colliderWater represents an entity that slows down a ship that hits it instead of ending the run, and destroys any asteroid that hits it
+	colliderWater
+	// colliderAsteroid represents an entity that ends the run when a ship hits it, and is destroyed by terrain, shields and lasers
+	colliderAsteroid
+	// colliderPickup represents an entity collected when a ship hits it, and destroyed by nearby explosions
+	colliderPickup
+	// colliderProjectile represents an entity that destroys the asteroids it hits
+	colliderProjectile
+	// colliderBoss represents an entity that ends the run when a ship hits it, and is worn down by lasers
+	colliderBoss
+	// colliderBolt represents an entity that ends the run when a ship hits it, and is blocked by shields
+	colliderBolt
+	// colliderUFO represents an entity that ends the run when a ship hits it, and is destroyed by shields and lasers
+	colliderUFO
+)
+
+// cullRule represents when an entity has left the world and is removed
+type cullRule int
+
+const (
+	// cullNever represents an entity that is never culled, such as the ground
+	cullNever cullRule = iota
+	// cullPassedLeft represents an entity culled once it is out of bounds to the left
+	cullPassedLeft
+	// cullPassedLeftOrFallen represents an entity culled once it is out of bounds to the left or has fallen off the bottom of the screen
+	cullPassedLeftOrFallen
+	// cullLeavingWorld represents an entity culled once it is out of bounds and heading further away, in any direction
+	cullLeavingWorld
+	// cullPassedRight represents an entity culled once it has left the right of the screen
+	cullPassedRight
+)
+
+// entityArchetype is the components every entity of a type is created with
+type entityArchetype struct {
+	// scrollsWithWorld represents whether the entity moves left with the world, its horizontal velocity following the world speed
+	scrollsWithWorld bool
+	// collider is how the entity takes part in collisions
+	collider collider
+	// cull is when the entity has left the world and is removed
+	cull cullRule
+	// max is the most entities of the type in the world at once, the oldest culled first to make room, or 0 for no limit
+	max int
+}
+
+// entityArchetypes are the components of each type of entity in the world
+var entityArchetypes = map[entityType]entityArchetype{
+	entityGround:     {scrollsWithWorld: true, collider: colliderTerrain, cull: cullNever},
+	entityWater:      {scrollsWithWorld: true, collider: colliderWater, cull: cullNever},
+	entitySpire:      {scrollsWithWorld: true, collider: colliderTerrain, cull: cullPassedLeft, max: maxSpires},
+	entityAsteroid:   {collider: colliderAsteroid, cull: cullLeavingWorld, max: maxAsteroids},
+	entityPickup:     {scrollsWithWorld: true, collider: colliderPickup, cull: cullPassedLeftOrFallen, max: maxPickups},
+	entityProjectile: {collider: colliderProjectile, cull: cullPassedRight, max: maxProjectiles},
+	entityBoss:       {collider: colliderBoss, cull: cullNever},
+	entityBolt:       {collider: colliderBolt, cull: cullLeavingWorld, max: maxBolts},
+	entityUFO:        {collider: colliderUFO, cull: cullLeavingWorld, max: maxUFOs},
+}
+
+// entity is an object in the world, a sprite moved, collided and culled by the entity systems according to its components
+type entity struct {
+	*spriteutils.Sprite
+	entityArchetype
+	// kind is the type of the entity
+	kind entityType
+	// lifetime is how many frames are left before the entity is removed, or 0 if it lasts until it is culled
+	lifetime int
+	// wrapWidth is how far the entity jumps ahead once it has scrolled off the left of the screen, or 0 if it doesn't wrap
+	wrapWidth int
+	// powerUp is the power-up given when the entity is collected, for pickups
+	powerUp *PowerUp
+	// tether is the tether linking the entity to its twin, for twin asteroids
+	tether *tether
+	// fireFrame is the frame the entity next fires on, for UFOs
+	fireFrame int64
+	// isHoming represents whether the entity steers toward the ship, for asteroids
+	isHoming bool
+	// baseY is the height the entity spawned at, for oscillating spires
+	baseY int
+	// oscillationAmplitude is how far the entity moves from where it spawned, negative for upward, for oscillating spires
+	oscillationAmplitude float64
+	// oscillationPeriod is how many frames the entity takes to move away and back, or 0 if it doesn't oscillate
+	oscillationPeriod int64
+	// oscillationStart is the frame the entity's current cycle started on, for oscillating spires
+	oscillationStart int64
+}
+
+// addEntity adds a sprite to the world as an entity of a type, culling the oldest entities of the type if there are too many
+func (g *Game) addEntity(kind entityType, sprite *spriteutils.Sprite) *entity {
+	e := &entity{Sprite: sprite, entityArchetype: entityArchetypes[kind], kind: kind}
+	if e.max > 0 {
+		existing := g.entitiesOf(kind)
+		if excess := len(existing) - e.max + 1; excess > 0 {
+			culled := map[*entity]bool{}
+			for _, old := range existing[:excess] {
+				culled[old] = true
+				g.releaseEntity(old)
+			}
+			g.filterEntities(func(other *entity) bool {
+				return !culled[other]
+			})
+		}
+	}
+	g.entities = append(g.entities, e)
+	return e
+}
+
+// filterEntities keeps only the entities keep returns true for, without releasing the sprites of the others.  The
+// entities are filtered in place, so keep must not look through or change the entities itself
+func (g *Game) filterEntities(keep func(e *entity) bool) {
+	temp := g.entities[:0]
+	for _, e := range g.entities {
+		if keep(e) {
+			temp = append(temp, e)
+		}
+	}
+	for i := len(temp); i < len(g.entities); i++ {
+		g.entities[i] = nil
+	}
+	g.entities = temp
+}
+
+// releaseEntity releases the sprite of an entity removed from the world back to the pool
+func (g *Game) releaseEntity(e *entity) {
+	switch e.kind {
+	case entityAsteroid:
+		g.releaseAsteroid(e.Sprite)
+	case entityGround, entityWater:
+		// Ground tiles wrap around rather than being culled, so they were never taken from the pool
+	default:
+		g.releaseSprite(e.Sprite)
+	}
+}
+
+// entitiesOf returns every entity of a type, in the order they were added
+func (g *Game) entitiesOf(kind entityType) []*entity {
+	var entities []*entity
+	for _, e := range g.entities {
+		if e.kind == kind {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+// entitySprites returns the sprites of every entity of a type, in the order they were added
+func (g *Game) entitySprites(kind entityType) []*spriteutils.Sprite {
+	var sprites []*spriteutils.Sprite
+	for _, e := range g.entities {
+		if e.kind == kind {
+			sprites = append(sprites, e.Sprite)
+		}
+	}
+	return sprites
+}
+
+// updateEntities is the movement, lifetime and cull system.  It moves every entity, wraps those that wrap around, and
+// removes those that have outlived their lifetime or left the world
+func (g *Game) updateEntities() {
+	g.filterEntities(func(e *entity) bool {
+		if e.scrollsWithWorld {
+			e.XVelocity = -g.speed
+		}
+		// Frozen entities stay still, moving only with the world
+		if g.isFrozen(e.Sprite) {
+			e.XVelocity = -g.speed
+			e.YVelocity = 0
+		}
+		e.Update()
+
+		if e.wrapWidth > 0 {
+			if width, _ := e.Image.Size(); e.X <= -width {
+				e.X += e.wrapWidth
+			}
+		}
+
+		if e.lifetime > 0 {
+			e.lifetime--
+			if e.lifetime == 0 {
+				g.releaseEntity(e)
+				return false
+			}
+		}
+		if g.isCulled(e) {
+			g.releaseEntity(e)
+			return false
+		}
+		return true
+	})
+}
+
+// isCulled returns whether an entity has left the world according to its cull rule
+func (g *Game) isCulled(e *entity) bool {
+	switch e.cull {
+	case cullPassedLeft:
+		return e.X <= tuning.OutOfBoundsX
+	case cullPassedLeftOrFallen:
+		return e.X <= tuning.OutOfBoundsX || e.Y >= screenHeight
+	case cullLeavingWorld:
+		return isLeavingWorld(e.Sprite)
+	case cullPassedRight:
+		return e.X >= screenWidth
+	default:
+		return false
+	}
+}
+
+// checkTerrainCollisions is the terrain collision system.  A ship hitting any terrain ends the run, and any asteroid hitting it is destroyed
+func (g *Game) checkTerrainCollisions() {
+	for _, e := range g.entities {
+		if e.collider != colliderTerrain {
+			continue
+		}
+		if g.isShipColliding(e.Sprite) {
+			g.mode = ModeGameOver
+		}
+		g.destroyAsteroidsColliding(e.Sprite, destroyedByTerrain)
+	}
+}